@@ -1,6 +1,93 @@
 package config
 
+// Config berisi semua pengaturan aplikasi. nama key (tag mapstructure) sama dengan nama
+// variabel environment, file .env/YAML dan flag (APP_PORT -> --app-port).
+// tag default dipakai kalau key tidak diisi di mana pun, tag secret menyembunyikan nilai di `config show`.
 type Config struct {
-	Port   string `mapstructure:"APP_PORT"`
-	DBConn string `mapstructure:"SUPABASE_DB_CONN"`
+	Port               string  `mapstructure:"APP_PORT" default:"8080"`
+	DBConn             string  `mapstructure:"SUPABASE_DB_CONN" secret:"url"`
+	ReceiptTemplateDir string  `mapstructure:"RECEIPT_TEMPLATE_DIR"`
+	SalesTaxRate       float64 `mapstructure:"SALES_TAX_RATE"`
+
+	// connection pool database.InitDB
+	DBMaxOpenConns           int `mapstructure:"DB_MAX_OPEN_CONNS" default:"25"`
+	DBMaxIdleConns           int `mapstructure:"DB_MAX_IDLE_CONNS" default:"25"`
+	DBConnMaxLifetimeMinutes int `mapstructure:"DB_CONN_MAX_LIFETIME_MINUTES" default:"60"`
+	DBConnMaxIdleTimeMinutes int `mapstructure:"DB_CONN_MAX_IDLE_TIME_MINUTES" default:"0"`
+
+	LoyaltyEarnRate         float64 `mapstructure:"LOYALTY_EARN_RATE"`
+	LoyaltyRedeemValue      float64 `mapstructure:"LOYALTY_REDEEM_VALUE"`
+	LoyaltyPointsExpiryDays int     `mapstructure:"LOYALTY_POINTS_EXPIRY_DAYS"`
+
+	// StorageDriver memilih tempat file gambar disimpan: local atau s3
+	StorageDriver     string `mapstructure:"STORAGE_DRIVER" default:"local"`
+	StorageLocalDir   string `mapstructure:"STORAGE_LOCAL_DIR" default:"./uploads"`
+	StoragePublicURL  string `mapstructure:"STORAGE_PUBLIC_URL" default:"/media"`
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"`
+	S3Region          string `mapstructure:"S3_REGION"`
+	S3Bucket          string `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID     string `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `mapstructure:"S3_SECRET_ACCESS_KEY" secret:"true"`
+	S3PublicURL       string `mapstructure:"S3_PUBLIC_URL"`
+
+	ImageMaxUploadMB    int    `mapstructure:"IMAGE_MAX_UPLOAD_MB" default:"5"`
+	ImageThumbnailSizes string `mapstructure:"IMAGE_THUMBNAIL_SIZES" default:"128,256,512"`
+
+	// LogLevel: debug, info, warn, error. LogFormat: json atau text
+	LogLevel  string `mapstructure:"LOG_LEVEL" default:"info"`
+	LogFormat string `mapstructure:"LOG_FORMAT" default:"json"`
+
+	// RequestTimeoutSeconds batas waktu setiap request API, UploadTimeoutSeconds untuk upload gambar
+	// (thumbnail dan upload ke object storage butuh waktu lebih lama dari request biasa)
+	RequestTimeoutSeconds int `mapstructure:"REQUEST_TIMEOUT_SECONDS" default:"5"`
+	UploadTimeoutSeconds  int `mapstructure:"UPLOAD_TIMEOUT_SECONDS" default:"30"`
+
+	// ReadinessTimeoutSeconds batas waktu ping database di /readyz
+	ReadinessTimeoutSeconds int `mapstructure:"READINESS_TIMEOUT_SECONDS" default:"2"`
+
+	// timeout koneksi http.Server. WriteTimeout harus lebih panjang dari UploadTimeoutSeconds
+	HTTPReadHeaderTimeoutSeconds int `mapstructure:"HTTP_READ_HEADER_TIMEOUT_SECONDS" default:"5"`
+	HTTPReadTimeoutSeconds       int `mapstructure:"HTTP_READ_TIMEOUT_SECONDS" default:"30"`
+	HTTPWriteTimeoutSeconds      int `mapstructure:"HTTP_WRITE_TIMEOUT_SECONDS" default:"40"`
+	HTTPIdleTimeoutSeconds       int `mapstructure:"HTTP_IDLE_TIMEOUT_SECONDS" default:"120"`
+
+	// ShutdownDrainSeconds jeda setelah /readyz 503 sebelum berhenti menerima koneksi,
+	// ShutdownTimeoutSeconds batas menunggu request yang sedang berjalan
+	ShutdownDrainSeconds   int `mapstructure:"SHUTDOWN_DRAIN_SECONDS" default:"5"`
+	ShutdownTimeoutSeconds int `mapstructure:"SHUTDOWN_TIMEOUT_SECONDS" default:"25"`
+
+	// TracingExporter: none, stdout atau otlp (endpoint dari OTEL_EXPORTER_OTLP_ENDPOINT)
+	TracingExporter    string  `mapstructure:"TRACING_EXPORTER" default:"none"`
+	TracingServiceName string  `mapstructure:"TRACING_SERVICE_NAME" default:"go-kasir-umam-ds"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO" default:"1"`
+
+	// CORS untuk UI kasir berbasis browser di origin lain. daftar dipisah koma, kosong berarti CORS mati
+	CORSAllowedOrigins   string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods   string `mapstructure:"CORS_ALLOWED_METHODS" default:"GET,POST,PUT,PATCH,DELETE"`
+	CORSAllowedHeaders   string `mapstructure:"CORS_ALLOWED_HEADERS" default:"Content-Type,If-Match,If-None-Match,Idempotency-Key,X-Outlet-ID,X-Request-ID,X-API-Key"`
+	CORSExposedHeaders   string `mapstructure:"CORS_EXPOSED_HEADERS" default:"ETag,Location,X-Request-ID,Idempotent-Replayed,Retry-After"`
+	CORSAllowCredentials bool   `mapstructure:"CORS_ALLOW_CREDENTIALS" default:"false"`
+	CORSMaxAgeSeconds    int    `mapstructure:"CORS_MAX_AGE_SECONDS" default:"600"`
+
+	// SecurityHSTS hanya diaktifkan kalau API selalu diakses lewat HTTPS. SecurityCSP dikirim di
+	// setiap response, API hanya mengirim JSON jadi default-nya melarang semua sumber
+	SecurityHSTS              bool   `mapstructure:"SECURITY_HSTS" default:"false"`
+	SecurityHSTSMaxAgeSeconds int    `mapstructure:"SECURITY_HSTS_MAX_AGE_SECONDS" default:"31536000"`
+	SecurityCSP               string `mapstructure:"SECURITY_CSP" default:"default-src 'none'; frame-ancestors 'none'"`
+
+	// AuthAPIKeys daftar API key (dipisah koma) yang boleh memanggil /api/v1 lewat header X-API-Key.
+	// kosong berarti API terbuka (misalnya di jaringan lokal toko)
+	AuthAPIKeys string `mapstructure:"AUTH_API_KEYS" secret:"true"`
+
+	// rate limit per client (user, API key atau IP) dengan token bucket, 0 berarti tidak dibatasi.
+	// Read untuk GET, Write untuk method lain, Upload khusus upload gambar produk
+	RateLimitReadPerMinute   int `mapstructure:"RATE_LIMIT_READ_PER_MINUTE" default:"600"`
+	RateLimitWritePerMinute  int `mapstructure:"RATE_LIMIT_WRITE_PER_MINUTE" default:"120"`
+	RateLimitUploadPerMinute int `mapstructure:"RATE_LIMIT_UPLOAD_PER_MINUTE" default:"20"`
+
+	// RequestMaxBodyKB batas body JSON, upload gambar memakai IMAGE_MAX_UPLOAD_MB
+	RequestMaxBodyKB int `mapstructure:"REQUEST_MAX_BODY_KB" default:"1024"`
+
+	// IdempotencyTTLHours lama response POST dengan Idempotency-Key disimpan untuk retry
+	IdempotencyTTLHours int `mapstructure:"IDEMPOTENCY_TTL_HOURS" default:"24"`
 }
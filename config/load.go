@@ -0,0 +1,149 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// FileEnv menunjuk file konfigurasi (.env atau .yaml/.yml). bisa juga lewat flag --config.
+// kalau tidak diisi, .env di direktori kerja dipakai kalau ada.
+const FileEnv = "CONFIG_FILE"
+
+// secretFileSuffix: KEY_FILE=/run/secrets/key membaca nilai KEY dari isi file (Docker/Kubernetes secrets)
+const secretFileSuffix = "_FILE"
+
+// Load membaca konfigurasi dengan urutan prioritas naik: default -> file -> env -> flag.
+// args adalah argumen command line tanpa nama program, misalnya os.Args[1:].
+// Load tidak memvalidasi isi, panggil Validate setelahnya.
+func Load(args []string) (*Config, error) {
+	v := viper.New()
+	keys := Keys()
+
+	flags := flag.NewFlagSet("go-kasir-umam-ds", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFile := flags.String("config", os.Getenv(FileEnv), "path file konfigurasi (.env, .yaml)")
+	for _, key := range keys {
+		flags.String(FlagName(key), "", key)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("config: unexpected argument %q", flags.Arg(0))
+	}
+
+	for _, field := range fields() {
+		if value, ok := field.Tag.Lookup("default"); ok {
+			v.SetDefault(keyOf(field), value)
+		}
+	}
+
+	if err := readFile(v, *configFile); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		// BindEnv per key supaya Unmarshal ikut membaca env walaupun key tidak ada di file
+		v.BindEnv(key)
+		v.BindEnv(key + secretFileSuffix)
+	}
+
+	for _, key := range keys {
+		if err := readSecretFile(v, key); err != nil {
+			return nil, err
+		}
+	}
+
+	// flag paling akhir karena prioritasnya paling tinggi, hanya flag yang benar-benar diisi
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		v.Set(keyFromFlag(f.Name), f.Value.String())
+	})
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return &config, nil
+}
+
+func readFile(v *viper.Viper, path string) error {
+	if path == "" {
+		if _, err := os.Stat(".env"); err != nil {
+			return nil
+		}
+		path = ".env"
+	}
+
+	v.SetConfigFile(path)
+	switch {
+	case strings.HasSuffix(path, ".yaml"), strings.HasSuffix(path, ".yml"):
+		v.SetConfigType("yaml")
+	default:
+		// .env dan file tanpa ekstensi (misalnya config.env) dibaca sebagai dotenv
+		v.SetConfigType("env")
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("config: read %s: %w", path, err)
+	}
+	return nil
+}
+
+// readSecretFile mengisi KEY dari file yang ditunjuk KEY_FILE
+func readSecretFile(v *viper.Viper, key string) error {
+	path := v.GetString(key + secretFileSuffix)
+	if path == "" {
+		return nil
+	}
+	if _, ok := os.LookupEnv(key); ok {
+		return fmt.Errorf("config: set either %s or %s%s, not both", key, key, secretFileSuffix)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %s%s: %w", key, secretFileSuffix, err)
+	}
+	// file secret biasanya diakhiri newline
+	v.Set(key, strings.TrimRight(string(content), "\r\n"))
+	return nil
+}
+
+// Keys mengembalikan semua key konfigurasi sesuai urutan field di Config
+func Keys() []string {
+	var keys []string
+	for _, field := range fields() {
+		keys = append(keys, keyOf(field))
+	}
+	return keys
+}
+
+// FlagName mengubah key menjadi nama flag: APP_PORT -> app-port
+func FlagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+func keyFromFlag(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func fields() []reflect.StructField {
+	t := reflect.TypeOf(Config{})
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, t.Field(i))
+	}
+	return fields
+}
+
+func keyOf(field reflect.StructField) string {
+	return field.Tag.Get("mapstructure")
+}
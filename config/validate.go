@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Validate memeriksa semua nilai dan mengembalikan semua kesalahan sekaligus
+// (satu baris per key) supaya konfigurasi yang salah ketahuan saat startup, bukan saat request pertama.
+func (c *Config) Validate() error {
+	var errs errorList
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs.add("APP_PORT", "must be a port number between 1 and 65535, got %q", c.Port)
+	}
+	if c.DBConn == "" {
+		errs.add("SUPABASE_DB_CONN", "is required")
+	}
+	if c.SalesTaxRate < 0 || c.SalesTaxRate >= 1 {
+		errs.add("SALES_TAX_RATE", "must be a fraction between 0 and 1 (0.11 for 11%%), got %v", c.SalesTaxRate)
+	}
+
+	if c.DBMaxOpenConns < 1 {
+		errs.add("DB_MAX_OPEN_CONNS", "must be at least 1")
+	}
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		errs.add("DB_MAX_IDLE_CONNS", "must be between 0 and DB_MAX_OPEN_CONNS (%d)", c.DBMaxOpenConns)
+	}
+	nonNegative(&errs, "DB_CONN_MAX_LIFETIME_MINUTES", c.DBConnMaxLifetimeMinutes)
+	nonNegative(&errs, "DB_CONN_MAX_IDLE_TIME_MINUTES", c.DBConnMaxIdleTimeMinutes)
+
+	if c.LoyaltyEarnRate < 0 {
+		errs.add("LOYALTY_EARN_RATE", "must not be negative")
+	}
+	if c.LoyaltyRedeemValue < 0 {
+		errs.add("LOYALTY_REDEEM_VALUE", "must not be negative")
+	}
+	nonNegative(&errs, "LOYALTY_POINTS_EXPIRY_DAYS", c.LoyaltyPointsExpiryDays)
+
+	switch c.StorageDriver {
+	case "local":
+		if c.StorageLocalDir == "" {
+			errs.add("STORAGE_LOCAL_DIR", "is required when STORAGE_DRIVER is local")
+		}
+	case "s3":
+		required := []struct{ key, value string }{
+			{"S3_REGION", c.S3Region},
+			{"S3_BUCKET", c.S3Bucket},
+			{"S3_ACCESS_KEY_ID", c.S3AccessKeyID},
+			{"S3_SECRET_ACCESS_KEY", c.S3SecretAccessKey},
+		}
+		for _, r := range required {
+			if r.value == "" {
+				errs.add(r.key, "is required when STORAGE_DRIVER is s3")
+			}
+		}
+	default:
+		errs.add("STORAGE_DRIVER", "must be local or s3, got %q", c.StorageDriver)
+	}
+
+	positive(&errs, "IMAGE_MAX_UPLOAD_MB", c.ImageMaxUploadMB)
+	if _, err := c.ThumbnailSizes(); err != nil {
+		errs.add("IMAGE_THUMBNAIL_SIZES", "%v", err)
+	}
+
+	if !oneOf(strings.ToLower(c.LogLevel), "debug", "info", "warn", "error") {
+		errs.add("LOG_LEVEL", "must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+	if !oneOf(strings.ToLower(c.LogFormat), "json", "text") {
+		errs.add("LOG_FORMAT", "must be json or text, got %q", c.LogFormat)
+	}
+
+	positive(&errs, "REQUEST_TIMEOUT_SECONDS", c.RequestTimeoutSeconds)
+	positive(&errs, "UPLOAD_TIMEOUT_SECONDS", c.UploadTimeoutSeconds)
+	positive(&errs, "READINESS_TIMEOUT_SECONDS", c.ReadinessTimeoutSeconds)
+	positive(&errs, "HTTP_READ_HEADER_TIMEOUT_SECONDS", c.HTTPReadHeaderTimeoutSeconds)
+	positive(&errs, "HTTP_READ_TIMEOUT_SECONDS", c.HTTPReadTimeoutSeconds)
+	positive(&errs, "HTTP_IDLE_TIMEOUT_SECONDS", c.HTTPIdleTimeoutSeconds)
+	// response 504 dari middleware.Timeout harus masih bisa ditulis sebelum koneksi ditutup
+	longest := max(c.RequestTimeoutSeconds, c.UploadTimeoutSeconds)
+	if c.HTTPWriteTimeoutSeconds <= longest {
+		errs.add("HTTP_WRITE_TIMEOUT_SECONDS", "must be longer than REQUEST_TIMEOUT_SECONDS and UPLOAD_TIMEOUT_SECONDS (%d)", longest)
+	}
+	nonNegative(&errs, "SHUTDOWN_DRAIN_SECONDS", c.ShutdownDrainSeconds)
+	positive(&errs, "SHUTDOWN_TIMEOUT_SECONDS", c.ShutdownTimeoutSeconds)
+
+	if !oneOf(strings.ToLower(c.TracingExporter), "none", "stdout", "otlp") {
+		errs.add("TRACING_EXPORTER", "must be none, stdout or otlp, got %q", c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errs.add("TRACING_SAMPLE_RATIO", "must be between 0 and 1, got %v", c.TracingSampleRatio)
+	}
+
+	for _, origin := range c.CORSOrigins() {
+		if origin == "*" {
+			if c.CORSAllowCredentials {
+				errs.add("CORS_ALLOWED_ORIGINS", `"*" cannot be combined with CORS_ALLOW_CREDENTIALS`)
+			}
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs.add("CORS_ALLOWED_ORIGINS", "%q is not an origin like https://kasir.example.com", origin)
+		}
+	}
+	nonNegative(&errs, "CORS_MAX_AGE_SECONDS", c.CORSMaxAgeSeconds)
+	for _, method := range c.CORSMethods() {
+		if method != strings.ToUpper(method) {
+			errs.add("CORS_ALLOWED_METHODS", "methods must be upper case, got %q", method)
+		}
+	}
+
+	if c.SecurityHSTS {
+		positive(&errs, "SECURITY_HSTS_MAX_AGE_SECONDS", c.SecurityHSTSMaxAgeSeconds)
+	}
+
+	nonNegative(&errs, "RATE_LIMIT_READ_PER_MINUTE", c.RateLimitReadPerMinute)
+	nonNegative(&errs, "RATE_LIMIT_WRITE_PER_MINUTE", c.RateLimitWritePerMinute)
+	nonNegative(&errs, "RATE_LIMIT_UPLOAD_PER_MINUTE", c.RateLimitUploadPerMinute)
+	positive(&errs, "REQUEST_MAX_BODY_KB", c.RequestMaxBodyKB)
+
+	positive(&errs, "IDEMPOTENCY_TTL_HOURS", c.IdempotencyTTLHours)
+
+	return errs.err()
+}
+
+// ThumbnailSizes mengubah IMAGE_THUMBNAIL_SIZES seperti "128,256,512" menjadi []int
+func (c *Config) ThumbnailSizes() ([]int, error) {
+	var sizes []int
+	for _, part := range SplitList(c.ImageThumbnailSizes) {
+		size, err := strconv.Atoi(part)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid size %q", part)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// APIKeys daftar AUTH_API_KEYS, kosong berarti API tidak memakai API key
+func (c *Config) APIKeys() []string {
+	return SplitList(c.AuthAPIKeys)
+}
+
+// CORSOrigins daftar CORS_ALLOWED_ORIGINS, kosong berarti CORS mati
+func (c *Config) CORSOrigins() []string {
+	return SplitList(c.CORSAllowedOrigins)
+}
+
+func (c *Config) CORSMethods() []string {
+	return SplitList(c.CORSAllowedMethods)
+}
+
+func (c *Config) CORSHeaders() []string {
+	return SplitList(c.CORSAllowedHeaders)
+}
+
+func (c *Config) CORSExposed() []string {
+	return SplitList(c.CORSExposedHeaders)
+}
+
+// SplitList memecah nilai yang dipisah koma dan membuang spasi serta item kosong
+func SplitList(value string) []string {
+	var items []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+func positive(errs *errorList, key string, value int) {
+	if value <= 0 {
+		errs.add(key, "must be greater than 0, got %d", value)
+	}
+}
+
+func nonNegative(errs *errorList, key string, value int) {
+	if value < 0 {
+		errs.add(key, "must not be negative, got %d", value)
+	}
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// errorList mengumpulkan semua kesalahan validasi supaya semuanya terlihat sekaligus saat startup
+type errorList []error
+
+func (list *errorList) add(key, format string, args ...interface{}) {
+	*list = append(*list, fmt.Errorf("%s %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (list errorList) err() error {
+	return errors.Join(list...)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fajar7xx/go-kasir-umam-ds/internal/services"
 	"fajar7xx/go-kasir-umam-ds/models"
 	"fajar7xx/go-kasir-umam-ds/utils"
@@ -21,38 +20,14 @@ func NewCategoryHandler(categoryService services.CategoryServiceInterface) *Cate
 	}
 }
 
-func (h *CategoryHandler) HandleCategories(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetAll(w, r)
-	case http.MethodPost:
-		h.Create(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetByID(w, r)
-	case http.MethodPatch, http.MethodPut:
-		h.Update(w, r)
-	case http.MethodDelete:
-		h.Delete(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
 func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.categoryService.GetAll()
+	categories, err := h.categoryService.GetAll(r.Context())
 	if err != nil {
-		utils.SendError(w, "INTERNAL_ERROR", err.Error(), http.StatusInternalServerError)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	utils.SendSuccess(w, categories, http.StatusOK)
+	utils.SendSuccessWithETag(w, r, categories, http.StatusOK)
 }
 
 func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -62,26 +37,39 @@ func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := h.categoryService.GetByID(id)
+	category, err := h.categoryService.GetByID(r.Context(), id)
 	if err != nil {
-		utils.SendError(w, "CATEGORY_NOT_FOUND", "category not found", http.StatusNotFound)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	utils.SendSuccess(w, category, http.StatusOK)
+	utils.SendSuccessWithETag(w, r, category, http.StatusOK)
+}
+
+// checkIfMatch mencocokkan If-Match dengan ETag kategori saat ini dan mengembalikan version-nya
+func (h *CategoryHandler) checkIfMatch(r *http.Request, id int) (int, error) {
+	current, err := h.categoryService.GetByID(r.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := utils.CheckIfMatch(r, current); err != nil {
+		return 0, err
+	}
+	return current.Version, nil
 }
 
 func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var newCategory models.Category
-	err := json.NewDecoder(r.Body).Decode(&newCategory)
+	err := utils.DecodeJSON(r, &newCategory)
 	if err != nil {
-		utils.SendError(w, "INVALID_REQUEST", "invalid request body", http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	err = h.categoryService.Create(&newCategory)
+	err = h.categoryService.Create(r.Context(), &newCategory)
 	if err != nil {
-		utils.SendError(w, "CREATE_FAILED", err.Error(), http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
@@ -96,19 +84,55 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var category models.Category
-	err = json.NewDecoder(r.Body).Decode(&category)
+	err = utils.DecodeJSON(r, &category)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	category.Version, err = h.checkIfMatch(r, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	updatedCategory, err := h.categoryService.Update(r.Context(), id, &category)
 	if err != nil {
-		utils.SendError(w, "INVALID_REQUEST", "invalid request body", http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	updatedCategory, err := h.categoryService.Update(id, &category)
+	utils.SendSuccessWithETag(w, r, updatedCategory, http.StatusOK)
+}
+
+// Patch mengubah sebagian field kategori (JSON merge patch), field yang tidak dikirim tetap
+func (h *CategoryHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid category ID format", http.StatusBadRequest)
+		return
+	}
+
+	var patch models.CategoryPatch
+	err = utils.DecodeMergePatch(r, &patch)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	patch.Version, err = h.checkIfMatch(r, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	category, err := h.categoryService.Patch(r.Context(), id, patch)
 	if err != nil {
-		utils.SendError(w, "UPDATE_FAILED", err.Error(), http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	utils.SendSuccess(w, updatedCategory, http.StatusOK)
+	utils.SendSuccessWithETag(w, r, category, http.StatusOK)
 }
 
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -118,9 +142,15 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.categoryService.Delete(id)
+	version, err := h.checkIfMatch(r, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	err = h.categoryService.Delete(r.Context(), id, version)
 	if err != nil {
-		utils.SendError(w, "DELETE_FAILED", err.Error(), http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
 	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
 	"fajar7xx/go-kasir-umam-ds/models"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +17,8 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var currentCategory = &models.Category{ID: 1, Name: "Food", Version: 2}
+
 func TestCategoryHandler_GetAll(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
@@ -24,7 +28,7 @@ func TestCategoryHandler_GetAll(t *testing.T) {
 		{ID: 1, Name: "Food", CreatedAt: now},
 	}
 
-	mockService.On("GetAll").Return(expectedCategories, nil)
+	mockService.On("GetAll", mock.Anything).Return(expectedCategories, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
 	w := httptest.NewRecorder()
@@ -47,7 +51,7 @@ func TestCategoryHandler_GetAll_Error(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("GetAll").Return(nil, errors.New("db error"))
+	mockService.On("GetAll", mock.Anything).Return(nil, errors.New("db error"))
 
 	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
 	w := httptest.NewRecorder()
@@ -65,7 +69,7 @@ func TestCategoryHandler_GetByID(t *testing.T) {
 	now := time.Now()
 	expectedCategory := &models.Category{ID: 1, Name: "Food", CreatedAt: now}
 
-	mockService.On("GetByID", 1).Return(expectedCategory, nil)
+	mockService.On("GetByID", mock.Anything, 1).Return(expectedCategory, nil)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /categories/{id}", handler.GetByID)
@@ -90,7 +94,7 @@ func TestCategoryHandler_GetByID_NotFound(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("GetByID", 1).Return(nil, errors.New("not found"))
+	mockService.On("GetByID", mock.Anything, 1).Return(nil, repositories.ErrCategoryNotFound)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /categories/{id}", handler.GetByID)
@@ -108,9 +112,10 @@ func TestCategoryHandler_Create(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
 
-	newCategory := models.Category{Name: "Food", Description: "Tasty"}
+	desc := "Tasty"
+	newCategory := models.Category{Name: "Food", Description: &desc}
 
-	mockService.On("Create", mock.AnythingOfType("*models.Category")).Return(nil)
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*models.Category")).Return(nil)
 
 	body, _ := json.Marshal(newCategory)
 	req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBuffer(body))
@@ -140,13 +145,15 @@ func TestCategoryHandler_Update(t *testing.T) {
 	handler := NewCategoryHandler(mockService)
 
 	updatedCategory := &models.Category{Name: "Food Updated"}
-	mockService.On("Update", 1, mock.AnythingOfType("*models.Category")).Return(updatedCategory, nil)
+	mockService.On("GetByID", mock.Anything, 1).Return(currentCategory, nil)
+	mockService.On("Update", mock.Anything, 1, mock.AnythingOfType("*models.Category")).Return(updatedCategory, nil)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("PUT /categories/{id}", handler.Update)
 
 	body, _ := json.Marshal(models.Category{Name: "Food Updated"})
 	req := httptest.NewRequest(http.MethodPut, "/categories/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", etagOf(t, currentCategory))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
@@ -159,13 +166,15 @@ func TestCategoryHandler_Update_Error(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("Update", 1, mock.AnythingOfType("*models.Category")).Return(nil, errors.New("failed"))
+	mockService.On("GetByID", mock.Anything, 1).Return(currentCategory, nil)
+	mockService.On("Update", mock.Anything, 1, mock.AnythingOfType("*models.Category")).Return(nil, apperrors.Validation("category name is required"))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("PUT /categories/{id}", handler.Update)
 
 	body, _ := json.Marshal(models.Category{Name: "Food Updated"})
 	req := httptest.NewRequest(http.MethodPut, "/categories/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", etagOf(t, currentCategory))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
@@ -178,12 +187,14 @@ func TestCategoryHandler_Delete(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("Delete", 1).Return(nil)
+	mockService.On("GetByID", mock.Anything, 1).Return(currentCategory, nil)
+	mockService.On("Delete", mock.Anything, 1, 2).Return(nil)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("DELETE /categories/{id}", handler.Delete)
 
 	req := httptest.NewRequest(http.MethodDelete, "/categories/1", nil)
+	req.Header.Set("If-Match", etagOf(t, currentCategory))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
@@ -196,16 +207,37 @@ func TestCategoryHandler_Delete_Error(t *testing.T) {
 	mockService := new(mocks.CategoryServiceMock)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("Delete", 1).Return(errors.New("failed"))
+	mockService.On("GetByID", mock.Anything, 1).Return(currentCategory, nil)
+	mockService.On("Delete", mock.Anything, 1, 2).Return(apperrors.Conflict("CATEGORY_IN_USE", "category still has products"))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("DELETE /categories/{id}", handler.Delete)
 
 	req := httptest.NewRequest(http.MethodDelete, "/categories/1", nil)
+	req.Header.Set("If-Match", etagOf(t, currentCategory))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
 	resp := w.Result()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestCategoryHandler_Delete_StaleETag(t *testing.T) {
+	mockService := new(mocks.CategoryServiceMock)
+	handler := NewCategoryHandler(mockService)
+
+	mockService.On("GetByID", mock.Anything, 1).Return(currentCategory, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /categories/{id}", handler.Delete)
+
+	req := httptest.NewRequest(http.MethodDelete, "/categories/1", nil)
+	req.Header.Set("If-Match", etagOf(t, &models.Category{ID: 1, Name: "Makanan", Version: 1}))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	mockService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
 }
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+)
+
+// customerHandler mengelola endpoint member/pelanggan beserta poin loyalty-nya
+type CustomerHandler struct {
+	customerService services.CustomerServiceInterface
+	loyaltyService  services.LoyaltyServiceInterface
+}
+
+// newCustomerHandler membuat instance baru CustomerHandler
+func NewCustomerHandler(customerService services.CustomerServiceInterface, loyaltyService services.LoyaltyServiceInterface) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+		loyaltyService:  loyaltyService,
+	}
+}
+
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	customers, err := h.customerService.GetAll(ctx)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, customers, http.StatusOK)
+}
+
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid customer ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	customer, err := h.customerService.GetByID(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, customer, http.StatusOK)
+}
+
+// Lookup mencari member dari nomor HP untuk layar kasir, ?phone=0812...
+func (h *CustomerHandler) Lookup(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+	if phone == "" {
+		utils.SendError(w, "INVALID_REQUEST", "phone query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	customer, err := h.customerService.GetByPhone(ctx, phone)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, customer, http.StatusOK)
+}
+
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var newCustomer models.Customer
+	err := utils.DecodeJSON(r, &newCustomer)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	err = h.customerService.Create(ctx, &newCustomer)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, newCustomer, http.StatusCreated)
+}
+
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid customer ID format", http.StatusBadRequest)
+		return
+	}
+
+	var customer models.Customer
+	err = utils.DecodeJSON(r, &customer)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	updatedCustomer, err := h.customerService.Update(ctx, id, &customer)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, updatedCustomer, http.StatusOK)
+}
+
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid customer ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	err = h.customerService.Delete(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, map[string]string{
+		"message": "customer successfully deleted",
+	}, http.StatusOK)
+}
+
+// GetPoints mengembalikan saldo poin dan riwayat ledger member
+func (h *CustomerHandler) GetPoints(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid customer ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	if _, err := h.customerService.GetByID(ctx, id); err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	account, err := h.loyaltyService.GetAccount(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, account, http.StatusOK)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCustomerHandler_Lookup(t *testing.T) {
+	mockService := new(mocks.CustomerServiceMock)
+	handler := NewCustomerHandler(mockService, new(mocks.LoyaltyServiceMock))
+
+	customer := &models.Customer{ID: 1, Name: "Budi", Phone: "081234567890"}
+	mockService.On("GetByPhone", mock.Anything, "081234567890").Return(customer, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/lookup?phone=081234567890", nil)
+	w := httptest.NewRecorder()
+
+	handler.Lookup(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "Budi", data["name"])
+}
+
+func TestCustomerHandler_Lookup_MissingPhone(t *testing.T) {
+	mockService := new(mocks.CustomerServiceMock)
+	handler := NewCustomerHandler(mockService, new(mocks.LoyaltyServiceMock))
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/lookup", nil)
+	w := httptest.NewRecorder()
+
+	handler.Lookup(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestCustomerHandler_Create(t *testing.T) {
+	mockService := new(mocks.CustomerServiceMock)
+	handler := NewCustomerHandler(mockService, new(mocks.LoyaltyServiceMock))
+
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*models.Customer")).Return(nil)
+
+	body, _ := json.Marshal(models.Customer{Name: "Budi", Phone: "081234567890"})
+	req := httptest.NewRequest(http.MethodPost, "/customers", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestCustomerHandler_GetPoints(t *testing.T) {
+	mockService := new(mocks.CustomerServiceMock)
+	mockLoyalty := new(mocks.LoyaltyServiceMock)
+	handler := NewCustomerHandler(mockService, mockLoyalty)
+
+	mockService.On("GetByID", mock.Anything, 1).Return(&models.Customer{ID: 1}, nil)
+	mockLoyalty.On("GetAccount", mock.Anything, 1).Return(&models.LoyaltyAccount{CustomerID: 1, Balance: 120}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /customers/{id}/points", handler.GetPoints)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/1/points", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(120), data["balance"])
+}
+
+func TestCustomerHandler_GetPoints_NotFound(t *testing.T) {
+	mockService := new(mocks.CustomerServiceMock)
+	mockLoyalty := new(mocks.LoyaltyServiceMock)
+	handler := NewCustomerHandler(mockService, mockLoyalty)
+
+	mockService.On("GetByID", mock.Anything, 1).Return(nil, repositories.ErrCustomerNotFound)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /customers/{id}/points", handler.GetPoints)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/1/points", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	mockLoyalty.AssertNotCalled(t, "GetAccount", mock.Anything, mock.Anything)
+}
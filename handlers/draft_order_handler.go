@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+)
+
+// draftOrderHandler mengelola endpoint open tab (pesanan yang diparkir)
+type DraftOrderHandler struct {
+	draftOrderService services.DraftOrderServiceInterface
+}
+
+// newDraftOrderHandler membuat instance baru DraftOrderHandler
+func NewDraftOrderHandler(draftOrderService services.DraftOrderServiceInterface) *DraftOrderHandler {
+	return &DraftOrderHandler{
+		draftOrderService: draftOrderService,
+	}
+}
+
+// GetOpen mengembalikan semua tab yang masih open
+func (h *DraftOrderHandler) GetOpen(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orders, err := h.draftOrderService.GetOpen(ctx)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, orders, http.StatusOK)
+}
+
+func (h *DraftOrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	order, err := h.draftOrderService.GetByID(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, order, http.StatusOK)
+}
+
+func (h *DraftOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var newOrder models.DraftOrder
+	err := utils.DecodeJSON(r, &newOrder)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	createdOrder, err := h.draftOrderService.Create(ctx, &newOrder)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, createdOrder, http.StatusCreated)
+}
+
+// UpdateTag mengganti nomor meja / nama pelanggan sebuah tab
+func (h *DraftOrderHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	var order models.DraftOrder
+	err = utils.DecodeJSON(r, &order)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	updatedOrder, err := h.draftOrderService.UpdateTag(ctx, id, &order)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, updatedOrder, http.StatusOK)
+}
+
+// Cancel membatalkan tab, reservasi stoknya otomatis dilepas
+func (h *DraftOrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	err = h.draftOrderService.Cancel(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, map[string]string{
+		"message": "draft order successfully cancelled",
+	}, http.StatusOK)
+}
+
+func (h *DraftOrderHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	var item models.DraftOrderItem
+	err = utils.DecodeJSON(r, &item)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	order, err := h.draftOrderService.AddItem(ctx, id, &item)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, order, http.StatusCreated)
+}
+
+func (h *DraftOrderHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := utils.ParseIdFromPath(r, "itemId")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid item ID format", http.StatusBadRequest)
+		return
+	}
+
+	var item models.DraftOrderItem
+	err = utils.DecodeJSON(r, &item)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	order, err := h.draftOrderService.UpdateItem(ctx, id, itemID, &item)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, order, http.StatusOK)
+}
+
+func (h *DraftOrderHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := utils.ParseIdFromPath(r, "itemId")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid item ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	order, err := h.draftOrderService.RemoveItem(ctx, id, itemID)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, order, http.StatusOK)
+}
+
+// Merge menggabungkan tab {"source_id": n} ke tab pada path
+func (h *DraftOrderHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		SourceID int `json:"source_id"`
+	}
+	err = utils.DecodeJSON(r, &req)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	if req.SourceID == 0 {
+		utils.SendError(w, "INVALID_REQUEST", "source_id is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	order, err := h.draftOrderService.Merge(ctx, id, req.SourceID)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, order, http.StatusOK)
+}
+
+// Split memindahkan item {"items": [{"item_id": n, "quantity": n}]} ke tab baru
+func (h *DraftOrderHandler) Split(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Items []models.SplitLine `json:"items"`
+	}
+	err = utils.DecodeJSON(r, &req)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	newOrder, err := h.draftOrderService.Split(ctx, id, req.Items)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, newOrder, http.StatusCreated)
+}
+
+// Checkout membayar tab dan mengubahnya menjadi sale
+func (h *DraftOrderHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid draft order ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CheckoutRequest
+	err = utils.DecodeJSON(r, &req)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	sale, err := h.draftOrderService.Checkout(ctx, id, &req)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, sale, http.StatusCreated)
+}
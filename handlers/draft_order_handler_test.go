@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDraftOrderHandler_GetOpen(t *testing.T) {
+	mockService := new(mocks.DraftOrderServiceMock)
+	handler := NewDraftOrderHandler(mockService)
+
+	table := "A1"
+	orders := []models.DraftOrder{{ID: 1, TableNumber: &table, Status: models.DraftOrderStatusOpen}}
+	mockService.On("GetOpen", mock.Anything).Return(orders, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/draft-orders", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetOpen(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	data := response["data"].([]interface{})
+	assert.Len(t, data, 1)
+	assert.Equal(t, "A1", data[0].(map[string]interface{})["table_number"])
+	mockService.AssertExpectations(t)
+}
+
+func TestDraftOrderHandler_Create(t *testing.T) {
+	mockService := new(mocks.DraftOrderServiceMock)
+	handler := NewDraftOrderHandler(mockService)
+
+	created := &models.DraftOrder{ID: 1, Status: models.DraftOrderStatusOpen}
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*models.DraftOrder")).Return(created, nil)
+
+	body := `{"table_number": "A1", "items": [{"product_id": 1, "quantity": 2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/draft-orders", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestDraftOrderHandler_AddItem_Error(t *testing.T) {
+	mockService := new(mocks.DraftOrderServiceMock)
+	handler := NewDraftOrderHandler(mockService)
+
+	mockService.On("AddItem", mock.Anything, 1, mock.AnythingOfType("*models.DraftOrderItem")).
+		Return(nil, repositories.ErrInsufficientStock)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /draft-orders/{id}/items", handler.AddItem)
+
+	req := httptest.NewRequest(http.MethodPost, "/draft-orders/1/items", bytes.NewBufferString(`{"product_id": 1, "quantity": 99}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestDraftOrderHandler_Merge_MissingSource(t *testing.T) {
+	mockService := new(mocks.DraftOrderServiceMock)
+	handler := NewDraftOrderHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /draft-orders/{id}/merge", handler.Merge)
+
+	req := httptest.NewRequest(http.MethodPost, "/draft-orders/1/merge", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	mockService.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderHandler_Checkout(t *testing.T) {
+	mockService := new(mocks.DraftOrderServiceMock)
+	handler := NewDraftOrderHandler(mockService)
+
+	sale := &models.Sale{ID: 10, InvoiceNumber: "INV-20260101-000001", Total: 30000}
+	mockService.On("Checkout", mock.Anything, 1, mock.AnythingOfType("*models.CheckoutRequest")).Return(sale, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /draft-orders/{id}/checkout", handler.Checkout)
+
+	body := `{"payments": [{"method": "cash", "amount": 50000}]}`
+	req := httptest.NewRequest(http.MethodPost, "/draft-orders/1/checkout", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "INV-20260101-000001", data["invoice_number"])
+}
+
+func TestDraftOrderHandler_Checkout_Error(t *testing.T) {
+	mockService := new(mocks.DraftOrderServiceMock)
+	handler := NewDraftOrderHandler(mockService)
+
+	mockService.On("Checkout", mock.Anything, 1, mock.AnythingOfType("*models.CheckoutRequest")).
+		Return(nil, apperrors.Unprocessable("INSUFFICIENT_PAYMENT", "insufficient payment"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /draft-orders/{id}/checkout", handler.Checkout)
+
+	body := `{"payments": [{"method": "cash", "amount": 1000}]}`
+	req := httptest.NewRequest(http.MethodPost, "/draft-orders/1/checkout", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+)
+
+// outletHandler mengelola endpoint cabang, stok per outlet / gabungan dan harga khusus outlet
+type OutletHandler struct {
+	outletService services.OutletServiceInterface
+}
+
+// newOutletHandler membuat instance baru OutletHandler
+func NewOutletHandler(outletService services.OutletServiceInterface) *OutletHandler {
+	return &OutletHandler{
+		outletService: outletService,
+	}
+}
+
+func (h *OutletHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	outlets, err := h.outletService.GetAll(ctx)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, outlets, http.StatusOK)
+}
+
+func (h *OutletHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid outlet ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	outlet, err := h.outletService.GetByID(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, outlet, http.StatusOK)
+}
+
+func (h *OutletHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var newOutlet models.Outlet
+	err := utils.DecodeJSON(r, &newOutlet)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	err = h.outletService.Create(ctx, &newOutlet)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, newOutlet, http.StatusCreated)
+}
+
+func (h *OutletHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid outlet ID format", http.StatusBadRequest)
+		return
+	}
+
+	var outlet models.Outlet
+	err = utils.DecodeJSON(r, &outlet)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	updatedOutlet, err := h.outletService.Update(ctx, id, &outlet)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, updatedOutlet, http.StatusOK)
+}
+
+// GetStock adalah tampilan stok satu outlet
+func (h *OutletHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid outlet ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	stock, err := h.outletService.GetStock(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, stock, http.StatusOK)
+}
+
+// GetConsolidatedStock adalah tampilan stok gabungan semua outlet
+func (h *OutletHandler) GetConsolidatedStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	stock, err := h.outletService.GetConsolidatedStock(ctx)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, stock, http.StatusOK)
+}
+
+// SetPrice mengatur harga khusus outlet, {"price": null} kembali ke harga produk
+func (h *OutletHandler) SetPrice(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid outlet ID format", http.StatusBadRequest)
+		return
+	}
+
+	productID, err := utils.ParseIdFromPath(r, "productId")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req models.OutletPrice
+	err = utils.DecodeJSON(r, &req)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	err = h.outletService.SetPrice(ctx, id, productID, req.Price)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, map[string]any{
+		"outlet_id":  id,
+		"product_id": productID,
+		"price":      req.Price,
+	}, http.StatusOK)
+}
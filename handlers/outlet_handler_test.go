@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutletHandler_GetConsolidatedStock(t *testing.T) {
+	mockService := new(mocks.OutletServiceMock)
+	handler := NewOutletHandler(mockService)
+
+	stocks := []models.ConsolidatedStock{{ProductID: 5, ProductName: "Es Teh", Stock: 14, InTransit: 3}}
+	mockService.On("GetConsolidatedStock", mock.Anything).Return(stocks, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/stock", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetConsolidatedStock(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	data := response["data"].([]interface{})
+	assert.Equal(t, float64(3), data[0].(map[string]interface{})["in_transit"])
+}
+
+func TestOutletHandler_SetPrice_Clear(t *testing.T) {
+	mockService := new(mocks.OutletServiceMock)
+	handler := NewOutletHandler(mockService)
+
+	mockService.On("SetPrice", mock.Anything, 2, 5, (*float64)(nil)).Return(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /outlets/{id}/prices/{productId}", handler.SetPrice)
+
+	req := httptest.NewRequest(http.MethodPut, "/outlets/2/prices/5", bytes.NewBufferString(`{"price": null}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockTransferHandler_Receive_Failed(t *testing.T) {
+	mockService := new(mocks.StockTransferServiceMock)
+	handler := NewStockTransferHandler(mockService)
+
+	mockService.On("Receive", mock.Anything, 7).Return(nil, repositories.ErrStockTransferNotInTransit)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /stock-transfers/{id}/receive", handler.Receive)
+
+	req := httptest.NewRequest(http.MethodPost, "/stock-transfers/7/receive", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+}
@@ -2,12 +2,10 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fajar7xx/go-kasir-umam-ds/internal/services"
 	"fajar7xx/go-kasir-umam-ds/models"
 	"fajar7xx/go-kasir-umam-ds/utils"
 	"net/http"
-	"time"
 )
 
 // producthandler mengelola semua endpoint
@@ -23,46 +21,16 @@ func NewProductHandler(productService services.ProductServiceInterface) *Product
 	}
 }
 
-func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetAll(w, r)
-	case http.MethodPost:
-		h.Create(w, r)
-	default:
-		utils.SendError(w, "METHOD_NOT_ALLOWED", "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetByID(w, r)
-	case http.MethodPatch, http.MethodPut:
-		h.Update(w, r)
-	case http.MethodDelete:
-		h.Delete(w, r)
-	default:
-		utils.SendError(w, "METHOD_NOT_ALLOWED", "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	// Buat context dengan timeout 5 detik
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
+	ctx := r.Context()
 
 	products, err := h.productService.GetAll(ctx)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			utils.SendError(w, "TIMEOUT", "Request timeout", http.StatusGatewayTimeout)
-			return
-		}
-		utils.SendError(w, "INTERNAL_ERROR", err.Error(), http.StatusInternalServerError)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	utils.SendSuccess(w, products, http.StatusOK)
+	utils.SendSuccessWithETag(w, r, products, http.StatusOK)
 }
 
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -72,27 +40,36 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
+	ctx := r.Context()
 
 	product, err := h.productService.GetByID(ctx, id)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			utils.SendError(w, "TIMEOUT", "Request timeout", http.StatusGatewayTimeout)
-			return
-		}
-		utils.SendError(w, "PRODUCT_NOT_FOUND", "product not found", http.StatusNotFound)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	utils.SendSuccess(w, product, http.StatusOK)
+	utils.SendSuccessWithETag(w, r, product, http.StatusOK)
+}
+
+// checkIfMatch membaca produk saat ini dan mencocokkan ETag-nya dengan header If-Match.
+// version yang dikembalikan diteruskan ke repository supaya update yang balapan tetap ditolak.
+func (h *ProductHandler) checkIfMatch(ctx context.Context, r *http.Request, id int) (int, error) {
+	current, err := h.productService.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := utils.CheckIfMatch(r, current); err != nil {
+		return 0, err
+	}
+	return current.Version, nil
 }
 
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var newProduct models.Product
-	err := json.NewDecoder(r.Body).Decode(&newProduct)
+	err := utils.DecodeJSON(r, &newProduct)
 	if err != nil {
-		utils.SendError(w, "INVALID_REQUEST", "invalid request body", http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
@@ -100,46 +77,13 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	// Client requests: Response body WAJIB di-close manual (ini yang sering bikin bingung)
 	defer r.Body.Close()
 
-	// simple validation
-	if newProduct.Name == "" {
-		utils.SendError(w, "VALIDATION_ERROR", "Product name is required", http.StatusBadRequest)
-		return
-	}
-
-	if newProduct.CategoryID == 0 {
-		utils.SendError(w, "VALIDATION_ERROR", "Category ID is required", http.StatusBadRequest)
-		return
-	}
-
-	if newProduct.Price <= 0 {
-		utils.SendError(w, "VALIDATION_ERROR", "Product price must be greater than 0", http.StatusBadRequest)
-		return
-	}
-
-	if newProduct.Stock <= 0 {
-		utils.SendError(w, "VALIDATION_ERROR", "Product stock must be greater than 0", http.StatusBadRequest)
-		return
-	}
-
-	// Context dengan timeout
-	// Kode ini adalah pattern wajib untuk mencegah operasi database/API yang "macet" atau terlalu lama,
-	// supaya aplikasi tidak hang.
-	// r.Context() = context dari HTTP request (otomatis cancel kalau user cabut koneksi)
-	// 5*time.Second = timeout maksimal
-	// ctx = context baru yang punya timeout?
-	// cancel = fungsi untuk stop paksa (kalau perlu)
-	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
-	// Artinya: "Apapun yang terjadi, panggil cancel() saat function ini selesai"
-	// Ini wajib untuk cleanup resource (timer, goroutine internal) yang dipakai context
-	defer cancel()
+	// r.Context() otomatis cancel kalau user cabut koneksi, dan batas waktunya
+	// sudah dipasang oleh middleware.Timeout di main.go (query yang macet ikut dibatalkan)
+	ctx := r.Context()
 
 	createdProduct, err := h.productService.Create(ctx, &newProduct)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			utils.SendError(w, "TIMEOUT", "Request Timeout", http.StatusGatewayTimeout)
-			return
-		}
-		utils.SendError(w, "CREATE_FAILED", err.Error(), http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
@@ -154,48 +98,60 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var product models.Product
-	err = json.NewDecoder(r.Body).Decode(&product)
+	err = utils.DecodeJSON(r, &product)
 	if err != nil {
-		utils.SendError(w, "INVALID_REQUEST", "invalid request body", http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 	defer r.Body.Close()
 
-	// simple validation
-	if product.Name == "" {
-		utils.SendError(w, "VALIDATION_ERROR", "Product name is required", http.StatusBadRequest)
+	ctx := r.Context()
+
+	product.Version, err = h.checkIfMatch(ctx, r, id)
+	if err != nil {
+		utils.SendAppError(w, err)
 		return
 	}
 
-	if product.CategoryID == 0 {
-		utils.SendError(w, "VALIDATION_ERROR", "Category ID is required", http.StatusBadRequest)
+	updatedProduct, err := h.productService.Update(ctx, id, &product)
+	if err != nil {
+		utils.SendAppError(w, err)
 		return
 	}
 
-	if product.Price <= 0 {
-		utils.SendError(w, "VALIDATION_ERROR", "Product price must be greater than 0", http.StatusBadRequest)
+	utils.SendSuccessWithETag(w, r, updatedProduct, http.StatusOK)
+}
+
+// Patch mengubah sebagian field produk (JSON merge patch), field yang tidak dikirim tetap
+func (h *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid product ID format", http.StatusBadRequest)
 		return
 	}
 
-	if product.Stock <= 0 {
-		utils.SendError(w, "VALIDATION_ERROR", "Product stock must be greater than 0", http.StatusBadRequest)
+	var patch models.ProductPatch
+	err = utils.DecodeMergePatch(r, &patch)
+	if err != nil {
+		utils.SendAppError(w, err)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+	ctx := r.Context()
 
-	updatedProduct, err := h.productService.Update(ctx, id, &product)
+	patch.Version, err = h.checkIfMatch(ctx, r, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	product, err := h.productService.Patch(ctx, id, patch)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			utils.SendError(w, "TIMEOUT_ERROR", "Request timed out", http.StatusRequestTimeout)
-			return
-		}
-		utils.SendError(w, "UPDATE_FAILED", err.Error(), http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
-	utils.SendSuccess(w, updatedProduct, http.StatusOK)
+	utils.SendSuccessWithETag(w, r, product, http.StatusOK)
 }
 
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -205,16 +161,17 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+	ctx := r.Context()
+
+	version, err := h.checkIfMatch(ctx, r, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
 
-	err = h.productService.Delete(ctx, id)
+	err = h.productService.Delete(ctx, id, version)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			utils.SendError(w, "TIMEOUT_ERROR", "Request timed out", http.StatusRequestTimeout)
-			return
-		}
-		utils.SendError(w, "DELETE_FAILED", err.Error(), http.StatusBadRequest)
+		utils.SendAppError(w, err)
 		return
 	}
 
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
 	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/validation"
 	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,16 +19,25 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// currentProduct adalah produk yang dibaca handler sebelum update untuk dicocokkan dengan If-Match
+var currentProduct = &models.ProductResponse{ID: 1, Name: "Nasi Goreng", Price: 15000, BasePrice: 15000, Stock: 10, CategoryID: 1, Version: 3}
+
+func etagOf(t *testing.T, data interface{}) string {
+	etag, err := utils.ETag(data)
+	assert.NoError(t, err)
+	return etag
+}
+
 func TestProductHandler_GetAll(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
 	now := time.Now()
-	expectedProducts := []models.Product{
+	expectedProducts := []models.ProductResponse{
 		{ID: 1, Name: "Nasi Goreng", CreatedAt: now},
 	}
 
-	mockService.On("GetAll").Return(expectedProducts, nil)
+	mockService.On("GetAll", mock.Anything).Return(expectedProducts, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/products", nil)
 	w := httptest.NewRecorder()
@@ -47,7 +60,7 @@ func TestProductHandler_GetAll_Error(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("GetAll").Return(nil, errors.New("db error"))
+	mockService.On("GetAll", mock.Anything).Return(nil, errors.New("db error"))
 
 	req := httptest.NewRequest(http.MethodGet, "/products", nil)
 	w := httptest.NewRecorder()
@@ -63,9 +76,9 @@ func TestProductHandler_GetByID(t *testing.T) {
 	handler := NewProductHandler(mockService)
 
 	now := time.Now()
-	expectedProduct := &models.Product{ID: 1, Name: "Nasi Goreng", CreatedAt: now}
+	expectedProduct := &models.ProductResponse{ID: 1, Name: "Nasi Goreng", CreatedAt: now}
 
-	mockService.On("GetByID", 1).Return(expectedProduct, nil)
+	mockService.On("GetByID", mock.Anything, 1).Return(expectedProduct, nil)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /products/{id}", handler.GetByID)
@@ -90,7 +103,7 @@ func TestProductHandler_GetByID_NotFound(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("GetByID", 1).Return(nil, errors.New("not found"))
+	mockService.On("GetByID", mock.Anything, 1).Return(nil, repositories.ErrProductNotFound)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /products/{id}", handler.GetByID)
@@ -104,14 +117,50 @@ func TestProductHandler_GetByID_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
+func TestProductHandler_GetByID_DatabaseUnavailable(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	dbErr := apperrors.Unavailable("DATABASE_UNAVAILABLE", "database is unavailable, please retry", errors.New("dial tcp: connection refused"))
+	mockService.On("GetByID", mock.Anything, 1).Return(nil, dbErr)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /products/{id}", handler.GetByID)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+}
+
+func TestProductHandler_GetAll_ErrorIsNotLeaked(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("GetAll", mock.Anything).Return(nil, errors.New("pq: relation \"products\" does not exist"))
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetAll(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "relation")
+}
+
 func TestProductHandler_Create(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
 	desc := "Tasty"
-	newProduct := models.Product{Name: "Nasi Goreng", Description: &desc, Price: 15000}
+	newProduct := models.Product{Name: "Nasi Goreng", Description: &desc, Price: 15000, Stock: 10, CategoryID: 1}
+	createdProduct := &models.ProductResponse{ID: 1, Name: "Nasi Goreng"}
 
-	mockService.On("Create", mock.AnythingOfType("*models.Product")).Return(nil)
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*models.Product")).Return(createdProduct, nil)
 
 	body, _ := json.Marshal(newProduct)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(body))
@@ -136,18 +185,60 @@ func TestProductHandler_Create_InvalidBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+func TestProductHandler_Create_UnknownField(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	body := `{"name": "Nasi Goreng", "price": 15000, "stock": 10, "categori_id": 1}`
+	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown field \"categori_id\"`)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_Create_ValidationDetails(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	errs := validation.Errors{
+		{Field: "name", Message: "is required"},
+		{Field: "category_id", Message: "category not found"},
+	}
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*models.Product")).Return(nil, errs.Err())
+
+	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(`{"category_id": 9}`))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response utils.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	assert.Equal(t, "VALIDATION_ERROR", response.Error.Code)
+	assert.Len(t, response.Error.Details, 2)
+}
+
 func TestProductHandler_Update(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
-	updatedProduct := &models.Product{Name: "Nasi Goreng Updated"}
-	mockService.On("Update", 1, mock.AnythingOfType("*models.Product")).Return(updatedProduct, nil)
+	updatedProduct := &models.ProductResponse{Name: "Nasi Goreng Updated"}
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+	mockService.On("Update", mock.Anything, 1, mock.MatchedBy(func(p *models.Product) bool {
+		return p.Version == currentProduct.Version
+	})).Return(updatedProduct, nil)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("PUT /products/{id}", handler.Update)
 
-	body, _ := json.Marshal(models.Product{Name: "Nasi Goreng Updated"})
+	body, _ := json.Marshal(models.Product{Name: "Nasi Goreng Updated", Price: 16000, Stock: 5, CategoryID: 1})
 	req := httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", etagOf(t, currentProduct))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
@@ -160,31 +251,35 @@ func TestProductHandler_Update_Error(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("Update", 1, mock.AnythingOfType("*models.Product")).Return(nil, errors.New("failed"))
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+	mockService.On("Update", mock.Anything, 1, mock.AnythingOfType("*models.Product")).Return(nil, apperrors.Unprocessable("CATEGORY_NOT_FOUND", "category not found"))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("PUT /products/{id}", handler.Update)
 
-	body, _ := json.Marshal(models.Product{Name: "Nasi Goreng Updated"})
+	body, _ := json.Marshal(models.Product{Name: "Nasi Goreng Updated", Price: 16000, Stock: 5, CategoryID: 1})
 	req := httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", etagOf(t, currentProduct))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
 	resp := w.Result()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
 }
 
 func TestProductHandler_Delete(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("Delete", 1).Return(nil)
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+	mockService.On("Delete", mock.Anything, 1, 3).Return(nil)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("DELETE /products/{id}", handler.Delete)
 
 	req := httptest.NewRequest(http.MethodDelete, "/products/1", nil)
+	req.Header.Set("If-Match", etagOf(t, currentProduct))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
@@ -197,16 +292,152 @@ func TestProductHandler_Delete_Error(t *testing.T) {
 	mockService := new(mocks.ProductServiceMock)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("Delete", 1).Return(errors.New("failed"))
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+	mockService.On("Delete", mock.Anything, 1, 3).Return(repositories.ErrVersionConflict)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("DELETE /products/{id}", handler.Delete)
 
 	req := httptest.NewRequest(http.MethodDelete, "/products/1", nil)
+	req.Header.Set("If-Match", etagOf(t, currentProduct))
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
 	resp := w.Result()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+}
+
+func TestProductHandler_Patch(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	expected := models.ProductPatch{Price: models.PatchField[float64]{Set: true, Value: 12000}, Version: 3}
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+	mockService.On("Patch", mock.Anything, 1, expected).Return(&models.ProductResponse{ID: 1, Price: 12000}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /products/{id}", handler.Patch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewBufferString(`{"price": 12000}`))
+	req.Header.Set("Content-Type", utils.MergePatchContentType)
+	req.Header.Set("If-Match", etagOf(t, currentProduct))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_Patch_UnsupportedContentType(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /products/{id}", handler.Patch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewBufferString(`[{"op": "replace"}]`))
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_GetByID_NotModified(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /products/{id}", handler.GetByID)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+	req.Header.Set("If-None-Match", `W/"stale", `+etagOf(t, currentProduct))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etagOf(t, currentProduct), w.Header().Get("ETag"))
+	assert.Equal(t, []string{"X-Outlet-ID"}, w.Header().Values("Vary"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestProductHandler_ETagChangesWithStock(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	// checkout mengurangi stok outlet tanpa menaikkan version produk
+	sold := *currentProduct
+	sold.Stock = 8
+	assert.NotEqual(t, etagOf(t, currentProduct), etagOf(t, &sold))
+
+	mockService.On("GetByID", mock.Anything, 1).Return(&sold, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /products/{id}", handler.GetByID)
+	mux.HandleFunc("PUT /products/{id}", handler.Update)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+	req.Header.Set("If-None-Match", etagOf(t, currentProduct))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, etagOf(t, &sold), w.Header().Get("ETag"))
+
+	// PUT dari GET sebelum checkout tidak boleh menimpa stok yang sudah terjual
+	body, _ := json.Marshal(models.Product{Name: "Nasi Goreng", Price: 15000, Stock: 10, CategoryID: 1})
+	req = httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", etagOf(t, currentProduct))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_Update_MissingIfMatch(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /products/{id}", handler.Update)
+
+	body, _ := json.Marshal(models.Product{Name: "Nasi Goreng Updated", Price: 16000, Stock: 5, CategoryID: 1})
+	req := httptest.NewRequest(http.MethodPut, "/products/1", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_Patch_StaleETag(t *testing.T) {
+	mockService := new(mocks.ProductServiceMock)
+	handler := NewProductHandler(mockService)
+
+	// tablet lain sudah mengubah harga sejak GET terakhir
+	mockService.On("GetByID", mock.Anything, 1).Return(currentProduct, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /products/{id}", handler.Patch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewBufferString(`{"price": 12000}`))
+	req.Header.Set("Content-Type", utils.MergePatchContentType)
+	req.Header.Set("If-Match", etagOf(t, &models.ProductResponse{ID: 1, Name: "Nasi Goreng", Price: 14000, Version: 2}))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
 }
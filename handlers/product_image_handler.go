@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// imageFormField adalah nama field multipart yang berisi file gambar
+const imageFormField = "image"
+
+// productimagehandler mengelola upload dan hapus gambar produk
+type ProductImageHandler struct {
+	imageService services.ProductImageServiceInterface
+	// maxBodyBytes membatasi seluruh body multipart, sedikit di atas batas file
+	maxBodyBytes int64
+}
+
+// newproductimagehandler membuat instance baru productimagehandler
+func NewProductImageHandler(imageService services.ProductImageServiceInterface, maxUploadBytes int64) *ProductImageHandler {
+	return &ProductImageHandler{
+		imageService: imageService,
+		maxBodyBytes: maxUploadBytes + 1<<20,
+	}
+}
+
+// Upload menerima multipart/form-data dengan field "image".
+// part dibaca langsung dari stream, tidak ditampung dulu ke file sementara.
+func (h *ProductImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		utils.SendError(w, "INVALID_REQUEST", "request must be multipart/form-data", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		utils.SendError(w, "INVALID_REQUEST", "invalid multipart body", http.StatusBadRequest)
+		return
+	}
+
+	var file io.Reader
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				utils.SendAppError(w, services.ErrImageTooLarge)
+				return
+			}
+			if err == io.EOF {
+				break
+			}
+			utils.SendError(w, "INVALID_REQUEST", "invalid multipart body", http.StatusBadRequest)
+			return
+		}
+
+		if part.FormName() == imageFormField && part.FileName() != "" {
+			file = part
+			break
+		}
+	}
+
+	if file == nil {
+		utils.SendError(w, "INVALID_REQUEST", "image file is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	image, err := h.imageService.Upload(ctx, id, file)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, image, http.StatusCreated)
+}
+
+func (h *ProductImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	imageID, err := utils.ParseIdFromPath(r, "imageId")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid image ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	err = h.imageService.Delete(ctx, id, imageID)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, map[string]string{
+		"message": "product image successfully deleted",
+	}, http.StatusOK)
+}
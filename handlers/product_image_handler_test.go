@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/imaging"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func multipartBody(t *testing.T, field, filename string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func serveImageUpload(handler *ProductImageHandler, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /products/{id}/images", handler.Upload)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestProductImageHandler_Upload(t *testing.T) {
+	mockService := new(mocks.ProductImageServiceMock)
+	handler := NewProductImageHandler(mockService, 1<<20)
+
+	var uploaded []byte
+	mockService.On("Upload", mock.Anything, 1, mock.Anything).Run(func(args mock.Arguments) {
+		uploaded, _ = io.ReadAll(args.Get(2).(io.Reader))
+	}).Return(&models.ProductImage{ID: 3, ProductID: 1, URL: "/media/products/1/ab/original.png"}, nil)
+
+	body, contentType := multipartBody(t, "image", "nasi.png", []byte("png-bytes"))
+	req := httptest.NewRequest(http.MethodPost, "/products/1/images", body)
+	req.Header.Set("Content-Type", contentType)
+
+	w := serveImageUpload(handler, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "png-bytes", string(uploaded))
+}
+
+func TestProductImageHandler_Upload_MissingFile(t *testing.T) {
+	handler := NewProductImageHandler(new(mocks.ProductImageServiceMock), 1<<20)
+
+	body, contentType := multipartBody(t, "other", "nasi.png", []byte("png-bytes"))
+	req := httptest.NewRequest(http.MethodPost, "/products/1/images", body)
+	req.Header.Set("Content-Type", contentType)
+
+	w := serveImageUpload(handler, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProductImageHandler_Upload_NotMultipart(t *testing.T) {
+	handler := NewProductImageHandler(new(mocks.ProductImageServiceMock), 1<<20)
+
+	req := httptest.NewRequest(http.MethodPost, "/products/1/images", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := serveImageUpload(handler, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProductImageHandler_Upload_UnsupportedType(t *testing.T) {
+	mockService := new(mocks.ProductImageServiceMock)
+	handler := NewProductImageHandler(mockService, 1<<20)
+
+	mockService.On("Upload", mock.Anything, 1, mock.Anything).Return(nil, imaging.ErrUnsupportedType)
+
+	body, contentType := multipartBody(t, "image", "menu.pdf", []byte("%PDF"))
+	req := httptest.NewRequest(http.MethodPost, "/products/1/images", body)
+	req.Header.Set("Content-Type", contentType)
+
+	w := serveImageUpload(handler, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestProductImageHandler_Upload_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"too large", services.ErrImageTooLarge, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE"},
+		{"unsupported type", imaging.ErrUnsupportedType, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"too many pixels", imaging.ErrTooManyPixels, http.StatusBadRequest, "INVALID_IMAGE"},
+		// error lain tidak boleh bocor ke client
+		{"internal", errors.New("disk full: /var/media"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ProductImageServiceMock)
+			handler := NewProductImageHandler(mockService, 1<<20)
+			mockService.On("Upload", mock.Anything, 1, mock.Anything).Return(nil, tt.err)
+
+			body, contentType := multipartBody(t, "image", "nasi.png", []byte("png-bytes"))
+			req := httptest.NewRequest(http.MethodPost, "/products/1/images", body)
+			req.Header.Set("Content-Type", contentType)
+
+			w := serveImageUpload(handler, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), `"`+tt.code+`"`)
+			assert.NotContains(t, w.Body.String(), "disk full")
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/receipt"
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"fmt"
+	"net/http"
+)
+
+// receiptHandler merender struk dari sale yang sudah tersimpan
+type ReceiptHandler struct {
+	saleService services.SaleServiceInterface
+	templates   receipt.Templates
+}
+
+// newReceiptHandler membuat instance baru ReceiptHandler
+func NewReceiptHandler(saleService services.SaleServiceInterface, templates receipt.Templates) *ReceiptHandler {
+	return &ReceiptHandler{
+		saleService: saleService,
+		templates:   templates,
+	}
+}
+
+// GetByID merender struk sale dengan format ?format=escpos|text|pdf (default text)
+// dan template toko ?store=<kode> (default template "default")
+func (h *ReceiptHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid sale ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	sale, err := h.saleService.GetByID(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	template := h.templates.Get(r.URL.Query().Get("store"))
+	body, contentType, err := receipt.Render(r.URL.Query().Get("format"), sale, template)
+	if err != nil {
+		if errors.Is(err, receipt.ErrUnknownFormat) {
+			utils.SendError(w, "INVALID_FORMAT", "format must be one of escpos, text, pdf", http.StatusBadRequest)
+			return
+		}
+		utils.SendAppError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if contentType == "application/pdf" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%s.pdf"`, sale.InvoiceNumber))
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+)
+
+// stockTransferHandler mengelola endpoint transfer stok antar outlet
+type StockTransferHandler struct {
+	transferService services.StockTransferServiceInterface
+}
+
+// newStockTransferHandler membuat instance baru StockTransferHandler
+func NewStockTransferHandler(transferService services.StockTransferServiceInterface) *StockTransferHandler {
+	return &StockTransferHandler{
+		transferService: transferService,
+	}
+}
+
+// GetAll menampilkan transfer, bisa difilter ?status=in_transit
+func (h *StockTransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	transfers, err := h.transferService.GetAll(ctx, r.URL.Query().Get("status"))
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, transfers, http.StatusOK)
+}
+
+func (h *StockTransferHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid stock transfer ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	transfer, err := h.transferService.GetByID(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, transfer, http.StatusOK)
+}
+
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var transfer models.StockTransfer
+	err := utils.DecodeJSON(r, &transfer)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	created, err := h.transferService.Create(ctx, &transfer)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, created, http.StatusCreated)
+}
+
+func (h *StockTransferHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid stock transfer ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	transfer, err := h.transferService.Receive(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, transfer, http.StatusOK)
+}
+
+func (h *StockTransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ParseIdFromPath(r, "id")
+	if err != nil {
+		utils.SendError(w, "INVALID_ID", "invalid stock transfer ID format", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	transfer, err := h.transferService.Cancel(ctx, id)
+	if err != nil {
+		utils.SendAppError(w, err)
+		return
+	}
+
+	utils.SendSuccess(w, transfer, http.StatusOK)
+}
@@ -0,0 +1,421 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/handlers"
+	"fajar7xx/go-kasir-umam-ds/internal/idempotency"
+	"fajar7xx/go-kasir-umam-ds/internal/middleware"
+	"fajar7xx/go-kasir-umam-ds/internal/receipt"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/router"
+	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/internal/storage"
+	"fajar7xx/go-kasir-umam-ds/internal/testdb"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// test end-to-end: request HTTP asli melewati handler, service dan repository ke Postgres.
+// jalankan dengan `go test -tags integration -p 1 ./...`
+
+var testDB *testdb.Instance
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testdb.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start test database: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+// newIntegrationServer mengosongkan database lalu memasang route /api/v1 seperti main,
+// tanpa API key dan rate limit
+func newIntegrationServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	testDB.Reset(t)
+	db := testDB.DB
+
+	localStore, err := storage.NewLocalStore(t.TempDir(), "/media")
+	require.NoError(t, err)
+	templates, err := receipt.LoadTemplates("")
+	require.NoError(t, err)
+
+	productRepository := repositories.NewProductRepository(db)
+	categoryRepository := repositories.NewCategoryRepository(db)
+	productImageRepository := repositories.NewProductImageRepository(db)
+	txManager := repositories.NewTxManager(db, repositories.TxConfig{MaxAttempts: 3, RetryDelay: 10 * time.Millisecond})
+	loyaltyRepository := repositories.NewLoyaltyRepository(db)
+	loyaltyService := services.NewLoyaltyService(loyaltyRepository, services.LoyaltyConfig{
+		EarnRate:    0.001,
+		RedeemValue: 100,
+		ExpiryDays:  365,
+	})
+
+	routes := router.New()
+	api := routes.Group("/api/v1",
+		middleware.BodyLimit(middleware.BodyLimitConfig{
+			Default: 64 << 10,
+			Routes:  map[string]int64{uploadRoute: 2 << 20},
+		}),
+		idempotency.Middleware(repositories.NewIdempotencyRepository(db), idempotency.Config{
+			TTL:         time.Hour,
+			LockTimeout: time.Minute,
+		}),
+	)
+	registerAPIRoutes(api, apiHandlers{
+		product: handlers.NewProductHandler(
+			services.NewProductService(productRepository, categoryRepository, productImageRepository, localStore),
+		),
+		productImage: handlers.NewProductImageHandler(
+			services.NewProductImageService(productRepository, productImageRepository, localStore, services.ImageConfig{
+				MaxBytes:       1 << 20,
+				ThumbnailSizes: []int{64},
+			}),
+			1<<20,
+		),
+		category: handlers.NewCategoryHandler(services.NewCategoryService(categoryRepository)),
+		receipt:  handlers.NewReceiptHandler(services.NewSaleService(repositories.NewSaleRepository(db)), templates),
+		customer: handlers.NewCustomerHandler(
+			services.NewCustomerService(repositories.NewCustomerRepository(db)),
+			loyaltyService,
+		),
+		draftOrder: handlers.NewDraftOrderHandler(
+			services.NewDraftOrderService(repositories.NewDraftOrderRepository(db), loyaltyRepository, loyaltyService, txManager, 0.11),
+		),
+		outlet:        handlers.NewOutletHandler(services.NewOutletService(repositories.NewOutletRepository(db))),
+		stockTransfer: handlers.NewStockTransferHandler(services.NewStockTransferService(repositories.NewStockTransferRepository(db))),
+	}, middleware.Timeout(5*time.Second), middleware.Timeout(10*time.Second))
+	routes.Use(middleware.RequestID)
+
+	server := httptest.NewServer(routes)
+	t.Cleanup(server.Close)
+	return server
+}
+
+type apiResponse struct {
+	*http.Response
+	Body []byte
+}
+
+// decode membaca field data dari envelope response ke v
+func (res *apiResponse) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(res.Body, &envelope), string(res.Body))
+	require.NoError(t, json.Unmarshal(envelope.Data, v), string(envelope.Data))
+}
+
+// errorCode membaca kode error dari envelope response gagal
+func (res *apiResponse) errorCode(t *testing.T) string {
+	t.Helper()
+	var envelope struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(res.Body, &envelope), string(res.Body))
+	return envelope.Error.Code
+}
+
+func send(t *testing.T, server *httptest.Server, method, path string, body io.Reader, headers map[string]string) *apiResponse {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, body)
+	require.NoError(t, err)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return &apiResponse{Response: res, Body: data}
+}
+
+func sendJSON(t *testing.T, server *httptest.Server, method, path, body string, headers map[string]string) *apiResponse {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	return send(t, server, method, path, reader, headers)
+}
+
+func TestIntegration_CheckoutFlow(t *testing.T) {
+	server := newIntegrationServer(t)
+	outlet := map[string]string{"X-Outlet-ID": "1"}
+
+	res := sendJSON(t, server, "POST", "/api/v1/categories", `{"name":"Minuman"}`, nil)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var category struct{ ID int }
+	res.decode(t, &category)
+
+	res = sendJSON(t, server, "POST", "/api/v1/products",
+		fmt.Sprintf(`{"name":"Kopi Susu","price":18000,"stock":10,"category_id":%d}`, category.ID), outlet)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var product struct {
+		ID    int
+		Price float64
+		Stock int
+	}
+	res.decode(t, &product)
+	productPath := fmt.Sprintf("/api/v1/products/%d", product.ID)
+
+	// update harga dengan If-Match dari GET, ETag lama ditolak setelah produk berubah
+	res = sendJSON(t, server, "GET", productPath, "", outlet)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	etag := res.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	patch := map[string]string{"X-Outlet-ID": "1", "If-Match": etag, "Content-Type": utils.MergePatchContentType}
+	res = sendJSON(t, server, "PATCH", productPath, `{"price":20000}`, patch)
+	require.Equal(t, http.StatusOK, res.StatusCode, string(res.Body))
+	res.decode(t, &product)
+	assert.Equal(t, 20000.0, product.Price)
+	assert.NotEqual(t, etag, res.Header.Get("ETag"))
+
+	res = sendJSON(t, server, "PATCH", productPath, `{"price":21000}`, patch)
+	assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+
+	res = sendJSON(t, server, "POST", "/api/v1/customers", `{"name":"Umam","phone":"08123456789"}`, nil)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var customer struct{ ID int }
+	res.decode(t, &customer)
+
+	// POST yang diulang dengan Idempotency-Key yang sama tidak membuat open tab kedua
+	draftHeaders := map[string]string{"X-Outlet-ID": "1", idempotency.HeaderKey: "draft-a1"}
+	draftBody := fmt.Sprintf(`{"table_number":"A1","items":[{"product_id":%d,"quantity":2}]}`, product.ID)
+	res = sendJSON(t, server, "POST", "/api/v1/draft-orders", draftBody, draftHeaders)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var order struct{ ID int }
+	res.decode(t, &order)
+
+	replay := sendJSON(t, server, "POST", "/api/v1/draft-orders", draftBody, draftHeaders)
+	require.Equal(t, http.StatusCreated, replay.StatusCode)
+	assert.Equal(t, "true", replay.Header.Get(idempotency.HeaderReplayed))
+	assert.JSONEq(t, string(res.Body), string(replay.Body))
+
+	res = sendJSON(t, server, "GET", "/api/v1/draft-orders", "", outlet)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var openOrders []struct{ ID int }
+	res.decode(t, &openOrders)
+	require.Len(t, openOrders, 1)
+
+	res = sendJSON(t, server, "POST", fmt.Sprintf("/api/v1/draft-orders/%d/checkout", order.ID),
+		fmt.Sprintf(`{"customer_id":%d,"payments":[{"method":"cash","amount":50000}]}`, customer.ID), outlet)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var sale struct {
+		ID            int     `json:"id"`
+		InvoiceNumber string  `json:"invoice_number"`
+		Total         float64 `json:"total"`
+		ChangeAmount  float64 `json:"change_amount"`
+		PointsEarned  int     `json:"points_earned"`
+	}
+	res.decode(t, &sale)
+	assert.Equal(t, 44400.0, sale.Total)
+	assert.Equal(t, 5600.0, sale.ChangeAmount)
+	assert.Equal(t, 40, sale.PointsEarned)
+
+	// stok berkurang, open tab selesai dan poin masuk ke pelanggan
+	res = sendJSON(t, server, "GET", productPath, "", outlet)
+	res.decode(t, &product)
+	assert.Equal(t, 8, product.Stock)
+
+	res = sendJSON(t, server, "GET", "/api/v1/draft-orders", "", outlet)
+	res.decode(t, &openOrders)
+	assert.Empty(t, openOrders)
+
+	res = sendJSON(t, server, "GET", fmt.Sprintf("/api/v1/customers/%d/points", customer.ID), "", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var points struct{ Balance int }
+	res.decode(t, &points)
+	assert.Equal(t, 40, points.Balance)
+
+	res = sendJSON(t, server, "GET", fmt.Sprintf("/api/v1/receipts/%d?format=text", sale.ID), "", outlet)
+	require.Equal(t, http.StatusOK, res.StatusCode, string(res.Body))
+	assert.True(t, strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain"))
+	assert.Contains(t, string(res.Body), sale.InvoiceNumber)
+	assert.Contains(t, string(res.Body), "Kopi Susu")
+
+	// checkout kedua untuk open tab yang sama ditolak
+	res = sendJSON(t, server, "POST", fmt.Sprintf("/api/v1/draft-orders/%d/checkout", order.ID),
+		`{"payments":[{"method":"cash","amount":50000}]}`, outlet)
+	assert.Equal(t, http.StatusConflict, res.StatusCode, string(res.Body))
+}
+
+func TestIntegration_StockTransferFlow(t *testing.T) {
+	server := newIntegrationServer(t)
+
+	res := sendJSON(t, server, "POST", "/api/v1/outlets", `{"code":"CBG","name":"Cabang"}`, nil)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var branch struct{ ID int }
+	res.decode(t, &branch)
+	branchHeaders := map[string]string{"X-Outlet-ID": fmt.Sprint(branch.ID)}
+
+	res = sendJSON(t, server, "POST", "/api/v1/categories", `{"name":"Minuman"}`, nil)
+	var category struct{ ID int }
+	res.decode(t, &category)
+	res = sendJSON(t, server, "POST", "/api/v1/products",
+		fmt.Sprintf(`{"name":"Kopi Susu","price":18000,"stock":10,"category_id":%d}`, category.ID),
+		map[string]string{"X-Outlet-ID": "1"})
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var product struct{ ID int }
+	res.decode(t, &product)
+
+	res = sendJSON(t, server, "POST", "/api/v1/stock-transfers",
+		fmt.Sprintf(`{"to_outlet_id":%d,"items":[{"product_id":%d,"quantity":4}]}`, branch.ID, product.ID),
+		map[string]string{"X-Outlet-ID": "1"})
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var transfer struct{ ID int }
+	res.decode(t, &transfer)
+	receivePath := fmt.Sprintf("/api/v1/stock-transfers/%d/receive", transfer.ID)
+
+	// hanya outlet tujuan yang boleh menerima
+	res = sendJSON(t, server, "POST", receivePath, "", map[string]string{"X-Outlet-ID": "1"})
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	assert.Equal(t, "WRONG_OUTLET", res.errorCode(t))
+
+	res = sendJSON(t, server, "POST", receivePath, "", branchHeaders)
+	require.Equal(t, http.StatusOK, res.StatusCode, string(res.Body))
+
+	res = sendJSON(t, server, "GET", fmt.Sprintf("/api/v1/products/%d", product.ID), "", branchHeaders)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var branchProduct struct{ Stock int }
+	res.decode(t, &branchProduct)
+	assert.Equal(t, 4, branchProduct.Stock)
+
+	res = sendJSON(t, server, "GET", "/api/v1/stock", "", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var consolidated []struct {
+		ProductID int `json:"product_id"`
+		Stock     int `json:"stock"`
+		InTransit int `json:"in_transit"`
+	}
+	res.decode(t, &consolidated)
+	require.Len(t, consolidated, 1)
+	assert.Equal(t, 10, consolidated[0].Stock)
+	assert.Zero(t, consolidated[0].InTransit)
+}
+
+func TestIntegration_ProductImages(t *testing.T) {
+	server := newIntegrationServer(t)
+	outlet := map[string]string{"X-Outlet-ID": "1"}
+
+	res := sendJSON(t, server, "POST", "/api/v1/categories", `{"name":"Minuman"}`, nil)
+	var category struct{ ID int }
+	res.decode(t, &category)
+	res = sendJSON(t, server, "POST", "/api/v1/products",
+		fmt.Sprintf(`{"name":"Kopi Susu","price":18000,"stock":10,"category_id":%d}`, category.ID), outlet)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var product struct{ ID int }
+	res.decode(t, &product)
+	productPath := fmt.Sprintf("/api/v1/products/%d", product.ID)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", "kopi.jpg")
+	require.NoError(t, err)
+	img := image.NewRGBA(image.Rect(0, 0, 120, 80))
+	for x := 0; x < 120; x++ {
+		img.Set(x, 40, color.RGBA{R: 200, A: 255})
+	}
+	require.NoError(t, jpeg.Encode(part, img, nil))
+	require.NoError(t, writer.Close())
+
+	res = send(t, server, "POST", productPath+"/images", &body,
+		map[string]string{"X-Outlet-ID": "1", "Content-Type": writer.FormDataContentType()})
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var uploaded struct {
+		ID         int               `json:"id"`
+		Width      int               `json:"width"`
+		Height     int               `json:"height"`
+		Thumbnails map[string]string `json:"thumbnails"`
+	}
+	res.decode(t, &uploaded)
+	assert.Equal(t, 120, uploaded.Width)
+	assert.Equal(t, 80, uploaded.Height)
+	assert.Contains(t, uploaded.Thumbnails, "64")
+
+	res = sendJSON(t, server, "GET", productPath, "", outlet)
+	var found struct {
+		Images []struct{ ID int }
+	}
+	res.decode(t, &found)
+	require.Len(t, found.Images, 1)
+	assert.Equal(t, uploaded.ID, found.Images[0].ID)
+
+	res = sendJSON(t, server, "DELETE", fmt.Sprintf("%s/images/%d", productPath, uploaded.ID), "", outlet)
+	require.Equal(t, http.StatusOK, res.StatusCode, string(res.Body))
+	res = sendJSON(t, server, "DELETE", fmt.Sprintf("%s/images/%d", productPath, uploaded.ID), "", outlet)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestIntegration_Errors(t *testing.T) {
+	server := newIntegrationServer(t)
+	outlet := map[string]string{"X-Outlet-ID": "1"}
+
+	res := sendJSON(t, server, "GET", "/api/v1/products/99", "", outlet)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, "PRODUCT_NOT_FOUND", res.errorCode(t))
+	assert.NotEmpty(t, res.Header.Get(utils.HeaderRequestID))
+
+	// kategori yang masih dipakai produk tidak bisa dihapus
+	res = sendJSON(t, server, "POST", "/api/v1/categories", `{"name":"Minuman"}`, nil)
+	var category struct{ ID int }
+	res.decode(t, &category)
+	res = sendJSON(t, server, "POST", "/api/v1/products",
+		fmt.Sprintf(`{"name":"Kopi Susu","price":18000,"stock":10,"category_id":%d}`, category.ID), outlet)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	var product struct{ ID int }
+	res.decode(t, &product)
+
+	categoryPath := fmt.Sprintf("/api/v1/categories/%d", category.ID)
+	res = sendJSON(t, server, "GET", categoryPath, "", nil)
+	res = sendJSON(t, server, "DELETE", categoryPath, "", map[string]string{"If-Match": res.Header.Get("ETag")})
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+	assert.Equal(t, "CATEGORY_IN_USE", res.errorCode(t))
+
+	res = sendJSON(t, server, "POST", "/api/v1/customers", `{"name":"Umam","phone":"08123456789"}`, nil)
+	require.Equal(t, http.StatusCreated, res.StatusCode, string(res.Body))
+	res = sendJSON(t, server, "POST", "/api/v1/customers", `{"name":"Umam Dua","phone":"08123456789"}`, nil)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+	assert.Equal(t, "PHONE_ALREADY_REGISTERED", res.errorCode(t))
+
+	// stok yang dipesan melebihi stok outlet
+	res = sendJSON(t, server, "POST", "/api/v1/draft-orders",
+		fmt.Sprintf(`{"table_number":"A1","items":[{"product_id":%d,"quantity":50}]}`, product.ID), outlet)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+	assert.Equal(t, "INSUFFICIENT_STOCK", res.errorCode(t))
+
+	res = sendJSON(t, server, "GET", "/api/v1/products", "", nil)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Equal(t, "OUTLET_REQUIRED", res.errorCode(t))
+}
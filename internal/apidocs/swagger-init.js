@@ -0,0 +1,5 @@
+// file terpisah (bukan inline script) supaya CSP halaman tidak perlu 'unsafe-inline'
+window.ui = SwaggerUIBundle({
+  url: "/openapi.json",
+  dom_id: "#swagger-ui",
+});
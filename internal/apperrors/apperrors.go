@@ -0,0 +1,90 @@
+// Package apperrors berisi error domain yang dipakai bersama oleh repository, service dan handler.
+// setiap error punya Kind (NotFound, Conflict, ...) yang nanti dipetakan ke status HTTP oleh
+// utils.SendAppError, jadi handler tidak perlu lagi menebak dari isi pesan error.
+package apperrors
+
+import (
+	"errors"
+)
+
+// Kind error. cek dengan errors.Is(err, apperrors.ErrNotFound)
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrValidation    = errors.New("validation failed")
+	ErrUnprocessable = errors.New("unprocessable")
+	ErrForbidden     = errors.New("forbidden")
+	ErrUnavailable   = errors.New("unavailable")
+	ErrUnsupported   = errors.New("unsupported media type")
+	ErrTooLarge      = errors.New("payload too large")
+	ErrPrecondition  = errors.New("precondition failed")
+	// ErrPreconditionRequired dipakai saat request wajib membawa header kondisi (If-Match)
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// Error adalah error domain yang aman ditampilkan ke client.
+// Message dan Details dikirim apa adanya, Err (penyebab asli) tidak pernah dikirim ke client.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Details interface{}
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap membuat errors.Is cocok dengan Kind maupun penyebab aslinya
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap sama seperti New tapi menyimpan error asli untuk logging dan errors.Is
+func Wrap(kind error, code, message string, err error) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+// Validation dipakai untuk input yang formatnya salah (400)
+func Validation(message string) *Error {
+	return New(ErrValidation, "VALIDATION_ERROR", message)
+}
+
+// Unprocessable dipakai untuk input yang formatnya benar tapi tidak bisa diproses,
+// misalnya merujuk data yang tidak ada atau pembayaran kurang (422)
+func Unprocessable(code, message string) *Error {
+	return New(ErrUnprocessable, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func Unavailable(code, message string, err error) *Error {
+	return Wrap(ErrUnavailable, code, message, err)
+}
+
+// As mengambil *Error dari rantai error, ok bernilai false kalau err bukan error domain
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,70 @@
+package apperrors
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_IsKindAndCause(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+	err := fmt.Errorf("load product: %w", Unavailable("DATABASE_UNAVAILABLE", "database is unavailable", cause))
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	appErr, ok := As(err)
+	assert.True(t, ok)
+	assert.Equal(t, "DATABASE_UNAVAILABLE", appErr.Code)
+	assert.Equal(t, "database is unavailable", appErr.Error())
+}
+
+func TestFromDB(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+		code string
+	}{
+		{"unique violation", &pgconn.PgError{Code: PgUniqueViolation}, ErrConflict, "ALREADY_EXISTS"},
+		{"fk missing reference", &pgconn.PgError{Code: PgForeignKeyViolation, Detail: `Key (category_id)=(9) is not present in table "categories".`}, ErrUnprocessable, "INVALID_REFERENCE"},
+		{"fk still referenced", &pgconn.PgError{Code: PgForeignKeyViolation, Detail: `Key (id)=(1) is still referenced from table "products".`}, ErrConflict, "STILL_REFERENCED"},
+		{"check violation", &pgconn.PgError{Code: PgCheckViolation}, ErrValidation, "VALIDATION_ERROR"},
+		{"serialization failure", &pgconn.PgError{Code: PgSerializationFailure}, ErrConflict, "CONCURRENT_UPDATE"},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, ErrUnavailable, "DATABASE_UNAVAILABLE"},
+		{"bad connection", fmt.Errorf("query: %w", driver.ErrBadConn), ErrUnavailable, "DATABASE_UNAVAILABLE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromDB(tt.err)
+
+			assert.ErrorIs(t, err, tt.kind)
+			appErr, ok := As(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, appErr.Code)
+		})
+	}
+}
+
+func TestFromDB_PassThrough(t *testing.T) {
+	notFound := NotFound("PRODUCT_NOT_FOUND", "product not found")
+	assert.Same(t, notFound, FromDB(notFound))
+	assert.Equal(t, context.DeadlineExceeded, FromDB(context.DeadlineExceeded))
+	assert.Nil(t, FromDB(nil))
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(&pgconn.PgError{Code: PgSerializationFailure}))
+	// tetap dikenali setelah diterjemahkan repository
+	assert.True(t, IsRetryable(FromDB(&pgconn.PgError{Code: PgDeadlockDetected})))
+	assert.False(t, IsRetryable(FromDB(&pgconn.PgError{Code: PgUniqueViolation})))
+	assert.False(t, IsRetryable(context.Canceled))
+	assert.False(t, IsRetryable(nil))
+}
@@ -0,0 +1,97 @@
+package apperrors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// kode error Postgres yang diterjemahkan, lihat https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	PgUniqueViolation      = "23505"
+	PgForeignKeyViolation  = "23503"
+	PgNotNullViolation     = "23502"
+	PgCheckViolation       = "23514"
+	PgSerializationFailure = "40001"
+	PgDeadlockDetected     = "40P01"
+)
+
+// FromDB menerjemahkan error dari database menjadi error domain.
+// error yang sudah berupa *Error, context error dan error lain yang tidak dikenali dikembalikan apa adanya.
+func FromDB(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := As(err); ok {
+		return err
+	}
+
+	// context.DeadlineExceeded juga memenuhi net.Error, jadi harus dicek lebih dulu
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return fromPgError(pgErr)
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return Unavailable("DATABASE_UNAVAILABLE", "database is unavailable, please retry", err)
+	}
+
+	return err
+}
+
+func fromPgError(pgErr *pgconn.PgError) error {
+	switch pgErr.Code {
+	case PgUniqueViolation:
+		return Wrap(ErrConflict, "ALREADY_EXISTS", "resource already exists", pgErr)
+	case PgForeignKeyViolation:
+		// delete/update data yang masih dipakai tabel lain vs insert yang merujuk data yang tidak ada
+		if strings.Contains(pgErr.Detail, "is still referenced") {
+			return Wrap(ErrConflict, "STILL_REFERENCED", "resource is still referenced by other data", pgErr)
+		}
+		return Wrap(ErrUnprocessable, "INVALID_REFERENCE", "referenced resource does not exist", pgErr)
+	case PgNotNullViolation, PgCheckViolation:
+		return Wrap(ErrValidation, "VALIDATION_ERROR", "invalid value", pgErr)
+	case PgSerializationFailure, PgDeadlockDetected:
+		return Wrap(ErrConflict, "CONCURRENT_UPDATE", "resource was modified concurrently, please retry", pgErr)
+	}
+
+	switch {
+	case strings.HasPrefix(pgErr.Code, "08"), // connection exception
+		strings.HasPrefix(pgErr.Code, "53"),  // insufficient resources
+		strings.HasPrefix(pgErr.Code, "57P"): // server shutdown
+		return Unavailable("DATABASE_UNAVAILABLE", "database is unavailable, please retry", pgErr)
+	case strings.HasPrefix(pgErr.Code, "22"): // data exception
+		return Wrap(ErrValidation, "VALIDATION_ERROR", "invalid value", pgErr)
+	}
+
+	return pgErr
+}
+
+// IsRetryable true untuk serialization failure dan deadlock, transaksinya aman diulang dari awal
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == PgSerializationFailure || pgErr.Code == PgDeadlockDetected
+}
+
+// IsConstraint mengecek apakah err adalah pelanggaran constraint Postgres dengan nama tertentu,
+// dipakai repository untuk memberi pesan yang lebih jelas, misalnya nomor HP yang sudah terdaftar
+func IsConstraint(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.ConstraintName == constraint
+}
@@ -0,0 +1,40 @@
+// Package buildinfo menyimpan versi dan commit binary. nilainya diisi saat build:
+//
+//	go build -ldflags "-X fajar7xx/go-kasir-umam-ds/internal/buildinfo.Version=v1.2.0 -X fajar7xx/go-kasir-umam-ds/internal/buildinfo.Commit=$(git rev-parse HEAD)"
+//
+// tanpa ldflags, commit diambil dari informasi VCS yang ditanam go build.
+package buildinfo
+
+import "runtime/debug"
+
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// Info versi yang ditampilkan di /readyz
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit}
+	if info.Commit == "" {
+		info.Commit = vcsRevision()
+	}
+	return info
+}
+
+func vcsRevision() string {
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, setting := range build.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return "unknown"
+}
@@ -3,37 +3,49 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	// _ "github.com/lib/pq"
+	"github.com/XSAM/otelsql"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
 )
 
-func InitDB(connectionString string) (*sql.DB, error) {
-	// open database
-	db, err := sql.Open("pgx", connectionString)
+// PoolConfig pengaturan connection pool, diisi dari DB_MAX_OPEN_CONNS dan seterusnya
+type PoolConfig struct {
+	MaxOpenConns int
+	// MaxIdleConns sebaiknya sama dengan MaxOpenConns supaya koneksi tidak sering dibuka-tutup saat ramai
+	MaxIdleConns int
+	// ConnMaxLifetime memastikan koneksi diperbarui sebelum diputus firewall, 0 berarti tanpa batas
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+func InitDB(connectionString string, pool PoolConfig) (*sql.DB, error) {
+	// open database, dibungkus otelsql supaya setiap query menjadi span (tanpa efek kalau tracing mati)
+	db, err := otelsql.Open("pgx", connectionString,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
-		fmt.Printf("sql.open: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("sql.open: %w", err)
 	}
 
 	// connection test
 	err = db.Ping()
 	if err != nil {
-		fmt.Printf("db.Ping: %v", err)
-		fmt.Println("error ping")
-		return nil, err
+		return nil, fmt.Errorf("db.Ping: %w", err)
 	}
 
-	// set connection pool settings (optional but recommended)
-	// Optimized: Set MaxIdleConns equal to MaxOpenConns to minimize connection churn
-	// and improve performance under high concurrency.
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	// Set MaxLifetime to ensure connections are refreshed and not closed by firewalls unexpectedly.
-	db.SetConnMaxLifetime(time.Hour)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
-	log.Println("Database connected successfully")
+	slog.Info("database connected", "max_open_conns", pool.MaxOpenConns, "max_idle_conns", pool.MaxIdleConns)
 	return db, nil
 }
@@ -0,0 +1,44 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/database"
+	"fajar7xx/go-kasir-umam-ds/internal/testdb"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_Integration(t *testing.T) {
+	instance, err := testdb.Start()
+	require.NoError(t, err)
+	defer instance.Close()
+
+	// Start sudah menjalankan semua migrasi, menjalankannya lagi tidak mengubah apa pun
+	require.NoError(t, database.Migrate(context.Background(), instance.DB))
+
+	files, err := fs.Glob(os.DirFS("migrations"), "*.sql")
+	require.NoError(t, err)
+
+	var applied []string
+	rows, err := instance.DB.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var version string
+		require.NoError(t, rows.Scan(&version))
+		applied = append(applied, version)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, files, applied)
+
+	// outlet bawaan untuk data lama
+	var code string
+	require.NoError(t, instance.DB.QueryRow(`SELECT code FROM outlets WHERE id = 1`).Scan(&code))
+	assert.Equal(t, "MAIN", code)
+}
@@ -0,0 +1,19 @@
+-- skema dasar yang sudah dipakai sejak awal (categories & products)
+CREATE TABLE IF NOT EXISTS categories (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT,
+    price       NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    stock       INTEGER NOT NULL DEFAULT 0,
+    category_id INTEGER NOT NULL REFERENCES categories (id),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ
+);
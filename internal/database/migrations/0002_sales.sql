@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS sales (
+    id             SERIAL PRIMARY KEY,
+    invoice_number VARCHAR(50) NOT NULL UNIQUE,
+    subtotal       NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    discount_total NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    tax_total      NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    total          NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    paid_amount    NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    change_amount  NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS sale_items (
+    id           SERIAL PRIMARY KEY,
+    sale_id      INTEGER NOT NULL REFERENCES sales (id) ON DELETE CASCADE,
+    product_id   INTEGER NOT NULL REFERENCES products (id),
+    product_name VARCHAR(255) NOT NULL,
+    quantity     INTEGER NOT NULL,
+    price        NUMERIC(15, 2) NOT NULL,
+    discount     NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    subtotal     NUMERIC(15, 2) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS sale_payments (
+    id      SERIAL PRIMARY KEY,
+    sale_id INTEGER NOT NULL REFERENCES sales (id) ON DELETE CASCADE,
+    method  VARCHAR(30) NOT NULL,
+    amount  NUMERIC(15, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sale_items_sale_id ON sale_items (sale_id);
+CREATE INDEX IF NOT EXISTS idx_sale_payments_sale_id ON sale_payments (sale_id);
@@ -0,0 +1,27 @@
+-- draft order (open tab) untuk pesanan yang diparkir sebelum dibayar.
+-- stok tidak dikurangi selama tab masih open, jumlah yang dipesan dihitung
+-- sebagai reservasi terhadap products.stock.
+CREATE TABLE IF NOT EXISTS draft_orders (
+    id            SERIAL PRIMARY KEY,
+    table_number  VARCHAR(20),
+    customer_name VARCHAR(100),
+    status        VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'paid', 'cancelled', 'merged')),
+    sale_id       INTEGER REFERENCES sales (id),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS draft_order_items (
+    id             SERIAL PRIMARY KEY,
+    draft_order_id INTEGER NOT NULL REFERENCES draft_orders (id) ON DELETE CASCADE,
+    product_id     INTEGER NOT NULL REFERENCES products (id),
+    quantity       INTEGER NOT NULL CHECK (quantity > 0),
+    price          NUMERIC(15, 2) NOT NULL,
+    discount       NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_draft_orders_status ON draft_orders (status);
+CREATE INDEX IF NOT EXISTS idx_draft_order_items_draft_order_id ON draft_order_items (draft_order_id);
+CREATE INDEX IF NOT EXISTS idx_draft_order_items_product_id ON draft_order_items (product_id);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    phone      VARCHAR(20) NOT NULL UNIQUE,
+    email      VARCHAR(255),
+    birthday   DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ
+);
+
+-- ledger poin loyalty. entry earn menyimpan sisa poin (remaining) yang belum
+-- ditukar supaya penukaran bisa memakai poin yang paling cepat kedaluwarsa dulu (FIFO).
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    id          SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+    sale_id     INTEGER REFERENCES sales (id),
+    type        VARCHAR(10) NOT NULL CHECK (type IN ('earn', 'redeem', 'expire', 'refund')),
+    points      INTEGER NOT NULL,
+    remaining   INTEGER NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer_id ON loyalty_ledger (customer_id);
+
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customers (id);
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS points_earned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS points_redeemed INTEGER NOT NULL DEFAULT 0;
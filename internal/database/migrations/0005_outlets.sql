@@ -0,0 +1,64 @@
+-- multi outlet: produk dan kategori tetap dipakai bersama, stok dan harga
+-- khusus disimpan per outlet di outlet_stock. outlet 1 dibuat untuk data lama.
+CREATE TABLE IF NOT EXISTS outlets (
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(20) NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    address    TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ
+);
+
+INSERT INTO outlets (id, code, name) VALUES (1, 'MAIN', 'Outlet Utama')
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('outlets', 'id'), (SELECT MAX(id) FROM outlets));
+
+-- price NULL berarti outlet memakai products.price
+CREATE TABLE IF NOT EXISTS outlet_stock (
+    outlet_id  INTEGER NOT NULL REFERENCES outlets (id),
+    product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    stock      INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    price      NUMERIC(15, 2),
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (outlet_id, product_id)
+);
+
+INSERT INTO outlet_stock (outlet_id, product_id, stock)
+SELECT 1, id, stock FROM products
+ON CONFLICT DO NOTHING;
+
+-- products.stock tidak dipakai lagi tapi belum dihapus supaya client lama yang masih membacanya
+-- tidak langsung rusak. kolom ini di-drop di migrasi terpisah setelah outlet_stock terverifikasi.
+COMMENT ON COLUMN products.stock IS 'deprecated: gunakan outlet_stock.stock';
+
+ALTER TABLE draft_orders ADD COLUMN IF NOT EXISTS outlet_id INTEGER NOT NULL DEFAULT 1 REFERENCES outlets (id);
+ALTER TABLE sales ADD COLUMN IF NOT EXISTS outlet_id INTEGER NOT NULL DEFAULT 1 REFERENCES outlets (id);
+
+CREATE INDEX IF NOT EXISTS idx_draft_orders_outlet_id ON draft_orders (outlet_id, status);
+CREATE INDEX IF NOT EXISTS idx_sales_outlet_id ON sales (outlet_id);
+
+-- transfer stok antar outlet. stok asal dikurangi saat dikirim (in_transit),
+-- stok tujuan baru bertambah saat transfer diterima.
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id             SERIAL PRIMARY KEY,
+    from_outlet_id INTEGER NOT NULL REFERENCES outlets (id),
+    to_outlet_id   INTEGER NOT NULL REFERENCES outlets (id),
+    status         VARCHAR(20) NOT NULL DEFAULT 'in_transit'
+        CHECK (status IN ('in_transit', 'received', 'cancelled')),
+    note           TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    received_at    TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    CHECK (from_outlet_id <> to_outlet_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_transfer_items (
+    id          SERIAL PRIMARY KEY,
+    transfer_id INTEGER NOT NULL REFERENCES stock_transfers (id) ON DELETE CASCADE,
+    product_id  INTEGER NOT NULL REFERENCES products (id),
+    quantity    INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_status ON stock_transfers (status);
+CREATE INDEX IF NOT EXISTS idx_stock_transfer_items_transfer_id ON stock_transfer_items (transfer_id);
@@ -0,0 +1,16 @@
+-- gambar produk. file disimpan di BlobStore, tabel ini hanya menyimpan key-nya
+-- sehingga URL publik bisa berubah (misalnya pindah ke CDN) tanpa migrasi data.
+-- thumbnails berisi {"128": "products/1/ab12/128.jpg", ...}
+CREATE TABLE IF NOT EXISTS product_images (
+    id           SERIAL PRIMARY KEY,
+    product_id   INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    storage_key  VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    width        INTEGER NOT NULL,
+    height       INTEGER NOT NULL,
+    size_bytes   INTEGER NOT NULL,
+    thumbnails   JSONB NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images (product_id);
@@ -0,0 +1,3 @@
+-- versi baris untuk optimistic concurrency (ETag / If-Match), naik setiap kali data diubah
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
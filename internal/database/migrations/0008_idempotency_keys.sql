@@ -0,0 +1,14 @@
+-- response yang sudah dikirim untuk setiap Idempotency-Key, supaya POST yang diulang kasir
+-- (misalnya karena Wi-Fi putus) dijawab ulang dengan response yang sama tanpa membuat data dobel.
+-- status_code NULL berarti request pertama masih diproses.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key              VARCHAR(255) PRIMARY KEY,
+    fingerprint      CHAR(64) NOT NULL,
+    status_code      INTEGER,
+    response_headers JSONB,
+    response_body    BYTEA,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,7 @@
+-- penukaran poin sekarang dibatalkan lewat rollback transaksi checkout, entry refund
+-- tidak ditulis lagi. refund lama diperlakukan sama seperti earn (punya remaining dan expires_at).
+UPDATE loyalty_ledger SET type = 'earn' WHERE type = 'refund';
+
+ALTER TABLE loyalty_ledger DROP CONSTRAINT IF EXISTS loyalty_ledger_type_check;
+ALTER TABLE loyalty_ledger ADD CONSTRAINT loyalty_ledger_type_check
+    CHECK (type IN ('earn', 'redeem', 'expire'));
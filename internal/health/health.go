@@ -0,0 +1,143 @@
+// Package health menyediakan probe untuk container orchestrator: /livez hanya memastikan
+// proses masih hidup, /readyz memastikan aplikasi siap menerima traffic (database bisa
+// di-ping dan server tidak sedang shutdown).
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/buildinfo"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	statusOK       = "ok"
+	statusFail     = "fail"
+	statusDraining = "draining"
+)
+
+type Checker struct {
+	db       *sql.DB
+	timeout  time.Duration
+	build    buildinfo.Info
+	started  time.Time
+	draining atomic.Bool
+}
+
+// NewChecker membuat checker untuk pool dari database.InitDB. timeout membatasi ping
+// supaya probe tidak menggantung saat database macet.
+func NewChecker(db *sql.DB, build buildinfo.Info, timeout time.Duration) *Checker {
+	return &Checker{
+		db:      db,
+		timeout: timeout,
+		build:   build,
+		started: time.Now(),
+	}
+}
+
+// Drain membuat /readyz mengembalikan 503 supaya load balancer berhenti mengirim
+// request baru, dipanggil di awal graceful shutdown
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+type LiveResponse struct {
+	Status string `json:"status"`
+}
+
+type ReadyResponse struct {
+	Status        string         `json:"status"`
+	Version       string         `json:"version"`
+	Commit        string         `json:"commit"`
+	UptimeSeconds int64          `json:"uptime_seconds"`
+	Database      DatabaseStatus `json:"database"`
+}
+
+type DatabaseStatus struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS float64   `json:"latency_ms"`
+	Pool      PoolStats `json:"pool"`
+}
+
+// PoolStats ringkasan sql.DBStats dari connection pool
+type PoolStats struct {
+	MaxOpen        int     `json:"max_open"`
+	Open           int     `json:"open"`
+	InUse          int     `json:"in_use"`
+	Idle           int     `json:"idle"`
+	WaitCount      int64   `json:"wait_count"`
+	WaitDurationMS float64 `json:"wait_duration_ms"`
+}
+
+// Livez GET /livez: 200 selama proses bisa melayani HTTP. sengaja tidak mengecek database,
+// kalau database mati restart pod tidak membantu.
+func (c *Checker) Livez(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, LiveResponse{Status: statusOK})
+}
+
+// Readyz GET /readyz: 200 kalau database bisa di-ping, 503 kalau tidak atau sedang shutdown
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	response := ReadyResponse{
+		Status:        statusOK,
+		Version:       c.build.Version,
+		Commit:        c.build.Commit,
+		UptimeSeconds: int64(time.Since(c.started).Seconds()),
+		Database:      c.checkDatabase(r.Context()),
+	}
+
+	if response.Database.Status != statusOK {
+		response.Status = statusFail
+	}
+	if c.draining.Load() {
+		response.Status = statusDraining
+	}
+
+	status := http.StatusOK
+	if response.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, response)
+}
+
+func (c *Checker) checkDatabase(ctx context.Context) DatabaseStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.db.PingContext(ctx)
+	latency := time.Since(start)
+
+	stats := c.db.Stats()
+	result := DatabaseStatus{
+		Status:    statusOK,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+		Pool: PoolStats{
+			MaxOpen:        stats.MaxOpenConnections,
+			Open:           stats.OpenConnections,
+			InUse:          stats.InUse,
+			Idle:           stats.Idle,
+			WaitCount:      stats.WaitCount,
+			WaitDurationMS: float64(stats.WaitDuration.Microseconds()) / 1000,
+		},
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "readiness: database ping failed", "error", err)
+		result.Status = statusFail
+		// detail error hanya di log, /readyz bisa diakses dari luar
+		result.Error = "database unavailable"
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	// probe tidak boleh di-cache oleh proxy
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/buildinfo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestChecker(t *testing.T) (*Checker, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewChecker(db, buildinfo.Info{Version: "v1.0.0", Commit: "abc123"}, time.Second), mock
+}
+
+func readyz(checker *Checker) (*httptest.ResponseRecorder, ReadyResponse) {
+	w := httptest.NewRecorder()
+	checker.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var response ReadyResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestLivez(t *testing.T) {
+	checker, _ := newTestChecker(t)
+
+	w := httptest.NewRecorder()
+	checker.Livez(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestReadyz_OK(t *testing.T) {
+	checker, mock := newTestChecker(t)
+	mock.ExpectPing()
+
+	w, response := readyz(checker)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", response.Status)
+	assert.Equal(t, "ok", response.Database.Status)
+	assert.Equal(t, "v1.0.0", response.Version)
+	assert.Equal(t, "abc123", response.Commit)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadyz_DatabaseDown(t *testing.T) {
+	checker, mock := newTestChecker(t)
+	mock.ExpectPing().WillReturnError(errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+
+	w, response := readyz(checker)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "fail", response.Status)
+	assert.Equal(t, "fail", response.Database.Status)
+	// alamat database tidak boleh ikut terkirim
+	assert.Equal(t, "database unavailable", response.Database.Error)
+	assert.NotContains(t, w.Body.String(), "10.0.0.5")
+}
+
+func TestReadyz_Draining(t *testing.T) {
+	checker, mock := newTestChecker(t)
+	mock.ExpectPing()
+
+	checker.Drain()
+	w, response := readyz(checker)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "draining", response.Status)
+	// database tetap dilaporkan sehat, yang 503 karena shutdown
+	assert.Equal(t, "ok", response.Database.Status)
+}
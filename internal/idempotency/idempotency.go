@@ -0,0 +1,176 @@
+// Package idempotency membuat POST aman diulang. kasir mengirim header Idempotency-Key yang
+// sama saat retry, dan response dari request pertama dikirim ulang tanpa menjalankan handler lagi.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderKey adalah header yang dikirim client, nilainya bebas (biasanya UUID) maksimal MaxKeyLength
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed ditambahkan ke response yang dikirim ulang dari penyimpanan
+	HeaderReplayed = "Idempotent-Replayed"
+
+	MaxKeyLength = 255
+)
+
+// header response yang ikut disimpan dan dikirim ulang
+var replayHeaders = []string{"Content-Type", "Location", "ETag"}
+
+type Config struct {
+	// TTL lama response disimpan, retry setelah itu diproses sebagai request baru
+	TTL time.Duration
+	// LockTimeout batas request pertama dianggap macet (misalnya server mati) sehingga key boleh dipakai lagi
+	LockTimeout time.Duration
+}
+
+// Middleware menyimpan response POST yang membawa Idempotency-Key. request lain
+// (atau POST tanpa header) diteruskan apa adanya, jadi handler tidak perlu tahu soal ini.
+func Middleware(repo repositories.IdempotencyRepositoryInterface, config Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > MaxKeyLength {
+				utils.SendError(w, "INVALID_IDEMPOTENCY_KEY",
+					HeaderKey+" must be at most "+strconv.Itoa(MaxKeyLength)+" characters", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				utils.SendError(w, "PAYLOAD_TOO_LARGE",
+					"request body must be at most "+strconv.FormatInt(maxErr.Limit, 10)+" bytes", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				utils.SendError(w, "INVALID_REQUEST", "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record, reserved, err := repo.Reserve(r.Context(), key, fingerprint(r, body), config.TTL, config.LockTimeout)
+			if err != nil {
+				utils.SendAppError(w, err)
+				return
+			}
+
+			if !reserved {
+				replay(w, r, body, record)
+				return
+			}
+
+			// response tetap disimpan walaupun client sudah memutus koneksi, justru itu kasus retry-nya
+			ctx := context.WithoutCancel(r.Context())
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if !completed {
+					// handler panic: lepas key supaya retry bisa diproses
+					if err := repo.Release(ctx, key); err != nil {
+						slog.ErrorContext(ctx, "idempotency: failed to release key", "key", key, "error", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			completed = true
+			if recorder.status >= http.StatusInternalServerError {
+				// error server tidak disimpan, retry berikutnya dijalankan ulang
+				if err := repo.Release(ctx, key); err != nil {
+					slog.ErrorContext(ctx, "idempotency: failed to release key", "key", key, "error", err)
+				}
+				return
+			}
+
+			headers := make(map[string]string)
+			for _, name := range replayHeaders {
+				if value := recorder.Header().Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+
+			if err := repo.Complete(ctx, key, recorder.status, headers, recorder.body.Bytes()); err != nil {
+				slog.ErrorContext(ctx, "idempotency: failed to store response", "key", key, "error", err)
+			}
+		})
+	}
+}
+
+// replay menjawab request ulang dari record yang tersimpan
+func replay(w http.ResponseWriter, r *http.Request, body []byte, record *models.IdempotencyRecord) {
+	if record.Fingerprint != fingerprint(r, body) {
+		utils.SendError(w, "IDEMPOTENCY_KEY_REUSED",
+			HeaderKey+" was already used for a different request", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !record.Completed() {
+		utils.SendError(w, "IDEMPOTENCY_IN_PROGRESS",
+			"a request with this "+HeaderKey+" is still being processed, retry later", http.StatusConflict)
+		return
+	}
+
+	for name, value := range record.Headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(*record.StatusCode)
+	w.Write(record.Body)
+}
+
+// fingerprint mengidentifikasi isi request: key yang sama untuk path, outlet atau body
+// yang berbeda berarti client salah memakai ulang key
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	io.WriteString(hash, r.Header.Get(outlet.HeaderOutletID)+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder meneruskan response ke client sambil menyalinnya untuk disimpan
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.wroteHeader {
+		return
+	}
+	rec.status = status
+	rec.wroteHeader = true
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
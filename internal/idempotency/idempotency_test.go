@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"bytes"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testConfig = Config{TTL: 24 * time.Hour, LockTimeout: time.Minute}
+
+func newRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewBufferString(body))
+	req.Header.Set(HeaderKey, key)
+	return req
+}
+
+func TestMiddleware_StoresFirstResponse(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":1}}`))
+	}))
+
+	req := newRequest("abc", `{"name":"Es Teh"}`)
+	repo.On("Reserve", mock.Anything, "abc", fingerprint(req, []byte(`{"name":"Es Teh"}`)), testConfig.TTL, testConfig.LockTimeout).
+		Return(nil, true, nil)
+	repo.On("Complete", mock.Anything, "abc", http.StatusCreated,
+		map[string]string{"Content-Type": "application/json"}, []byte(`{"data":{"id":1}}`)).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"data":{"id":1}}`, w.Body.String())
+	repo.AssertExpectations(t)
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called on replay")
+	}))
+
+	req := newRequest("abc", `{"name":"Es Teh"}`)
+	status := http.StatusCreated
+	repo.On("Reserve", mock.Anything, "abc", mock.Anything, mock.Anything, mock.Anything).Return(&models.IdempotencyRecord{
+		Key:         "abc",
+		Fingerprint: fingerprint(req, []byte(`{"name":"Es Teh"}`)),
+		StatusCode:  &status,
+		Headers:     map[string]string{"Content-Type": "application/json"},
+		Body:        []byte(`{"data":{"id":1}}`),
+	}, false, nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "true", w.Header().Get(HeaderReplayed))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"data":{"id":1}}`, w.Body.String())
+}
+
+func TestMiddleware_KeyReusedWithDifferentBody(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	status := http.StatusCreated
+	repo.On("Reserve", mock.Anything, "abc", mock.Anything, mock.Anything, mock.Anything).Return(&models.IdempotencyRecord{
+		Key:         "abc",
+		Fingerprint: fingerprint(newRequest("abc", ""), []byte(`{"name":"Es Teh"}`)),
+		StatusCode:  &status,
+	}, false, nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("abc", `{"name":"Kopi"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	req := newRequest("abc", `{}`)
+	repo.On("Reserve", mock.Anything, "abc", mock.Anything, mock.Anything, mock.Anything).Return(&models.IdempotencyRecord{
+		Key:         "abc",
+		Fingerprint: fingerprint(req, []byte(`{}`)),
+	}, false, nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	repo.On("Reserve", mock.Anything, "abc", mock.Anything, mock.Anything, mock.Anything).Return(nil, true, nil)
+	repo.On("Release", mock.Anything, "abc").Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("abc", `{}`))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMiddleware_PassesThroughWithoutKey(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	called := false
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/products", nil))
+
+	assert.True(t, called)
+	repo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMiddleware_BodyTooLarge(t *testing.T) {
+	repo := new(mocks.IdempotencyRepositoryMock)
+	handler := Middleware(repo, testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	}))
+
+	req := newRequest("abc", `{"name":"Es Teh Manis"}`)
+	w := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(w, req.Body, 8)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "PAYLOAD_TOO_LARGE")
+	repo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
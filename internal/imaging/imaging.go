@@ -0,0 +1,145 @@
+// Package imaging memvalidasi gambar upload dan membuat thumbnail hanya dengan library standar.
+package imaging
+
+import (
+	"bytes"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registrasi decoder GIF untuk image.Decode
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// MaxPixels membatasi resolusi gambar yang mau di-decode (sekitar 40 megapixel)
+// supaya file kecil yang mengaku berukuran raksasa tidak menghabiskan memori.
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedType = apperrors.New(apperrors.ErrUnsupported, "UNSUPPORTED_MEDIA_TYPE", "unsupported image type, use JPEG, PNG or GIF")
+	ErrTooManyPixels   = apperrors.New(apperrors.ErrValidation, "INVALID_IMAGE", "image resolution is too large")
+)
+
+// Image adalah hasil decode beserta format aslinya
+type Image struct {
+	image.Image
+	// ContentType adalah image/jpeg, image/png atau image/gif
+	ContentType string
+	// Ext adalah ekstensi file tanpa titik
+	Ext string
+}
+
+var formats = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// Decode mengecek tipe dari isi file (bukan dari nama file / header yang dikirim client),
+// mengecek resolusi lewat DecodeConfig, baru kemudian men-decode seluruh gambar.
+func Decode(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := formats[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	return &Image{Image: img, ContentType: contentType, Ext: ext}, nil
+}
+
+// Thumbnail mengecilkan gambar supaya sisi terpanjangnya = size, rasio tetap.
+// gambar yang sudah lebih kecil tidak diperbesar.
+func Thumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= size && h <= size {
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+		return dst
+	}
+
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	tw, th = max(tw, 1), max(th, 1)
+
+	return boxResize(src, tw, th)
+}
+
+// boxResize menghitung rata-rata semua pixel sumber yang jatuh ke setiap pixel tujuan.
+// hasilnya jauh lebih halus daripada nearest-neighbour untuk downscale.
+func boxResize(src image.Image, tw, th int) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+
+	for ty := 0; ty < th; ty++ {
+		y0 := bounds.Min.Y + ty*h/th
+		y1 := max(bounds.Min.Y+(ty+1)*h/th, y0+1)
+
+		for tx := 0; tx < tw; tx++ {
+			x0 := bounds.Min.X + tx*w/tw
+			x1 := max(bounds.Min.X+(tx+1)*w/tw, x0+1)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := src.At(x, y).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(tx, ty, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+// Encode menyimpan thumbnail sebagai PNG kalau sumbernya PNG (menjaga transparansi),
+// selain itu sebagai JPEG. mengembalikan data, content type dan ekstensi.
+func Encode(img image.Image, sourceType string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+
+	if sourceType == "image/png" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", "png", nil
+	}
+
+	// JPEG tidak punya alpha, tempel di atas latar putih dulu
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/jpeg", "jpg", nil
+}
@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 10, B: 10, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	img, err := Decode(encodePNG(t, 40, 20))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, "png", img.Ext)
+	assert.Equal(t, 40, img.Bounds().Dx())
+}
+
+func TestDecode_Unsupported(t *testing.T) {
+	_, err := Decode([]byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	// header PNG yang valid tapi isinya rusak
+	data := encodePNG(t, 10, 10)
+	_, err = Decode(data[:40])
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestThumbnail_KeepsAspectRatio(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	thumb := Thumbnail(src, 100)
+	assert.Equal(t, 100, thumb.Bounds().Dx())
+	assert.Equal(t, 50, thumb.Bounds().Dy())
+
+	tall := Thumbnail(image.NewRGBA(image.Rect(0, 0, 90, 300)), 150)
+	assert.Equal(t, 45, tall.Bounds().Dx())
+	assert.Equal(t, 150, tall.Bounds().Dy())
+}
+
+func TestThumbnail_NoUpscale(t *testing.T) {
+	thumb := Thumbnail(image.NewRGBA(image.Rect(0, 0, 60, 30)), 128)
+
+	assert.Equal(t, 60, thumb.Bounds().Dx())
+	assert.Equal(t, 30, thumb.Bounds().Dy())
+}
+
+func TestThumbnail_AveragesColor(t *testing.T) {
+	img, err := Decode(encodePNG(t, 64, 64))
+	assert.NoError(t, err)
+
+	thumb := Thumbnail(img, 8)
+	r, g, b, a := thumb.At(3, 3).RGBA()
+	assert.Equal(t, uint32(200), r>>8)
+	assert.Equal(t, uint32(10), g>>8)
+	assert.Equal(t, uint32(10), b>>8)
+	assert.Equal(t, uint32(255), a>>8)
+}
+
+func TestEncode(t *testing.T) {
+	thumb := Thumbnail(image.NewRGBA(image.Rect(0, 0, 20, 20)), 10)
+
+	data, contentType, ext, err := Encode(thumb, "image/jpeg")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+	assert.Equal(t, "jpg", ext)
+	_, err = jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	_, contentType, ext, err = Encode(thumb, "image/png")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "png", ext)
+}
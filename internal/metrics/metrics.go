@@ -0,0 +1,142 @@
+// Package metrics menyediakan endpoint /metrics (format Prometheus) berisi metrik HTTP,
+// connection pool database dan angka bisnis per outlet.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "kasir"
+
+// scrapeTimeout membatasi query metrik bisnis supaya scrape tidak ikut macet kalau database lambat
+const scrapeTimeout = 3 * time.Second
+
+// OutletMetricsSource dipenuhi oleh repositories.OutletRepositoryInterface
+type OutletMetricsSource interface {
+	GetMetrics(ctx context.Context) ([]models.OutletMetrics, error)
+}
+
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	panics   *prometheus.CounterVec
+}
+
+// New mendaftarkan semua metrik ke registry sendiri (bukan registry global) supaya
+// bisa dibuat berulang kali di test.
+func New(db *sql.DB, outlets OutletMetricsSource) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Jumlah request HTTP per route dan status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency request HTTP per route dan status.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"method", "route", "status"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_panics_total",
+			Help:      "Jumlah panic di handler yang ditangkap middleware.Recover per route.",
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.panics,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	if db != nil {
+		// go_sql_open_connections, go_sql_in_use_connections, go_sql_wait_count_total, ...
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, "kasir"))
+	}
+
+	if outlets != nil {
+		m.registry.MustRegister(&outletCollector{source: outlets})
+	}
+
+	return m
+}
+
+// Handler melayani GET /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest dipanggil oleh middleware.Metrics setelah setiap request selesai
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.duration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObservePanic dipanggil oleh middleware.Recover setiap kali panic ditangkap
+func (m *Metrics) ObservePanic(method, route string) {
+	m.panics.WithLabelValues(method, route).Inc()
+}
+
+var (
+	outOfStockDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "products_out_of_stock"),
+		"Jumlah produk yang stoknya habis per outlet.",
+		[]string{"outlet"}, nil,
+	)
+	openDraftOrdersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "draft_orders_open"),
+		"Jumlah open tab (draft order) yang belum dibayar per outlet.",
+		[]string{"outlet"}, nil,
+	)
+	businessUpDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "business_metrics_up"),
+		"1 kalau metrik bisnis berhasil dibaca dari database saat scrape terakhir.",
+		nil, nil,
+	)
+)
+
+// outletCollector membaca angka bisnis dari database setiap kali /metrics di-scrape,
+// jadi nilainya selalu sama dengan data terbaru tanpa perlu di-update dari service
+type outletCollector struct {
+	source OutletMetricsSource
+}
+
+func (c *outletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- outOfStockDesc
+	ch <- openDraftOrdersDesc
+	ch <- businessUpDesc
+}
+
+func (c *outletCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	outlets, err := c.source.GetMetrics(ctx)
+	if err != nil {
+		slog.Warn("failed to collect business metrics", "error", err)
+		ch <- prometheus.MustNewConstMetric(businessUpDesc, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(businessUpDesc, prometheus.GaugeValue, 1)
+	for _, outlet := range outlets {
+		ch <- prometheus.MustNewConstMetric(outOfStockDesc, prometheus.GaugeValue, float64(outlet.ProductsOutOfStock), outlet.OutletCode)
+		ch <- prometheus.MustNewConstMetric(openDraftOrdersDesc, prometheus.GaugeValue, float64(outlet.OpenDraftOrders), outlet.OutletCode)
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOutlets struct {
+	metrics []models.OutletMetrics
+	err     error
+}
+
+func (f fakeOutlets) GetMetrics(ctx context.Context) ([]models.OutletMetrics, error) {
+	return f.metrics, f.err
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body, _ := io.ReadAll(w.Body)
+	return string(body)
+}
+
+func TestMetrics_Requests(t *testing.T) {
+	m := New(nil, nil)
+	m.ObserveRequest(http.MethodGet, "GET /api/v1/products/{id}", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "GET /api/v1/products/{id}", http.StatusOK, 30*time.Millisecond)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `kasir_http_requests_total{method="GET",route="GET /api/v1/products/{id}",status="200"} 2`)
+	assert.Contains(t, body, `kasir_http_request_duration_seconds_count{method="GET",route="GET /api/v1/products/{id}",status="200"} 2`)
+}
+
+func TestMetrics_Panics(t *testing.T) {
+	m := New(nil, nil)
+	m.ObservePanic(http.MethodPost, "POST /api/v1/products")
+
+	assert.Contains(t, scrape(t, m), `kasir_http_panics_total{method="POST",route="POST /api/v1/products"} 1`)
+}
+
+func TestMetrics_DBStats(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	body := scrape(t, New(db, nil))
+	assert.Contains(t, body, `go_sql_open_connections{db_name="kasir"}`)
+	assert.Contains(t, body, `go_sql_in_use_connections{db_name="kasir"}`)
+	assert.Contains(t, body, `go_sql_wait_count_total{db_name="kasir"}`)
+	assert.Contains(t, body, `go_sql_wait_duration_seconds_total{db_name="kasir"}`)
+}
+
+func TestMetrics_Business(t *testing.T) {
+	m := New(nil, fakeOutlets{metrics: []models.OutletMetrics{
+		{OutletID: 1, OutletCode: "PST", ProductsOutOfStock: 3, OpenDraftOrders: 2},
+	}})
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `kasir_business_metrics_up 1`)
+	assert.Contains(t, body, `kasir_products_out_of_stock{outlet="PST"} 3`)
+	assert.Contains(t, body, `kasir_draft_orders_open{outlet="PST"} 2`)
+}
+
+func TestMetrics_BusinessError(t *testing.T) {
+	m := New(nil, fakeOutlets{err: errors.New("db down")})
+
+	// scrape tetap berhasil, metrik lain tidak ikut hilang
+	body := scrape(t, m)
+	assert.Contains(t, body, `kasir_business_metrics_up 0`)
+	assert.NotContains(t, body, `kasir_products_out_of_stock{`)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+)
+
+// HeaderAPIKey dikirim oleh aplikasi kasir kalau AUTH_API_KEYS diisi
+const HeaderAPIKey = "X-API-Key"
+
+type apiKeyContextKey struct{}
+
+// APIKey menolak request tanpa X-API-Key yang terdaftar dengan 401. key yang valid ditandai di
+// context (lihat APIKeyID) supaya rate limit dihitung per key. kalau keys kosong semua request
+// diteruskan (API terbuka) dan header X-API-Key diabaikan.
+func APIKey(keys []string) func(http.Handler) http.Handler {
+	// dibandingkan dalam bentuk hash supaya perbandingan constant-time tidak bocor lewat panjang key
+	hashes := make([][32]byte, 0, len(keys))
+	for _, key := range keys {
+		hashes = append(hashes, sha256.Sum256([]byte(key)))
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(hashes) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hash, ok := validAPIKey(hashes, r.Header.Get(HeaderAPIKey))
+			if !ok {
+				utils.SendError(w, "UNAUTHORIZED", "missing or invalid "+HeaderAPIKey+" header", http.StatusUnauthorized)
+				return
+			}
+			// key tidak disimpan apa adanya di context, cukup sidik jarinya
+			id := hex.EncodeToString(hash[:8])
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, id)))
+		})
+	}
+}
+
+// APIKeyID mengembalikan sidik jari X-API-Key yang sudah divalidasi APIKey, ok bernilai false
+// kalau request tidak melewati APIKey atau API key tidak diaktifkan
+func APIKeyID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(apiKeyContextKey{}).(string)
+	return id, ok
+}
+
+func validAPIKey(hashes [][32]byte, key string) ([32]byte, bool) {
+	if key == "" {
+		return [32]byte{}, false
+	}
+	hash := sha256.Sum256([]byte(key))
+	valid := 0
+	for _, h := range hashes {
+		valid |= subtle.ConstantTimeCompare(hash[:], h[:])
+	}
+	return hash, valid == 1
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey(t *testing.T) {
+	var keyID string
+	handler := APIKey([]string{"key-1", "key-2"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyID, _ = APIKeyID(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"valid", "key-2", http.StatusNoContent},
+		{"missing", "", http.StatusUnauthorized},
+		{"unknown", "key-3", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyID = ""
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusNoContent {
+				assert.Regexp(t, `^[0-9a-f]{16}$`, keyID)
+			}
+		})
+	}
+}
+
+func TestAPIKey_DistinctIDs(t *testing.T) {
+	ids := map[string]bool{}
+	handler := APIKey([]string{"key-1", "key-2"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := APIKeyID(r.Context())
+		assert.True(t, ok)
+		ids[id] = true
+	}))
+
+	for _, key := range []string{"key-1", "key-2", "key-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+		req.Header.Set(HeaderAPIKey, key)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Len(t, ids, 2)
+}
+
+func TestAPIKey_Disabled(t *testing.T) {
+	var marked bool
+	handler := APIKey(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, marked = APIKeyID(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// tanpa AUTH_API_KEYS header dari client tidak dipercaya
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+	req.Header.Set(HeaderAPIKey, "anything")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.False(t, marked)
+}
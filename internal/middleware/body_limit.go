@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+	"strconv"
+)
+
+// BodyLimitConfig batas ukuran body request dalam byte
+type BodyLimitConfig struct {
+	// Default untuk semua route di group, 0 berarti tidak dibatasi
+	Default int64
+	// Routes batas khusus per pola route (r.Pattern), misalnya upload gambar yang lebih besar
+	Routes map[string]int64
+}
+
+// BodyLimit membatasi body request dengan http.MaxBytesReader. request yang Content-Length-nya
+// sudah melebihi batas langsung ditolak 413 PAYLOAD_TOO_LARGE; body chunked yang ternyata lebih
+// besar gagal dibaca dengan *http.MaxBytesError (utils.DecodeJSON mengubahnya menjadi 413).
+// dipasang di group route supaya r.Pattern sudah terisi.
+func BodyLimit(config BodyLimitConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit, ok := config.Routes[r.Pattern]
+			if !ok {
+				limit = config.Default
+			}
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				utils.SendError(w, "PAYLOAD_TOO_LARGE",
+					"request body must be at most "+strconv.FormatInt(limit, 10)+" bytes", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
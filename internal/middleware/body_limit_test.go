@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bodyLimitHandler() http.Handler {
+	limit := BodyLimit(BodyLimitConfig{
+		Default: 16,
+		Routes:  map[string]int64{"POST /upload": 64},
+	})
+	decode := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := utils.DecodeJSON(r, &body); err != nil {
+			utils.SendAppError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /products", limit(decode))
+	mux.Handle("POST /upload", limit(decode))
+	return mux
+}
+
+func TestBodyLimit(t *testing.T) {
+	large := `{"name":"` + strings.Repeat("a", 30) + `"}`
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		status  int
+	}{
+		{"within limit", "/products", `{"name":"kopi"}`, false, http.StatusNoContent},
+		{"content length too large", "/products", large, false, http.StatusRequestEntityTooLarge},
+		{"chunked too large", "/products", large, true, http.StatusRequestEntityTooLarge},
+		{"route limit", "/upload", large, false, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.chunked {
+				// tanpa Content-Length, batas baru ketahuan saat body dibaca
+				body = io.MultiReader(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, tt.path, body)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			bodyLimitHandler().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, w.Body.String(), `"code":"PAYLOAD_TOO_LARGE"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins origin lengkap seperti https://kasir.example.com, atau "*" untuk semua origin.
+	// kosong berarti CORS mati dan browser di origin lain tidak bisa memanggil API.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders header response yang boleh dibaca JavaScript (ETag, Location, ...)
+	ExposedHeaders []string
+	// AllowCredentials mengizinkan cookie/Authorization, tidak bisa digabung dengan origin "*"
+	AllowCredentials bool
+	// MaxAgeSeconds lama browser boleh menyimpan hasil preflight
+	MaxAgeSeconds int
+}
+
+// CORS menambahkan header CORS untuk origin yang diizinkan dan menjawab preflight
+// (OPTIONS dengan Access-Control-Request-Method) langsung tanpa meneruskan ke handler.
+// origin yang tidak diizinkan tetap diteruskan tanpa header CORS, browser yang akan memblokir.
+func CORS(config CORSConfig) func(http.Handler) http.Handler {
+	anyOrigin := slices.Contains(config.AllowedOrigins, "*")
+	methods := strings.Join(config.AllowedMethods, ", ")
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+	allowedHeaders := make(map[string]bool, len(config.AllowedHeaders))
+	for _, h := range config.AllowedHeaders {
+		allowedHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(config.AllowedOrigins) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			// response berbeda per origin, cache (CDN/proxy) harus membedakannya
+			w.Header().Add("Vary", "Origin")
+
+			if origin == "" || !(anyOrigin || slices.Contains(config.AllowedOrigins, origin)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin && !config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			preflightMethod := r.Header.Get("Access-Control-Request-Method")
+			if r.Method != http.MethodOptions || preflightMethod == "" {
+				if exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// preflight
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if slices.Contains(config.AllowedMethods, preflightMethod) && headersAllowed(allowedHeaders, r.Header.Get("Access-Control-Request-Headers")) {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+					w.Header().Set("Access-Control-Allow-Headers", requested)
+				}
+				if config.MaxAgeSeconds > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAgeSeconds))
+				}
+			}
+			// tanpa Access-Control-Allow-Methods browser menganggap preflight gagal
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func headersAllowed(allowed map[string]bool, requested string) bool {
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		if h != "" && !allowed[http.CanonicalHeaderKey(h)] {
+			return false
+		}
+	}
+	return true
+}
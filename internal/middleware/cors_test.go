@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCORS = CORSConfig{
+	AllowedOrigins: []string{"https://kasir.example.com"},
+	AllowedMethods: []string{"GET", "POST", "PUT"},
+	AllowedHeaders: []string{"Content-Type", "X-Outlet-ID", "If-Match"},
+	ExposedHeaders: []string{"ETag", "X-Request-ID"},
+	MaxAgeSeconds:  600,
+}
+
+func corsHandler(config CORSConfig) http.Handler {
+	return CORS(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+}
+
+func TestCORS_SimpleRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+	req.Header.Set("Origin", "https://kasir.example.com")
+	w := httptest.NewRecorder()
+	corsHandler(testCORS).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "https://kasir.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "ETag, X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+}
+
+func TestCORS_UnknownOrigin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+	corsHandler(testCORS).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/products/1", nil)
+	req.Header.Set("Origin", "https://kasir.example.com")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	req.Header.Set("Access-Control-Request-Headers", "content-type, x-outlet-id")
+	w := httptest.NewRecorder()
+	corsHandler(testCORS).ServeHTTP(w, req)
+
+	// dijawab langsung, tidak sampai ke handler
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://kasir.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST, PUT", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "content-type, x-outlet-id", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORS_PreflightRejected(t *testing.T) {
+	tests := []struct {
+		name, method, headers string
+	}{
+		{"method", "DELETE", ""},
+		{"header", "POST", "X-Unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/v1/products", nil)
+			req.Header.Set("Origin", "https://kasir.example.com")
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			w := httptest.NewRecorder()
+			corsHandler(testCORS).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
+func TestCORS_WildcardAndCredentials(t *testing.T) {
+	config := testCORS
+	config.AllowedOrigins = []string{"*"}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	w := httptest.NewRecorder()
+	corsHandler(config).ServeHTTP(w, req)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	config.AllowedOrigins = []string{"https://kasir.example.com"}
+	config.AllowCredentials = true
+	req.Header.Set("Origin", "https://kasir.example.com")
+	w = httptest.NewRecorder()
+	corsHandler(config).ServeHTTP(w, req)
+	assert.Equal(t, "https://kasir.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_Disabled(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://kasir.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	corsHandler(CORSConfig{}).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
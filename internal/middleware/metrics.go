@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestObserver dipenuhi oleh *metrics.Metrics
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// Metrics mencatat jumlah dan latency request per pola route (bukan path asli,
+// supaya /products/1 dan /products/2 masuk ke label yang sama)
+func Metrics(observer RequestObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			observer.ObserveRequest(r.Method, routeOf(r), rec.status, time.Since(start))
+		})
+	}
+}
+
+// routeOf mengambil pola route yang diisi ServeMux, "unmatched" kalau tidak ada yang cocok
+func routeOf(r *http.Request) string {
+	if r.Pattern == "" {
+		return "unmatched"
+	}
+	return r.Pattern
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type observation struct {
+	method, route string
+	status        int
+}
+
+type fakeObserver struct {
+	observed []observation
+}
+
+func (f *fakeObserver) ObserveRequest(method, route string, status int, duration time.Duration) {
+	f.observed = append(f.observed, observation{method, route, status})
+}
+
+func TestMetrics_UsesRoutePattern(t *testing.T) {
+	observer := &fakeObserver{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := Metrics(observer)(mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	assert.Equal(t, []observation{
+		{http.MethodGet, "GET /api/v1/products/{id}", http.StatusNotFound},
+		{http.MethodGet, "unmatched", http.StatusNotFound},
+	}, observer.observed)
+}
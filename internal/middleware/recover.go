@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicObserver dipenuhi oleh *metrics.Metrics
+type PanicObserver interface {
+	ObservePanic(method, route string)
+}
+
+// Recover menangkap panic dari handler (misalnya nil dereference) supaya koneksi tidak diputus:
+// panic dicatat di log bersama stack dan request_id, metrik panic bertambah, dan client
+// menerima 500 INTERNAL_ERROR. dipasang di dalam AccessLog dan Metrics supaya status 500 ikut tercatat.
+func Recover(logger *slog.Logger, observer PanicObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					// sengaja dipakai handler untuk memutus koneksi, bukan bug
+					panic(v)
+				}
+
+				route := routeOf(r)
+				logger.ErrorContext(r.Context(), "panic recovered",
+					"method", r.Method,
+					"route", route,
+					"path", r.URL.Path,
+					"panic", fmt.Sprint(v),
+					"stack", string(debug.Stack()),
+				)
+				observer.ObservePanic(r.Method, route)
+
+				if rec.wroteHeader {
+					// sebagian response sudah terkirim, yang bisa dilakukan hanya memutus koneksi
+					panic(http.ErrAbortHandler)
+				}
+				utils.SendError(rec, "INTERNAL_ERROR", "internal server error", http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
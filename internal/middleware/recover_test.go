@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/logging"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePanicObserver struct {
+	routes []string
+}
+
+func (f *fakePanicObserver) ObservePanic(method, route string) {
+	f.routes = append(f.routes, route)
+}
+
+type product struct{ Name string }
+
+func TestRecover(t *testing.T) {
+	var logs bytes.Buffer
+	// logger dari logging.New menambahkan request_id dari context
+	logger, err := logging.New(&logs, "info", "json")
+	require.NoError(t, err)
+	observer := &fakePanicObserver{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var p *product
+		w.Write([]byte(p.Name)) // nil dereference
+	})
+	handler := RequestID(Recover(logger, observer)(mux))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil)
+	req.Header.Set(utils.HeaderRequestID, "req-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var body utils.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "INTERNAL_ERROR", body.Error.Code)
+	assert.Equal(t, "req-123", body.Error.RequestID)
+
+	assert.Equal(t, []string{"GET /api/v1/products/{id}"}, observer.routes)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "panic recovered", entry["msg"])
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Contains(t, entry["panic"], "nil pointer dereference")
+	assert.Contains(t, entry["stack"], "recover_test.go")
+}
+
+func TestRecover_AfterResponseStarted(t *testing.T) {
+	observer := &fakePanicObserver{}
+	handler := Recover(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)), observer)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("boom")
+		}))
+
+	// response sudah mulai terkirim, koneksi diputus lewat http.ErrAbortHandler
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.Len(t, observer.routes, 1)
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	observer := &fakePanicObserver{}
+	handler := Recover(slog.Default(), observer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, observer.routes)
+}
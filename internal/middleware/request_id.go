@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fajar7xx/go-kasir-umam-ds/internal/logging"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+)
+
+// maxRequestIDLength membatasi id dari client supaya log tidak bisa diisi string sembarang panjang
+const maxRequestIDLength = 128
+
+// RequestID memakai X-Request-ID dari client (misalnya dari load balancer atau aplikasi kasir)
+// atau membuat id baru. id dikirim balik di header response, disimpan di context untuk log,
+// dan ikut di body error dari utils.SendError.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(utils.HeaderRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(utils.HeaderRequestID, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID hanya menerima huruf, angka dan - _ . : supaya aman ditulis ke log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/logging"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID_Generates(t *testing.T) {
+	var fromContext string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromContext = logging.RequestID(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Len(t, fromContext, 32)
+	assert.Equal(t, fromContext, w.Header().Get(utils.HeaderRequestID))
+}
+
+func TestRequestID_PropagatesValidID(t *testing.T) {
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(utils.HeaderRequestID, "till-3:abc-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "till-3:abc-123", w.Header().Get(utils.HeaderRequestID))
+}
+
+func TestRequestID_ReplacesInvalidID(t *testing.T) {
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(utils.HeaderRequestID, "bad id\nwith newline")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.NotEqual(t, "bad id\nwith newline", w.Header().Get(utils.HeaderRequestID))
+	assert.Len(t, w.Header().Get(utils.HeaderRequestID), 32)
+}
+
+func TestRequestID_IncludedInErrorBody(t *testing.T) {
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.SendError(w, "PRODUCT_NOT_FOUND", "product not found", http.StatusNotFound)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(utils.HeaderRequestID, "req-42")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.JSONEq(t, `{"error":{"code":"PRODUCT_NOT_FOUND","message":"product not found","request_id":"req-42"}}`, w.Body.String())
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", "json")
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("missing"))
+	})
+	handler := RequestID(AccessLog(logger)(mux))
+
+	req := httptest.NewRequest(http.MethodGet, "/products/7", nil)
+	req.Header.Set(utils.HeaderRequestID, "req-7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "GET /products/{id}", entry["route"])
+	assert.Equal(t, "/products/7", entry["path"])
+	assert.Equal(t, float64(404), entry["status"])
+	assert.Equal(t, float64(7), entry["bytes"])
+	assert.Equal(t, "req-7", entry["request_id"])
+	assert.Contains(t, entry, "latency_ms")
+}
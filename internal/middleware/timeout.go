@@ -0,0 +1,66 @@
+// Package middleware berisi middleware HTTP yang dipasang untuk semua route di main.go
+package middleware
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+	"time"
+)
+
+// Timeout memberi batas waktu ke context setiap request. handler dan repository cukup memakai
+// r.Context(): query dibatalkan saat batas waktu habis atau client memutus koneksi.
+//
+// response dibuat konsisten: kalau batas waktu sudah habis dan handler mengirim error 5xx
+// (atau tidak mengirim apa pun), client selalu menerima 504 TIMEOUT.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			if !tw.wroteHeader && ctx.Err() == context.DeadlineExceeded {
+				tw.WriteHeader(http.StatusGatewayTimeout)
+			}
+		})
+	}
+}
+
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+
+	if status >= http.StatusInternalServerError && tw.ctx.Err() == context.DeadlineExceeded {
+		// error yang muncul karena query dibatalkan diganti dengan 504, body dari handler dibuang
+		tw.timedOut = true
+		utils.SendError(tw.ResponseWriter, "TIMEOUT", "Request timeout", http.StatusGatewayTimeout)
+		return
+	}
+
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.WriteHeader(http.StatusOK)
+	if tw.timedOut {
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap dipakai http.ResponseController (misalnya untuk Flush)
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing membuat span server untuk setiap request. header traceparent dari client
+// (W3C trace-context) dipakai sebagai parent, jadi trace bisa menyambung dari aplikasi kasir.
+// nama span memakai pola route (misalnya "GET /api/v1/products/{id}") setelah ServeMux memilih route.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(rec, r)
+
+		// r.Pattern diisi ServeMux pada request yang sama (salinan dari WithContext di atas).
+		// http.route hanya berisi path, method di pola seperti "GET /metrics" dibuang
+		route := routeOf(r)
+		if _, path, ok := strings.Cut(route, " "); ok {
+			route = path
+		}
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(rec.status),
+		)
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
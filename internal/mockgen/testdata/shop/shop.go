@@ -0,0 +1,19 @@
+package shop
+
+import (
+	"context"
+	"io"
+)
+
+type Item struct{ ID int }
+
+type Items []Item
+
+type Filter func(Item) bool
+
+type Store interface {
+	Find(ctx context.Context, ids ...int) (Items, error)
+	Split(Item, Filter) (Items, Items)
+	Export(ctx context.Context, args map[string]string, w io.Writer) (int64, bool, error)
+	Reset()
+}
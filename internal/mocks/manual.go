@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+// method di file ini dilewati oleh internal/mockgen (lihat Spec.Manual)
+
+// Checkout menjalankan build dengan draft order yang disiapkan di argumen ke-0 (kalau ada),
+// sehingga perhitungan sale di service ikut teruji.
+func (m *DraftOrderRepositoryMock) Checkout(ctx context.Context, id int, build repositories.BuildSaleFunc) (*models.Sale, error) {
+	args := m.Called(ctx, id, build)
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+	order, ok := args.Get(0).(*models.DraftOrder)
+	if !ok {
+		return nil, nil
+	}
+	return build(order)
+}
+
+// URL tidak perlu diset lewat On, hasilnya selalu "/media/" + key
+func (m *BlobStoreMock) URL(key string) string {
+	return "/media/" + key
+}
+
+// WithinTx langsung menjalankan fn tanpa transaksi (tx nil), tidak perlu diset lewat On
+func (m *TxManagerMock) WithinTx(ctx context.Context, fn func(tx repositories.DBTX) error) error {
+	return fn(nil)
+}
+
+// WithTx mengembalikan mock yang sama, expectation yang diset lewat On tetap berlaku di dalam transaksi
+func (m *DraftOrderRepositoryMock) WithTx(tx repositories.DBTX) repositories.DraftOrderRepositoryInterface {
+	return m
+}
+
+// WithTx mengembalikan mock yang sama, lihat DraftOrderRepositoryMock.WithTx
+func (m *LoyaltyRepositoryMock) WithTx(tx repositories.DBTX) repositories.LoyaltyRepositoryInterface {
+	return m
+}
@@ -0,0 +1,6 @@
+// Package mocks berisi mock testify untuk interface repository, service dan storage.
+// file *_mock.go dibuat ulang dengan `go generate ./internal/mocks` setiap kali interface berubah,
+// method yang butuh perilaku khusus ditulis tangan di manual.go
+package mocks
+
+//go:generate go run ../mockgen
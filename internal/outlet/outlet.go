@@ -0,0 +1,87 @@
+// Package outlet membawa id outlet (cabang) yang sedang dilayani dari request sampai ke repository.
+package outlet
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+	"strconv"
+)
+
+// HeaderOutletID adalah header yang dikirim aplikasi kasir untuk menandai outlet-nya
+const HeaderOutletID = utils.HeaderOutletID
+
+// ErrMissingOutlet dikembalikan repository yang datanya per outlet ketika context tidak membawa outlet
+var ErrMissingOutlet = apperrors.New(apperrors.ErrValidation, "OUTLET_REQUIRED", "outlet ID is required")
+
+type contextKey struct{}
+
+// WithID menyimpan id outlet ke context. dipakai oleh Middleware, dan bisa juga dipakai
+// oleh lapisan auth yang membaca outlet dari claim token.
+func WithID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// IDFromContext mengambil id outlet dari context, ok bernilai false kalau tidak ada
+func IDFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(contextKey{}).(int)
+	return id, ok
+}
+
+// RequireID sama seperti IDFromContext tapi mengembalikan ErrMissingOutlet kalau tidak ada
+func RequireID(ctx context.Context) (int, error) {
+	id, ok := IDFromContext(ctx)
+	if !ok {
+		return 0, ErrMissingOutlet
+	}
+	return id, nil
+}
+
+// Middleware membaca header X-Outlet-ID (kalau ada) dan menaruhnya di context request.
+// outlet yang sudah ada di context (misalnya dari token) tidak ditimpa.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, ok := withHeader(w, r)
+		if !ok {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require seperti Middleware, tapi menolak request yang tidak membawa outlet sama sekali
+func Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, ok := withHeader(w, r)
+		if !ok {
+			return
+		}
+
+		if _, ok := IDFromContext(r.Context()); !ok {
+			utils.SendError(w, "OUTLET_REQUIRED", HeaderOutletID+" header is required", http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func withHeader(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if _, ok := IDFromContext(r.Context()); ok {
+		return r, true
+	}
+
+	value := r.Header.Get(HeaderOutletID)
+	if value == "" {
+		return r, true
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		utils.SendError(w, "INVALID_OUTLET", "invalid "+HeaderOutletID+" header", http.StatusBadRequest)
+		return r, false
+	}
+
+	return r.WithContext(WithID(r.Context(), id)), true
+}
@@ -0,0 +1,206 @@
+// Package ratelimit membatasi jumlah request per client dengan token bucket, supaya aplikasi
+// kasir yang bug (misalnya retry tanpa jeda) tidak membanjiri database.
+package ratelimit
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/middleware"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sweepInterval jarak minimal antar pembersihan bucket client yang sudah lama tidak aktif
+const sweepInterval = time.Minute
+
+// defaultMaxBuckets dipakai kalau Config.MaxBuckets 0
+const defaultMaxBuckets = 10000
+
+// Rate adalah budget satu bucket: Requests per Per, dengan Burst request boleh dikirim sekaligus.
+// Requests 0 berarti tidak dibatasi.
+type Rate struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// PerMinute membuat Rate n request per menit dengan burst sebanyak jatah 10 detik (minimal 1)
+func PerMinute(n int) Rate {
+	return Rate{Requests: n, Per: time.Minute, Burst: max(n/6, 1)}
+}
+
+func (rate Rate) unlimited() bool {
+	return rate.Requests <= 0 || rate.Per <= 0
+}
+
+// tokens per detik
+func (rate Rate) perSecond() float64 {
+	return float64(rate.Requests) / rate.Per.Seconds()
+}
+
+type Config struct {
+	// Read untuk GET dan HEAD, Write untuk method lain
+	Read  Rate
+	Write Rate
+	// Routes budget khusus per pola route (r.Pattern), misalnya "POST /api/v1/products/{id}/images".
+	// setiap route di sini punya bucket sendiri, tidak mengurangi jatah Read/Write.
+	Routes map[string]Rate
+	// MaxBuckets batas jumlah bucket di memory. kalau penuh, bucket yang paling lama tidak
+	// dipakai dibuang supaya client yang terus berganti IP tidak menghabiskan memory
+	MaxBuckets int
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // waktu bucket penuh lagi kalau tidak ada request
+}
+
+// Limiter menyimpan bucket per client per budget di memory. cukup untuk satu instance,
+// kalau API dijalankan beberapa instance setiap instance punya jatah sendiri.
+type Limiter struct {
+	config Config
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func New(config Config) *Limiter {
+	return &Limiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow mengambil satu token dari bucket key. kalau habis, dikembalikan lama menunggu
+// sampai token berikutnya tersedia.
+func (l *Limiter) Allow(key string, rate Rate) (bool, time.Duration) {
+	if rate.unlimited() {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	burst := float64(max(rate.Burst, 1))
+	b, ok := l.buckets[key]
+	if !ok {
+		l.makeRoom(now)
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate.perSecond())
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate.perSecond() * float64(time.Second))
+		return false, wait
+	}
+
+	b.tokens--
+	b.full = now.Add(time.Duration((burst - b.tokens) / rate.perSecond() * float64(time.Second)))
+	return true, 0
+}
+
+// sweep membuang bucket yang sudah penuh lagi, hasilnya sama dengan bucket baru
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// makeRoom membuang bucket sebelum bucket baru ditambahkan kalau jumlahnya sudah di batas:
+// pertama yang sudah penuh lagi, kalau masih di batas yang paling lama tidak dipakai
+func (l *Limiter) makeRoom(now time.Time) {
+	limit := l.config.MaxBuckets
+	if limit <= 0 {
+		limit = defaultMaxBuckets
+	}
+	if len(l.buckets) < limit {
+		return
+	}
+
+	l.lastSweep = time.Time{}
+	l.sweep(now)
+	if len(l.buckets) < limit {
+		return
+	}
+
+	var oldestKey string
+	var oldest time.Time
+	for key, b := range l.buckets {
+		if oldestKey == "" || b.last.Before(oldest) {
+			oldestKey, oldest = key, b.last
+		}
+	}
+	delete(l.buckets, oldestKey)
+}
+
+// budget memilih Rate untuk request dan nama bucket-nya
+func (l *Limiter) budget(r *http.Request) (string, Rate) {
+	if rate, ok := l.config.Routes[r.Pattern]; ok {
+		return r.Pattern, rate
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return "read", l.config.Read
+	}
+	return "write", l.config.Write
+}
+
+// Middleware menolak request dengan 429 RATE_LIMITED dan header Retry-After kalau budget
+// client untuk route tersebut habis. dipasang di group route supaya r.Pattern sudah terisi.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, rate := l.budget(r)
+		allowed, wait := l.Allow(ClientKey(r)+" "+name, rate)
+		if !allowed {
+			seconds := strconv.Itoa(int(math.Ceil(wait.Seconds())))
+			w.Header().Set("Retry-After", seconds)
+			utils.SendError(w, "RATE_LIMITED", "too many requests, retry after "+seconds+" seconds", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type contextKey struct{}
+
+// WithClient menandai client yang sudah dikenali (misalnya user dari token login) supaya
+// budget dihitung per user, bukan per API key atau IP yang bisa dipakai bersama beberapa kasir.
+func WithClient(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ClientKey mengidentifikasi pemilik bucket: user dari context, lalu API key yang sudah divalidasi
+// middleware.APIKey, lalu IP client. header X-API-Key mentah tidak dipakai, client bisa
+// mengirim key acak di setiap request untuk mendapat bucket baru
+func ClientKey(r *http.Request) string {
+	if id, ok := r.Context().Value(contextKey{}).(string); ok && id != "" {
+		return "user:" + id
+	}
+	if id, ok := middleware.APIKeyID(r.Context()); ok {
+		return "key:" + id
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestLimiter(config Config) (*Limiter, *clock) {
+	c := &clock{t: time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)}
+	l := New(config)
+	l.now = c.now
+	return l, c
+}
+
+func TestAllow_RefillsOverTime(t *testing.T) {
+	l, c := newTestLimiter(Config{})
+	rate := Rate{Requests: 60, Per: time.Minute, Burst: 2}
+
+	ok, _ := l.Allow("a", rate)
+	assert.True(t, ok)
+	ok, _ = l.Allow("a", rate)
+	assert.True(t, ok)
+
+	ok, wait := l.Allow("a", rate)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// client lain punya bucket sendiri
+	ok, _ = l.Allow("b", rate)
+	assert.True(t, ok)
+
+	c.t = c.t.Add(time.Second)
+	ok, _ = l.Allow("a", rate)
+	assert.True(t, ok)
+}
+
+func TestAllow_Unlimited(t *testing.T) {
+	l, _ := newTestLimiter(Config{})
+	for i := 0; i < 100; i++ {
+		ok, _ := l.Allow("a", Rate{})
+		assert.True(t, ok)
+	}
+}
+
+func TestAllow_SweepsIdleBuckets(t *testing.T) {
+	l, c := newTestLimiter(Config{})
+	l.Allow("a", PerMinute(60))
+	assert.Len(t, l.buckets, 1)
+
+	c.t = c.t.Add(2 * sweepInterval)
+	l.Allow("b", PerMinute(60))
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestAllow_MaxBuckets(t *testing.T) {
+	l, c := newTestLimiter(Config{MaxBuckets: 2})
+	rate := Rate{Requests: 60, Per: time.Minute, Burst: 1}
+
+	l.Allow("a", rate)
+	c.t = c.t.Add(time.Millisecond)
+	l.Allow("b", rate)
+	c.t = c.t.Add(time.Millisecond)
+	l.Allow("c", rate)
+
+	// belum ada yang penuh lagi, jadi yang paling lama tidak dipakai dibuang
+	assert.Len(t, l.buckets, 2)
+	assert.NotContains(t, l.buckets, "a")
+	assert.Contains(t, l.buckets, "c")
+
+	// bucket yang sudah penuh lagi dibuang lebih dulu
+	c.t = c.t.Add(2 * time.Second)
+	l.Allow("d", rate)
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestMiddleware(t *testing.T) {
+	upload := "POST /api/v1/products/{id}/images"
+	l, _ := newTestLimiter(Config{
+		Read:   Rate{Requests: 60, Per: time.Minute, Burst: 2},
+		Write:  Rate{Requests: 60, Per: time.Minute, Burst: 1},
+		Routes: map[string]Rate{upload: {Requests: 6, Per: time.Minute, Burst: 1}},
+	})
+	mux := http.NewServeMux()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	mux.Handle("GET /api/v1/products", l.Middleware(ok))
+	mux.Handle("POST /api/v1/products", l.Middleware(ok))
+	mux.Handle(upload, l.Middleware(ok))
+	// route dengan API key aktif, seperti group /api/v1 di main
+	mux.Handle("GET /api/v1/categories", middleware.APIKey([]string{"key-1"})(l.Middleware(ok)))
+
+	send := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.7:51234"
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("GET", "/api/v1/products", "").Code)
+	assert.Equal(t, http.StatusNoContent, send("GET", "/api/v1/products", "").Code)
+	// budget write dan upload terpisah dari read
+	assert.Equal(t, http.StatusNoContent, send("POST", "/api/v1/products", "").Code)
+	assert.Equal(t, http.StatusNoContent, send("POST", "/api/v1/products/1/images", "").Code)
+
+	w := send("GET", "/api/v1/products", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"RATE_LIMITED"`)
+
+	w = send("POST", "/api/v1/products/2/images", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	// X-API-Key yang tidak divalidasi tidak memberi bucket baru
+	assert.Equal(t, http.StatusTooManyRequests, send("GET", "/api/v1/products", "random-1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send("GET", "/api/v1/products", "random-2").Code)
+
+	// IP yang sama dengan API key valid dihitung terpisah
+	assert.Equal(t, http.StatusNoContent, send("GET", "/api/v1/categories", "key-1").Code)
+}
+
+func TestClientKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.7:51234"
+	assert.Equal(t, "ip:10.0.0.7", ClientKey(req))
+
+	// header mentah diabaikan, hanya key yang lolos middleware.APIKey yang dipakai
+	req.Header.Set("X-API-Key", "secret")
+	assert.Equal(t, "ip:10.0.0.7", ClientKey(req))
+
+	var key string
+	middleware.APIKey([]string{"secret"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = ClientKey(r)
+		req = r
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Regexp(t, `^key:[0-9a-f]{16}$`, key)
+	assert.NotContains(t, key, "secret")
+
+	req = req.WithContext(WithClient(req.Context(), "42"))
+	assert.Equal(t, "user:42", ClientKey(req))
+}
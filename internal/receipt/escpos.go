@@ -0,0 +1,72 @@
+package receipt
+
+import (
+	"bytes"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+// perintah ESC/POS yang dipakai, lihat Epson ESC/POS command reference
+var (
+	escInit      = []byte{0x1b, 0x40}       // ESC @
+	escAlign     = []byte{0x1b, 0x61}       // ESC a n
+	escBold      = []byte{0x1b, 0x45}       // ESC E n
+	gsCharSize   = []byte{0x1d, 0x21}       // GS ! n
+	escFeedLines = []byte{0x1b, 0x64}       // ESC d n
+	gsPartialCut = []byte{0x1d, 0x56, 0x42} // GS V m n (feed lalu potong)
+)
+
+// RenderESCPOS merender struk sebagai byte stream yang bisa langsung dikirim ke printer thermal
+func RenderESCPOS(sale *models.Sale, t Template) []byte {
+	var buf bytes.Buffer
+	buf.Write(escInit)
+
+	for _, l := range buildLines(sale, t) {
+		buf.Write(escAlign)
+		buf.WriteByte(byte(l.align))
+
+		if l.bold {
+			buf.Write(escBold)
+			buf.WriteByte(1)
+		}
+		if l.large {
+			// double height saja, double width akan memotong jumlah kolom
+			buf.Write(gsCharSize)
+			buf.WriteByte(0x01)
+		}
+
+		buf.WriteString(toPrintable(l.text))
+		buf.WriteByte('\n')
+
+		if l.large {
+			buf.Write(gsCharSize)
+			buf.WriteByte(0x00)
+		}
+		if l.bold {
+			buf.Write(escBold)
+			buf.WriteByte(0)
+		}
+	}
+
+	buf.Write(escAlign)
+	buf.WriteByte(byte(alignLeft))
+	buf.Write(escFeedLines)
+	buf.WriteByte(3)
+	buf.Write(gsPartialCut)
+	buf.WriteByte(0)
+
+	return buf.Bytes()
+}
+
+// toPrintable mengganti karakter non-ASCII dengan '?' karena code page default printer
+// tidak bisa mencetak UTF-8
+func toPrintable(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			out = append(out, '?')
+			continue
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
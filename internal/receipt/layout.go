@@ -0,0 +1,181 @@
+package receipt
+
+import (
+	"fajar7xx/go-kasir-umam-ds/models"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+// line adalah satu baris struk, dipakai bersama oleh semua renderer
+type line struct {
+	text  string
+	align align
+	bold  bool
+	large bool
+}
+
+// padded mengembalikan teks yang sudah di-align ke lebar kolom,
+// dipakai oleh renderer yang tidak punya perintah align sendiri (text & pdf)
+func (l line) padded(columns int) string {
+	n := utf8.RuneCountInString(l.text)
+	if n >= columns {
+		return l.text
+	}
+
+	switch l.align {
+	case alignCenter:
+		left := (columns - n) / 2
+		return strings.Repeat(" ", left) + l.text
+	case alignRight:
+		return strings.Repeat(" ", columns-n) + l.text
+	default:
+		return l.text
+	}
+}
+
+func buildLines(sale *models.Sale, t Template) []line {
+	columns := t.Columns()
+	separator := line{text: strings.Repeat("-", columns)}
+
+	lines := make([]line, 0, 32)
+	lines = append(lines, line{text: t.StoreName, align: alignCenter, bold: true, large: true})
+	for _, header := range t.HeaderLines {
+		for _, wrapped := range wrap(header, columns) {
+			lines = append(lines, line{text: wrapped, align: alignCenter})
+		}
+	}
+	lines = append(lines, separator)
+	lines = append(lines, twoColumns("No. "+sale.InvoiceNumber, sale.CreatedAt.Format("02/01/06 15:04"), columns))
+	lines = append(lines, separator)
+
+	for _, item := range sale.Items {
+		for _, wrapped := range wrap(item.ProductName, columns) {
+			lines = append(lines, line{text: wrapped})
+		}
+
+		qty := "  " + strconv.Itoa(item.Quantity) + " x " + formatMoney(item.Price)
+		lines = append(lines, twoColumns(qty, formatMoney(item.Price*float64(item.Quantity)), columns))
+		if item.Discount > 0 {
+			lines = append(lines, twoColumns("  Diskon", "-"+formatMoney(item.Discount), columns))
+		}
+	}
+	lines = append(lines, separator)
+
+	lines = append(lines, twoColumns("Subtotal", formatMoney(sale.Subtotal), columns))
+	if sale.DiscountTotal > 0 {
+		lines = append(lines, twoColumns("Diskon", "-"+formatMoney(sale.DiscountTotal), columns))
+	}
+	if sale.TaxTotal > 0 {
+		lines = append(lines, twoColumns("Pajak", formatMoney(sale.TaxTotal), columns))
+	}
+	total := twoColumns("TOTAL", t.Currency+" "+formatMoney(sale.Total), columns)
+	total.bold = true
+	lines = append(lines, total)
+	lines = append(lines, separator)
+
+	for _, payment := range sale.Payments {
+		lines = append(lines, twoColumns(strings.ToUpper(payment.Method), formatMoney(payment.Amount), columns))
+	}
+	lines = append(lines, twoColumns("Kembali", formatMoney(sale.ChangeAmount), columns))
+	lines = append(lines, separator)
+
+	for _, footer := range t.FooterLines {
+		for _, wrapped := range wrap(footer, columns) {
+			lines = append(lines, line{text: wrapped, align: alignCenter})
+		}
+	}
+
+	return lines
+}
+
+// twoColumns menaruh left di kiri dan right di kanan dalam satu baris,
+// left dipotong kalau tidak muat
+func twoColumns(left, right string, columns int) line {
+	rightLen := utf8.RuneCountInString(right)
+	room := columns - rightLen - 1
+	if room < 0 {
+		room = 0
+	}
+
+	leftRunes := []rune(left)
+	if len(leftRunes) > room {
+		leftRunes = leftRunes[:room]
+	}
+
+	gap := columns - len(leftRunes) - rightLen
+	if gap < 1 {
+		gap = 1
+	}
+
+	return line{text: string(leftRunes) + strings.Repeat(" ", gap) + right}
+}
+
+// wrap memecah teks per kata supaya tidak melebihi lebar kolom
+func wrap(text string, columns int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var result []string
+	current := ""
+	for _, word := range words {
+		for utf8.RuneCountInString(word) > columns {
+			if current != "" {
+				result = append(result, current)
+				current = ""
+			}
+			runes := []rune(word)
+			result = append(result, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= columns:
+			current += " " + word
+		default:
+			result = append(result, current)
+			current = word
+		}
+	}
+	if current != "" {
+		result = append(result, current)
+	}
+
+	return result
+}
+
+// formatMoney memformat angka rupiah tanpa desimal dengan pemisah ribuan titik, contoh 15.000
+func formatMoney(amount float64) string {
+	value := int64(math.Round(amount))
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+
+	digits := strconv.FormatInt(value, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+
+	if negative {
+		return "-" + b.String()
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package receipt
+
+import (
+	"bytes"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fmt"
+	"strings"
+)
+
+const (
+	pointsPerMM = 72 / 25.4
+	pdfMargin   = 8.0
+	// lebar glyph Courier adalah 600/1000 dari ukuran font
+	courierAdvance = 0.6
+)
+
+// RenderPDF merender struk sebagai PDF satu halaman selebar kertas thermal.
+// tinggi halaman mengikuti jumlah baris sehingga hasilnya sama seperti struk fisik.
+func RenderPDF(sale *models.Sale, t Template) []byte {
+	columns := t.Columns()
+	lines := buildLines(sale, t)
+
+	pageWidth := float64(t.PaperWidth) * pointsPerMM
+	fontSize := (pageWidth - 2*pdfMargin) / (float64(columns) * courierAdvance)
+	leading := fontSize * 1.3
+	pageHeight := 2*pdfMargin + float64(len(lines))*leading
+
+	var content bytes.Buffer
+	y := pageHeight - pdfMargin - fontSize
+	for _, l := range lines {
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+			font, fontSize, pdfMargin, y, escapePDF(toPrintable(l.padded(columns))))
+		y -= leading
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>", pageWidth, pageHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objects)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+func escapePDF(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return replacer.Replace(text)
+}
@@ -0,0 +1,44 @@
+// Package receipt merender sebuah sale menjadi struk: teks monospace,
+// byte stream ESC/POS untuk printer thermal 58/80mm, dan PDF.
+package receipt
+
+import (
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"strings"
+)
+
+const (
+	FormatText   = "text"
+	FormatESCPOS = "escpos"
+	FormatPDF    = "pdf"
+)
+
+var ErrUnknownFormat = errors.New("unknown receipt format")
+
+// Render merender sale sesuai format dan mengembalikan isi beserta content type-nya
+func Render(format string, sale *models.Sale, t Template) ([]byte, string, error) {
+	switch format {
+	case FormatText, "":
+		return RenderText(sale, t), "text/plain; charset=utf-8", nil
+	case FormatESCPOS:
+		return RenderESCPOS(sale, t), "application/octet-stream", nil
+	case FormatPDF:
+		return RenderPDF(sale, t), "application/pdf", nil
+	default:
+		return nil, "", ErrUnknownFormat
+	}
+}
+
+// RenderText merender struk sebagai teks monospace selebar kolom kertas
+func RenderText(sale *models.Sale, t Template) []byte {
+	columns := t.Columns()
+
+	var b strings.Builder
+	for _, l := range buildLines(sale, t) {
+		b.WriteString(l.padded(columns))
+		b.WriteByte('\n')
+	}
+
+	return []byte(b.String())
+}
@@ -0,0 +1,116 @@
+package receipt
+
+import (
+	"bytes"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleSale() *models.Sale {
+	return &models.Sale{
+		ID:            1,
+		InvoiceNumber: "INV-0001",
+		Subtotal:      33000,
+		DiscountTotal: 3000,
+		TaxTotal:      3000,
+		Total:         33000,
+		PaidAmount:    50000,
+		ChangeAmount:  17000,
+		Items: []models.SaleItem{
+			{ProductName: "Nasi Goreng Spesial", Quantity: 2, Price: 15000, Subtotal: 30000},
+			{ProductName: "Es Teh", Quantity: 1, Price: 5000, Discount: 2000, Subtotal: 3000},
+		},
+		Payments: []models.SalePayment{
+			{Method: "cash", Amount: 50000},
+		},
+		CreatedAt: time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC),
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	tmpl := DefaultTemplate()
+
+	out := string(RenderText(sampleSale(), tmpl))
+
+	assert.Contains(t, out, "Kasir Umam DS")
+	assert.Contains(t, out, "No. INV-0001")
+	assert.Contains(t, out, "Nasi Goreng Spesial")
+	assert.Contains(t, out, "-2.000")
+	assert.Contains(t, out, "Rp 33.000")
+	assert.Contains(t, out, "Terima kasih")
+
+	for _, l := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		assert.LessOrEqual(t, utf8.RuneCountInString(l), 32, "line too wide: %q", l)
+	}
+}
+
+func TestRenderText_80mm(t *testing.T) {
+	tmpl := DefaultTemplate()
+	tmpl.PaperWidth = 80
+
+	out := string(RenderText(sampleSale(), tmpl))
+
+	assert.Contains(t, out, strings.Repeat("-", 48))
+}
+
+func TestRenderESCPOS(t *testing.T) {
+	out := RenderESCPOS(sampleSale(), DefaultTemplate())
+
+	assert.True(t, bytes.HasPrefix(out, escInit))
+	assert.True(t, bytes.HasSuffix(out, append(gsPartialCut, 0)))
+	assert.Contains(t, string(out), "No. INV-0001")
+}
+
+func TestRenderPDF(t *testing.T) {
+	out := RenderPDF(sampleSale(), DefaultTemplate())
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "(No. INV-0001")
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, _, err := Render("docx", sampleSale(), DefaultTemplate())
+
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "0", formatMoney(0))
+	assert.Equal(t, "999", formatMoney(999))
+	assert.Equal(t, "15.000", formatMoney(15000))
+	assert.Equal(t, "1.250.000", formatMoney(1250000))
+	assert.Equal(t, "-3.000", formatMoney(-3000))
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"store_name": "Umam Cabang 2", "header_lines": ["Jl. Merdeka 1"], "paper_width": 80}`
+	err := os.WriteFile(filepath.Join(dir, "cabang-2.json"), []byte(content), 0o644)
+	assert.NoError(t, err)
+
+	templates, err := LoadTemplates(dir)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Umam Cabang 2", templates.Get("cabang-2").StoreName)
+	assert.Equal(t, 48, templates.Get("cabang-2").Columns())
+	assert.Equal(t, "Rp", templates.Get("cabang-2").Currency)
+	assert.Equal(t, DefaultTemplate().StoreName, templates.Get("unknown").StoreName)
+}
+
+func TestLoadTemplates_InvalidPaperWidth(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"paper_width": 100}`), 0o644)
+	assert.NoError(t, err)
+
+	_, err = LoadTemplates(dir)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package receipt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultStore adalah kode toko yang dipakai kalau request tidak menyebut toko
+const DefaultStore = "default"
+
+// Template mengatur isi header/footer dan ukuran kertas struk untuk satu toko
+type Template struct {
+	StoreName   string   `json:"store_name"`
+	HeaderLines []string `json:"header_lines"`
+	FooterLines []string `json:"footer_lines"`
+	PaperWidth  int      `json:"paper_width"` // dalam mm, 58 atau 80
+	Currency    string   `json:"currency"`
+}
+
+func DefaultTemplate() Template {
+	return Template{
+		StoreName:   "Kasir Umam DS",
+		FooterLines: []string{"Terima kasih", "Barang yang sudah dibeli tidak dapat dikembalikan"},
+		PaperWidth:  58,
+		Currency:    "Rp",
+	}
+}
+
+// Columns mengembalikan jumlah karakter per baris (font A) sesuai lebar kertas
+func (t Template) Columns() int {
+	if t.PaperWidth >= 80 {
+		return 48
+	}
+	return 32
+}
+
+func (t Template) validate() error {
+	if t.StoreName == "" {
+		return errors.New("store_name is required")
+	}
+	if t.PaperWidth != 58 && t.PaperWidth != 80 {
+		return fmt.Errorf("paper_width must be 58 or 80, got %d", t.PaperWidth)
+	}
+	return nil
+}
+
+// Templates adalah kumpulan template per kode toko
+type Templates map[string]Template
+
+// Get mengembalikan template untuk toko tersebut, atau template default
+func (ts Templates) Get(store string) Template {
+	if t, ok := ts[store]; ok {
+		return t
+	}
+	if t, ok := ts[DefaultStore]; ok {
+		return t
+	}
+	return DefaultTemplate()
+}
+
+// LoadTemplates membaca semua file <kode-toko>.json di dir.
+// field yang kosong diisi dari DefaultTemplate, dan dir kosong berarti hanya template default.
+func LoadTemplates(dir string) (Templates, error) {
+	templates := Templates{DefaultStore: DefaultTemplate()}
+	if dir == "" {
+		return templates, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		t := DefaultTemplate()
+		if err := json.Unmarshal(content, &t); err != nil {
+			return nil, fmt.Errorf("receipt template %s: %w", file, err)
+		}
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("receipt template %s: %w", file, err)
+		}
+
+		store := strings.TrimSuffix(filepath.Base(file), ".json")
+		templates[store] = t
+	}
+
+	return templates, nil
+}
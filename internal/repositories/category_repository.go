@@ -1,41 +1,42 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"fajar7xx/go-kasir-umam-ds/models"
 )
 
 type CategoryRepositoryInterface interface {
-	GetAll() ([]models.Category, error)
-	GetByID(id int) (*models.Category, error)
-	Create(category *models.Category) error
-	Update(id int, category *models.Category) error
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]models.Category, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
+	Update(ctx context.Context, id int, category *models.Category) error
+	Patch(ctx context.Context, id int, patch models.CategoryPatch) error
+	Delete(ctx context.Context, id int, version int) error
 }
 
 type CategoryRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewCategoryRepository(db *sql.DB) CategoryRepositoryInterface {
+func NewCategoryRepository(db DBTX) CategoryRepositoryInterface {
 	return &CategoryRepository{
 		db: db,
 	}
 }
 
-func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
+func (repo *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
 	query := `SELECT
-		id, name, description, created_at, updated_at
+		id, name, description, created_at, updated_at, version
 		FROM categories`
 
-	rows, err := repo.db.Query(query)
+	rows, err := repo.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var categories []models.Category
+	categories := make([]models.Category, 0)
 	for rows.Next() {
 		var category models.Category
 		err := rows.Scan(
@@ -44,6 +45,7 @@ func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
 			&category.Description,
 			&category.CreatedAt,
 			&category.UpdatedAt,
+			&category.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -55,13 +57,13 @@ func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
 	return categories, nil
 }
 
-func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
+func (repo *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
 	query := `SELECT
-				id, name, description, created_at, updated_at
+				id, name, description, created_at, updated_at, version
 			FROM categories
 			where id = $1`
 
-	row := repo.db.QueryRow(query, id)
+	row := repo.db.QueryRowContext(ctx, query, id)
 
 	var category models.Category
 	err := row.Scan(
@@ -70,10 +72,11 @@ func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
 		&category.Description,
 		&category.CreatedAt,
 		&category.UpdatedAt,
+		&category.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("Category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, err
 	}
@@ -81,20 +84,21 @@ func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
 	return &category, nil
 }
 
-func (repo *CategoryRepository) Create(category *models.Category) error {
+func (repo *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
 	query := `INSERT INTO categories
 				(name, description)
 				VALUES
 				($1, $2)
-				RETURNING id, created_at, updated_at`
+				RETURNING id, created_at, updated_at, version`
 
-	err := repo.db.QueryRow(query,
+	err := repo.db.QueryRowContext(ctx, query,
 		category.Name,
 		category.Description,
 	).Scan(
 		&category.ID,
 		&category.CreatedAt,
 		&category.UpdatedAt,
+		&category.Version,
 	)
 	if err != nil {
 		return err
@@ -103,15 +107,16 @@ func (repo *CategoryRepository) Create(category *models.Category) error {
 	return nil
 }
 
-func (repo *CategoryRepository) Update(id int, category *models.Category) error {
+func (repo *CategoryRepository) Update(ctx context.Context, id int, category *models.Category) error {
 	query := `UPDATE categories
-			SET name=$1, description=$2, updated_at=NOW()
-			WHERE id=$3`
+			SET name=$1, description=$2, updated_at=NOW(), version = version + 1
+			WHERE id=$3 AND version=$4`
 
-	result, err := repo.db.Exec(query,
+	result, err := repo.db.ExecContext(ctx, query,
 		category.Name,
 		category.Description,
 		id,
+		category.Version,
 	)
 	if err != nil {
 		return err
@@ -123,26 +128,54 @@ func (repo *CategoryRepository) Update(id int, category *models.Category) error
 	}
 
 	if rows == 0 {
-		return errors.New("category not found")
+		return notFoundOrStale(ctx, repo.db, "categories", id, ErrCategoryNotFound)
 	}
 
 	return nil
 }
 
-func (repo *CategoryRepository) Delete(id int) error {
-	query := `DELETE FROM categories where id=$1`
-	result, err := repo.db.Exec(query, id)
+// Patch hanya mengubah kolom yang dikirim
+func (repo *CategoryRepository) Patch(ctx context.Context, id int, patch models.CategoryPatch) error {
+	var update updateBuilder
+	if patch.Name.Set {
+		update.set("name", patch.Name.SQLValue())
+	}
+	if patch.Description.Set {
+		update.set("description", patch.Description.SQLValue())
+	}
+
+	query, args := update.query("categories", id, patch.Version)
+	result, err := repo.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateDBError(err, categoryConstraints)
+	}
+
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
+	if rows == 0 {
+		return notFoundOrStale(ctx, repo.db, "categories", id, ErrCategoryNotFound)
+	}
+
+	return nil
+}
+
+func (repo *CategoryRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `DELETE FROM categories where id=$1 AND version=$2`
+	result, err := repo.db.ExecContext(ctx, query, id, version)
+	if err != nil {
+		return translateDBError(err, categoryConstraints)
+	}
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
 	if rows == 0 {
-		return errors.New("category not found")
+		return notFoundOrStale(ctx, repo.db, "categories", id, ErrCategoryNotFound)
 	}
 
 	return nil
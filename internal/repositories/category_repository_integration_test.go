@@ -0,0 +1,115 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryRepository_Integration_CRUD(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	categories, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+	assert.NotNil(t, categories)
+
+	category := &models.Category{Name: "Minuman", Description: strPtr("Kopi dan teh")}
+	require.NoError(t, repo.Create(ctx, category))
+	assert.Equal(t, 1, category.ID)
+	assert.Equal(t, 1, category.Version)
+	assert.False(t, category.CreatedAt.IsZero())
+	assert.Nil(t, category.UpdatedAt)
+
+	found, err := repo.GetByID(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Minuman", found.Name)
+	assert.Equal(t, "Kopi dan teh", *found.Description)
+
+	// update menaikkan versi, versi lama ditolak
+	require.NoError(t, repo.Update(ctx, category.ID, &models.Category{Name: "Minuman Dingin", Version: 1}))
+	err = repo.Update(ctx, category.ID, &models.Category{Name: "Basi", Version: 1})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	found, err = repo.GetByID(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Minuman Dingin", found.Name)
+	assert.Nil(t, found.Description)
+	assert.Equal(t, 2, found.Version)
+	assert.NotNil(t, found.UpdatedAt)
+
+	categories, err = repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, categories, 1)
+
+	err = repo.Delete(ctx, category.ID, 1)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	require.NoError(t, repo.Delete(ctx, category.ID, 2))
+
+	_, err = repo.GetByID(ctx, category.ID)
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func TestCategoryRepository_Integration_NotFound(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, 99)
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+
+	err = repo.Update(ctx, 99, &models.Category{Name: "x", Version: 1})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+
+	err = repo.Patch(ctx, 99, models.CategoryPatch{Version: 1})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+
+	err = repo.Delete(ctx, 99, 1)
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func TestCategoryRepository_Integration_Patch(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Makanan", Description: strPtr("Nasi dan mie")}
+	require.NoError(t, repo.Create(ctx, category))
+
+	// description di-null-kan, name tidak dikirim sehingga tetap
+	err := repo.Patch(ctx, category.ID, models.CategoryPatch{
+		Description: models.PatchField[string]{Set: true, Null: true},
+		Version:     1,
+	})
+	require.NoError(t, err)
+
+	found, err := repo.GetByID(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Makanan", found.Name)
+	assert.Nil(t, found.Description)
+	assert.Equal(t, 2, found.Version)
+
+	err = repo.Patch(ctx, category.ID, models.CategoryPatch{
+		Name:    models.PatchField[string]{Set: true, Value: "Basi"},
+		Version: 1,
+	})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestCategoryRepository_Integration_DeleteInUse(t *testing.T) {
+	db := integrationDB(t)
+	category := seedCategory(t, db, "Minuman")
+	seedProduct(t, outletCtx(1), db, category.ID, "Es Teh", 5000, 10)
+
+	err := NewCategoryRepository(db).Delete(context.Background(), category.ID, category.Version)
+
+	assertAppError(t, err, apperrors.ErrConflict, "CATEGORY_IN_USE")
+}
@@ -1,13 +1,16 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
 	"fajar7xx/go-kasir-umam-ds/models"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,14 +24,14 @@ func TestCategoryRepository_GetAll(t *testing.T) {
 	repo := NewCategoryRepository(db)
 
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
-		AddRow(1, "Food", "Food Category", now, now).
-		AddRow(2, "Beverage", "Beverage Category", now, now)
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at", "version"}).
+		AddRow(1, "Food", "Food Category", now, now, 1).
+		AddRow(2, "Beverage", "Beverage Category", now, now, 1)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at FROM categories`)
+	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at, version FROM categories`)
 	mock.ExpectQuery(query).WillReturnRows(rows)
 
-	categories, err := repo.GetAll()
+	categories, err := repo.GetAll(context.Background())
 
 	assert.NoError(t, err)
 	assert.Len(t, categories, 2)
@@ -45,10 +48,10 @@ func TestCategoryRepository_GetAll_Error(t *testing.T) {
 
 	repo := NewCategoryRepository(db)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at FROM categories`)
+	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at, version FROM categories`)
 	mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
 
-	categories, err := repo.GetAll()
+	categories, err := repo.GetAll(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, categories)
@@ -64,13 +67,13 @@ func TestCategoryRepository_GetByID(t *testing.T) {
 	repo := NewCategoryRepository(db)
 
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
-		AddRow(1, "Food", "Food Category", now, now)
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at", "version"}).
+		AddRow(1, "Food", "Food Category", now, now, 1)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at FROM categories where id = $1`)
+	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at, version FROM categories where id = $1`)
 	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
 
-	category, err := repo.GetByID(1)
+	category, err := repo.GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, category)
@@ -87,13 +90,14 @@ func TestCategoryRepository_GetByID_NotFound(t *testing.T) {
 
 	repo := NewCategoryRepository(db)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at FROM categories where id = $1`)
+	query := regexp.QuoteMeta(`SELECT id, name, description, created_at, updated_at, version FROM categories where id = $1`)
 	mock.ExpectQuery(query).WithArgs(1).WillReturnError(sql.ErrNoRows)
 
-	category, err := repo.GetByID(1)
+	category, err := repo.GetByID(context.Background(), 1)
 
 	assert.Error(t, err)
-	assert.Equal(t, "Category not found", err.Error())
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	assert.Equal(t, "category not found", err.Error())
 	assert.Nil(t, category)
 }
 
@@ -107,19 +111,20 @@ func TestCategoryRepository_Create(t *testing.T) {
 	repo := NewCategoryRepository(db)
 
 	now := time.Now()
+	desc := "Food Category"
 	category := &models.Category{
 		Name:        "Food",
-		Description: "Food Category",
+		Description: &desc,
 	}
 
-	query := regexp.QuoteMeta(`INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id, created_at, updated_at`)
+	query := regexp.QuoteMeta(`INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id, created_at, updated_at, version`)
 
-	// Create returns id, created_at, updated_at
+	// Create returns id, created_at, updated_at, version
 	mock.ExpectQuery(query).
 		WithArgs(category.Name, category.Description).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, now, now))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "version"}).AddRow(1, now, now, 1))
 
-	err = repo.Create(category)
+	err = repo.Create(context.Background(), category)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, category.ID)
@@ -135,17 +140,19 @@ func TestCategoryRepository_Update(t *testing.T) {
 
 	repo := NewCategoryRepository(db)
 
+	desc := "Food Desc Updated"
 	category := &models.Category{
 		Name:        "Food Updated",
-		Description: "Food Desc Updated",
+		Description: &desc,
+		Version:     2,
 	}
 
-	query := regexp.QuoteMeta(`UPDATE categories SET name=$1, description=$2, updated_at=NOW() WHERE id=$3`)
+	query := regexp.QuoteMeta(`UPDATE categories SET name=$1, description=$2, updated_at=NOW(), version = version + 1 WHERE id=$3 AND version=$4`)
 	mock.ExpectExec(query).
-		WithArgs(category.Name, category.Description, 1).
+		WithArgs(category.Name, category.Description, 1, 2).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.Update(1, category)
+	err = repo.Update(context.Background(), 1, category)
 
 	assert.NoError(t, err)
 }
@@ -159,17 +166,22 @@ func TestCategoryRepository_Update_NotFound(t *testing.T) {
 
 	repo := NewCategoryRepository(db)
 
+	desc := "Food Desc Updated"
 	category := &models.Category{
 		Name:        "Food Updated",
-		Description: "Food Desc Updated",
+		Description: &desc,
+		Version:     2,
 	}
 
-	query := regexp.QuoteMeta(`UPDATE categories SET name=$1, description=$2, updated_at=NOW() WHERE id=$3`)
+	query := regexp.QuoteMeta(`UPDATE categories SET name=$1, description=$2, updated_at=NOW(), version = version + 1 WHERE id=$3 AND version=$4`)
 	mock.ExpectExec(query).
-		WithArgs(category.Name, category.Description, 1).
+		WithArgs(category.Name, category.Description, 1, 2).
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-	err = repo.Update(1, category)
+	err = repo.Update(context.Background(), 1, category)
 
 	assert.Error(t, err)
 	assert.Equal(t, "category not found", err.Error())
@@ -184,12 +196,12 @@ func TestCategoryRepository_Delete(t *testing.T) {
 
 	repo := NewCategoryRepository(db)
 
-	query := regexp.QuoteMeta(`DELETE FROM categories where id=$1`)
+	query := regexp.QuoteMeta(`DELETE FROM categories where id=$1 AND version=$2`)
 	mock.ExpectExec(query).
-		WithArgs(1).
+		WithArgs(1, 2).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.Delete(1)
+	err = repo.Delete(context.Background(), 1, 2)
 
 	assert.NoError(t, err)
 }
@@ -203,13 +215,89 @@ func TestCategoryRepository_Delete_NotFound(t *testing.T) {
 
 	repo := NewCategoryRepository(db)
 
-	query := regexp.QuoteMeta(`DELETE FROM categories where id=$1`)
+	query := regexp.QuoteMeta(`DELETE FROM categories where id=$1 AND version=$2`)
 	mock.ExpectExec(query).
-		WithArgs(1).
+		WithArgs(1, 2).
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-	err = repo.Delete(1)
+	err = repo.Delete(context.Background(), 1, 2)
 
 	assert.Error(t, err)
 	assert.Equal(t, "category not found", err.Error())
 }
+
+func TestCategoryRepository_Delete_InUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	query := regexp.QuoteMeta(`DELETE FROM categories where id=$1 AND version=$2`)
+	mock.ExpectExec(query).
+		WithArgs(1, 2).
+		WillReturnError(&pgconn.PgError{
+			Code:           apperrors.PgForeignKeyViolation,
+			ConstraintName: "products_category_id_fkey",
+			Detail:         `Key (id)=(1) is still referenced from table "products".`,
+		})
+
+	err = repo.Delete(context.Background(), 1, 2)
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, "category still has products", err.Error())
+}
+
+func TestCategoryRepository_Patch_NullDescription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	patch := models.CategoryPatch{Description: models.PatchField[string]{Set: true, Null: true}}
+
+	query := regexp.QuoteMeta(`UPDATE categories SET description = $1, updated_at = NOW(), version = version + 1 WHERE id = $2 AND version = $3`)
+	mock.ExpectExec(query).
+		WithArgs(nil, 1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Patch(context.Background(), 1, patch)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+
+	category := &models.Category{Name: "Food Updated", Version: 2}
+
+	query := regexp.QuoteMeta(`UPDATE categories SET name=$1, description=$2, updated_at=NOW(), version = version + 1 WHERE id=$3 AND version=$4`)
+	mock.ExpectExec(query).
+		WithArgs(category.Name, category.Description, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// baris masih ada, berarti version sudah dinaikkan oleh request lain
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err = repo.Update(context.Background(), 1, category)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.ErrorIs(t, err, apperrors.ErrPrecondition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
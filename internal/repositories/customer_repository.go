@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+type CustomerRepositoryInterface interface {
+	GetAll(ctx context.Context) ([]models.Customer, error)
+	GetByID(ctx context.Context, id int) (*models.Customer, error)
+	GetByPhone(ctx context.Context, phone string) (*models.Customer, error)
+	Create(ctx context.Context, customer *models.Customer) error
+	Update(ctx context.Context, id int, customer *models.Customer) error
+	Delete(ctx context.Context, id int) error
+}
+
+type CustomerRepository struct {
+	db DBTX
+}
+
+func NewCustomerRepository(db DBTX) CustomerRepositoryInterface {
+	return &CustomerRepository{
+		db: db,
+	}
+}
+
+// birthday dibaca sebagai teks YYYY-MM-DD supaya tidak berubah jadi timestamp di JSON
+const customerSelect = `SELECT
+				id, name, phone, email, to_char(birthday, 'YYYY-MM-DD'), created_at, updated_at
+			FROM customers`
+
+func (repo *CustomerRepository) GetAll(ctx context.Context) ([]models.Customer, error) {
+	rows, err := repo.db.QueryContext(ctx, customerSelect+` ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		var customer models.Customer
+		err := rows.Scan(
+			&customer.ID,
+			&customer.Name,
+			&customer.Phone,
+			&customer.Email,
+			&customer.Birthday,
+			&customer.CreatedAt,
+			&customer.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		customers = append(customers, customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (repo *CustomerRepository) GetByID(ctx context.Context, id int) (*models.Customer, error) {
+	return repo.getOne(ctx, customerSelect+` WHERE id = $1`, id)
+}
+
+func (repo *CustomerRepository) GetByPhone(ctx context.Context, phone string) (*models.Customer, error) {
+	return repo.getOne(ctx, customerSelect+` WHERE phone = $1`, phone)
+}
+
+func (repo *CustomerRepository) getOne(ctx context.Context, query string, arg any) (*models.Customer, error) {
+	var customer models.Customer
+	err := repo.db.QueryRowContext(ctx, query, arg).Scan(
+		&customer.ID,
+		&customer.Name,
+		&customer.Phone,
+		&customer.Email,
+		&customer.Birthday,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+func (repo *CustomerRepository) Create(ctx context.Context, customer *models.Customer) error {
+	query := `INSERT INTO customers
+				(name, phone, email, birthday)
+			VALUES
+				($1, $2, $3, $4)
+			RETURNING id, created_at, updated_at`
+
+	err := repo.db.QueryRowContext(ctx, query,
+		customer.Name,
+		customer.Phone,
+		customer.Email,
+		customer.Birthday,
+	).Scan(
+		&customer.ID,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+	if err != nil {
+		return translateDBError(err, customerConstraints)
+	}
+
+	return nil
+}
+
+func (repo *CustomerRepository) Update(ctx context.Context, id int, customer *models.Customer) error {
+	query := `UPDATE customers
+			SET name = $1, phone = $2, email = $3, birthday = $4, updated_at = NOW()
+			WHERE id = $5`
+
+	result, err := repo.db.ExecContext(ctx, query,
+		customer.Name,
+		customer.Phone,
+		customer.Email,
+		customer.Birthday,
+		id,
+	)
+	if err != nil {
+		return translateDBError(err, customerConstraints)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+func (repo *CustomerRepository) Delete(ctx context.Context, id int) error {
+	result, err := repo.db.ExecContext(ctx, `DELETE FROM customers WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrCustomerNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerRepository_Integration_CRUD(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	customer := &models.Customer{
+		Name:     "Umam",
+		Phone:    "08123456789",
+		Email:    strPtr("umam@example.com"),
+		Birthday: strPtr("1990-05-17"),
+	}
+	require.NoError(t, repo.Create(ctx, customer))
+	assert.Equal(t, 1, customer.ID)
+	assert.False(t, customer.CreatedAt.IsZero())
+
+	found, err := repo.GetByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "umam@example.com", *found.Email)
+	// birthday dibaca kembali sebagai tanggal, bukan timestamp
+	assert.Equal(t, "1990-05-17", *found.Birthday)
+
+	found, err = repo.GetByPhone(ctx, "08123456789")
+	require.NoError(t, err)
+	assert.Equal(t, customer.ID, found.ID)
+
+	seedCustomer(t, db, "08120000000")
+	customers, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, customers, 2)
+	// diurutkan berdasarkan nama
+	assert.Equal(t, "Pelanggan 08120000000", customers[0].Name)
+	assert.Equal(t, "Umam", customers[1].Name)
+
+	err = repo.Update(ctx, customer.ID, &models.Customer{Name: "Umam DS", Phone: "08123456789"})
+	require.NoError(t, err)
+	found, err = repo.GetByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Umam DS", found.Name)
+	assert.Nil(t, found.Email)
+	assert.Nil(t, found.Birthday)
+	assert.NotNil(t, found.UpdatedAt)
+
+	require.NoError(t, repo.Delete(ctx, customer.ID))
+	_, err = repo.GetByID(ctx, customer.ID)
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+}
+
+func TestCustomerRepository_Integration_NotFound(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, 99)
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	_, err = repo.GetByPhone(ctx, "0000")
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	err = repo.Update(ctx, 99, &models.Customer{Name: "x", Phone: "0000"})
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	err = repo.Delete(ctx, 99)
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+}
+
+func TestCustomerRepository_Integration_DuplicatePhone(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewCustomerRepository(db)
+	ctx := context.Background()
+	seedCustomer(t, db, "08123456789")
+	other := seedCustomer(t, db, "08120000000")
+
+	err := repo.Create(ctx, &models.Customer{Name: "Kembar", Phone: "08123456789"})
+	assertAppError(t, err, apperrors.ErrConflict, "PHONE_ALREADY_REGISTERED")
+
+	err = repo.Update(ctx, other.ID, &models.Customer{Name: other.Name, Phone: "08123456789"})
+	assertAppError(t, err, apperrors.ErrConflict, "PHONE_ALREADY_REGISTERED")
+}
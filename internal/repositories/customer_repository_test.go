@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerRepository_GetByPhone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCustomerRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "phone", "email", "birthday", "created_at", "updated_at"}).
+		AddRow(1, "Budi", "081234567890", nil, "1990-05-01", now, nil)
+
+	mock.ExpectQuery(`SELECT .* FROM customers WHERE phone = \$1`).WithArgs("081234567890").WillReturnRows(rows)
+
+	customer, err := repo.GetByPhone(context.Background(), "081234567890")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi", customer.Name)
+	assert.Equal(t, "1990-05-01", *customer.Birthday)
+}
+
+func TestCustomerRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCustomerRepository(db)
+
+	mock.ExpectQuery(`SELECT .* FROM customers WHERE id = \$1`).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+	customer, err := repo.GetByID(context.Background(), 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "customer not found", err.Error())
+	assert.Nil(t, customer)
+}
+
+func TestCustomerRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCustomerRepository(db)
+
+	now := time.Now()
+	customer := &models.Customer{Name: "Budi", Phone: "081234567890"}
+
+	query := regexp.QuoteMeta(`INSERT INTO customers (name, phone, email, birthday) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`)
+	mock.ExpectQuery(query).
+		WithArgs(customer.Name, customer.Phone, customer.Email, customer.Birthday).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, now, nil))
+
+	err = repo.Create(context.Background(), customer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, customer.ID)
+}
+
+func TestCustomerRepository_Create_DuplicatePhone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCustomerRepository(db)
+
+	mock.ExpectQuery(`INSERT INTO customers`).
+		WillReturnError(&pgconn.PgError{Code: apperrors.PgUniqueViolation, ConstraintName: "customers_phone_key"})
+
+	err = repo.Create(context.Background(), &models.Customer{Name: "Budi", Phone: "081234567890"})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, "phone number is already registered", err.Error())
+}
+
+func TestLoyaltyRepository_Redeem_Insufficient(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLoyaltyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, remaining FROM loyalty_ledger`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "remaining"}).AddRow(1, 10).AddRow(2, 5))
+	mock.ExpectRollback()
+
+	entry, err := repo.Redeem(context.Background(), 1, 20, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, "insufficient loyalty points", err.Error())
+	assert.Nil(t, entry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoyaltyRepository_Redeem_FIFO(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewLoyaltyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, remaining FROM loyalty_ledger`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "remaining"}).AddRow(1, 10).AddRow(2, 15))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE loyalty_ledger SET remaining = remaining - $1 WHERE id = $2`)).
+		WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE loyalty_ledger SET remaining = remaining - $1 WHERE id = $2`)).
+		WithArgs(8, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO loyalty_ledger`).
+		WithArgs(1, nil, models.LoyaltyEntryRedeem, -18).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+	mock.ExpectCommit()
+
+	entry, err := repo.Redeem(context.Background(), 1, 18, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, -18, entry.Points)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,737 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"sort"
+)
+
+// BuildSaleFunc menyusun sale dari draft order yang sudah dikunci di dalam transaksi checkout
+type BuildSaleFunc func(order *models.DraftOrder) (*models.Sale, error)
+
+type DraftOrderRepositoryInterface interface {
+	GetOpen(ctx context.Context) ([]models.DraftOrder, error)
+	GetByID(ctx context.Context, id int) (*models.DraftOrder, error)
+	Create(ctx context.Context, order *models.DraftOrder) error
+	UpdateTag(ctx context.Context, id int, order *models.DraftOrder) error
+	AddItem(ctx context.Context, orderID int, item *models.DraftOrderItem) error
+	UpdateItem(ctx context.Context, orderID, itemID int, item *models.DraftOrderItem) error
+	RemoveItem(ctx context.Context, orderID, itemID int) error
+	Merge(ctx context.Context, targetID, sourceID int) error
+	Split(ctx context.Context, orderID int, lines []models.SplitLine) (int, error)
+	Cancel(ctx context.Context, id int) error
+	Checkout(ctx context.Context, id int, build BuildSaleFunc) (*models.Sale, error)
+	// WithTx mengembalikan repository yang sama tapi memakai transaksi dari TxManager.WithinTx
+	WithTx(tx DBTX) DraftOrderRepositoryInterface
+}
+
+type DraftOrderRepository struct {
+	db DBTX
+}
+
+func NewDraftOrderRepository(db DBTX) DraftOrderRepositoryInterface {
+	return &DraftOrderRepository{
+		db: db,
+	}
+}
+
+func (repo *DraftOrderRepository) WithTx(tx DBTX) DraftOrderRepositoryInterface {
+	return NewDraftOrderRepository(tx)
+}
+
+const draftOrderItemSelect = `SELECT
+				i.id, i.draft_order_id, i.product_id, p.name, i.quantity,
+				i.price, i.discount, (i.quantity * i.price - i.discount) AS subtotal, i.created_at
+			FROM draft_order_items i
+			JOIN products p ON p.id = i.product_id`
+
+// GetOpen mengembalikan open tab milik outlet yang sedang dilayani
+func (repo *DraftOrderRepository) GetOpen(ctx context.Context) ([]models.DraftOrder, error) {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, outlet_id, table_number, customer_name, status, sale_id, created_at, updated_at
+			FROM draft_orders
+			WHERE status = 'open' AND outlet_id = $1
+			ORDER BY created_at`
+
+	rows, err := repo.db.QueryContext(ctx, query, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.DraftOrder, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		var order models.DraftOrder
+		err := rows.Scan(
+			&order.ID,
+			&order.OutletID,
+			&order.TableNumber,
+			&order.CustomerName,
+			&order.Status,
+			&order.SaleID,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		order.Items = make([]models.DraftOrderItem, 0)
+		index[order.ID] = len(orders)
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// ambil semua item dari tab yang open sekaligus, lalu kelompokkan per order
+	items, err := scanDraftOrderItems(repo.db.QueryContext(ctx, draftOrderItemSelect+`
+			JOIN draft_orders o ON o.id = i.draft_order_id
+			WHERE o.status = 'open' AND o.outlet_id = $1
+			ORDER BY i.id`, outletID))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		i, ok := index[item.DraftOrderID]
+		if !ok {
+			continue
+		}
+		orders[i].Items = append(orders[i].Items, item)
+		orders[i].Total += item.Subtotal
+	}
+
+	return orders, nil
+}
+
+func (repo *DraftOrderRepository) GetByID(ctx context.Context, id int) (*models.DraftOrder, error) {
+	return getDraftOrder(ctx, repo.db, id, false)
+}
+
+func (repo *DraftOrderRepository) Create(ctx context.Context, order *models.DraftOrder) error {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO draft_orders (outlet_id, table_number, customer_name)
+			VALUES ($1, $2, $3)
+			RETURNING id, status, created_at`
+
+	order.OutletID = outletID
+	err = tx.QueryRowContext(ctx, query, outletID, order.TableNumber, order.CustomerName).Scan(
+		&order.ID,
+		&order.Status,
+		&order.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	// stok dikunci dengan urutan product_id, urutan item di order tidak berubah
+	indexes := make([]int, len(order.Items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return order.Items[indexes[a]].ProductID < order.Items[indexes[b]].ProductID
+	})
+
+	for _, i := range indexes {
+		if err := insertDraftOrderItem(ctx, tx, outletID, order.ID, &order.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo *DraftOrderRepository) UpdateTag(ctx context.Context, id int, order *models.DraftOrder) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenDraftOrder(ctx, tx, id); err != nil {
+		return err
+	}
+
+	query := `UPDATE draft_orders
+			SET table_number = $1, customer_name = $2, updated_at = NOW()
+			WHERE id = $3`
+
+	if _, err := tx.ExecContext(ctx, query, order.TableNumber, order.CustomerName, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *DraftOrderRepository) AddItem(ctx context.Context, orderID int, item *models.DraftOrderItem) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	outletID, err := lockOpenDraftOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertDraftOrderItem(ctx, tx, outletID, orderID, item); err != nil {
+		return err
+	}
+
+	if err := touchDraftOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *DraftOrderRepository) UpdateItem(ctx context.Context, orderID, itemID int, item *models.DraftOrderItem) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	outletID, err := lockOpenDraftOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	var productID int
+	err = tx.QueryRowContext(ctx,
+		`SELECT product_id FROM draft_order_items WHERE id = $1 AND draft_order_id = $2 FOR UPDATE`,
+		itemID, orderID,
+	).Scan(&productID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrDraftOrderItemNotFound
+		}
+		return err
+	}
+
+	// item ini sendiri tidak dihitung sebagai reservasi karena quantity-nya akan diganti
+	if _, err := reserveStock(ctx, tx, outletID, productID, item.Quantity, itemID); err != nil {
+		return err
+	}
+
+	query := `UPDATE draft_order_items SET quantity = $1, discount = $2 WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, item.Quantity, item.Discount, itemID); err != nil {
+		return err
+	}
+
+	if err := touchDraftOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *DraftOrderRepository) RemoveItem(ctx context.Context, orderID, itemID int) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenDraftOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM draft_order_items WHERE id = $1 AND draft_order_id = $2`, itemID, orderID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrDraftOrderItemNotFound
+	}
+
+	if err := touchDraftOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Merge memindahkan semua item dari source ke target lalu menandai source sebagai merged.
+// reservasi stok tidak berubah karena item tetap berada di tab yang open.
+func (repo *DraftOrderRepository) Merge(ctx context.Context, targetID, sourceID int) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// kunci dengan urutan id yang sama untuk menghindari deadlock
+	first, second := targetID, sourceID
+	if first > second {
+		first, second = second, first
+	}
+	firstOutlet, err := lockOpenDraftOrder(ctx, tx, first)
+	if err != nil {
+		return err
+	}
+	secondOutlet, err := lockOpenDraftOrder(ctx, tx, second)
+	if err != nil {
+		return err
+	}
+
+	// reservasi stok dihitung per outlet, jadi tab dari outlet lain tidak boleh digabung
+	if firstOutlet != secondOutlet {
+		return apperrors.Conflict("OUTLET_MISMATCH", "cannot merge draft orders from different outlets")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE draft_order_items SET draft_order_id = $1 WHERE draft_order_id = $2`, targetID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE draft_orders SET status = 'merged', updated_at = NOW() WHERE id = $1`, sourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := touchDraftOrder(ctx, tx, targetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Split memindahkan quantity yang diminta ke tab baru dan mengembalikan id tab baru tersebut.
+// diskon item ikut dibagi proporsional terhadap quantity yang dipindah.
+func (repo *DraftOrderRepository) Split(ctx context.Context, orderID int, lines []models.SplitLine) (int, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenDraftOrder(ctx, tx, orderID); err != nil {
+		return 0, err
+	}
+
+	var newID int
+	err = tx.QueryRowContext(ctx, `INSERT INTO draft_orders (outlet_id, table_number, customer_name)
+			SELECT outlet_id, table_number, customer_name FROM draft_orders WHERE id = $1
+			RETURNING id`, orderID).Scan(&newID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range lines {
+		var quantity int
+		var discount float64
+		err := tx.QueryRowContext(ctx,
+			`SELECT quantity, discount FROM draft_order_items WHERE id = $1 AND draft_order_id = $2 FOR UPDATE`,
+			line.ItemID, orderID,
+		).Scan(&quantity, &discount)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, ErrDraftOrderItemNotFound
+			}
+			return 0, err
+		}
+
+		if line.Quantity > quantity {
+			return 0, apperrors.Unprocessable("INVALID_SPLIT", "split quantity exceeds item quantity")
+		}
+
+		if line.Quantity == quantity {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE draft_order_items SET draft_order_id = $1 WHERE id = $2`, newID, line.ItemID)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		movedDiscount := discount * float64(line.Quantity) / float64(quantity)
+		_, err = tx.ExecContext(ctx,
+			`UPDATE draft_order_items SET quantity = quantity - $1, discount = discount - $2 WHERE id = $3`,
+			line.Quantity, movedDiscount, line.ItemID)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO draft_order_items
+				(draft_order_id, product_id, quantity, price, discount)
+			SELECT $1, product_id, $2, price, $3 FROM draft_order_items WHERE id = $4`,
+			newID, line.Quantity, movedDiscount, line.ItemID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := touchDraftOrder(ctx, tx, orderID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return newID, nil
+}
+
+func (repo *DraftOrderRepository) Cancel(ctx context.Context, id int) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenDraftOrder(ctx, tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE draft_orders SET status = 'cancelled', updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Checkout mengubah draft order menjadi sale dalam satu transaksi:
+// stok produk baru dikurangi di sini, lalu sale, item dan pembayarannya disimpan.
+func (repo *DraftOrderRepository) Checkout(ctx context.Context, id int, build BuildSaleFunc) (*models.Sale, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	order, err := getDraftOrder(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.DraftOrderStatusOpen {
+		return nil, ErrDraftOrderNotOpen
+	}
+
+	sale, err := build(order)
+	if err != nil {
+		return nil, err
+	}
+	sale.OutletID = order.OutletID
+
+	// stok dikunci dengan urutan product_id yang sama seperti reserveStock dan transfer stok
+	items := make([]models.SaleItem, len(sale.Items))
+	copy(items, sale.Items)
+	sort.Slice(items, func(i, j int) bool { return items[i].ProductID < items[j].ProductID })
+
+	for _, item := range items {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE outlet_stock SET stock = stock - $1, updated_at = NOW()
+			WHERE outlet_id = $2 AND product_id = $3 AND stock >= $1`,
+			item.Quantity, order.OutletID, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		if rows == 0 {
+			return nil, ErrInsufficientStock
+		}
+	}
+
+	query := `INSERT INTO sales
+				(invoice_number, outlet_id, customer_id, subtotal, discount_total, tax_total, total,
+				paid_amount, change_amount, points_earned, points_redeemed)
+			VALUES
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id, created_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		sale.InvoiceNumber,
+		sale.OutletID,
+		sale.CustomerID,
+		sale.Subtotal,
+		sale.DiscountTotal,
+		sale.TaxTotal,
+		sale.Total,
+		sale.PaidAmount,
+		sale.ChangeAmount,
+		sale.PointsEarned,
+		sale.PointsRedeemed,
+	).Scan(&sale.ID, &sale.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sale.Items {
+		item := &sale.Items[i]
+		item.SaleID = sale.ID
+		err := tx.QueryRowContext(ctx, `INSERT INTO sale_items
+				(sale_id, product_id, product_name, quantity, price, discount, subtotal)
+			VALUES
+				($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id`,
+			sale.ID, item.ProductID, item.ProductName, item.Quantity, item.Price, item.Discount, item.Subtotal,
+		).Scan(&item.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range sale.Payments {
+		payment := &sale.Payments[i]
+		payment.SaleID = sale.ID
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO sale_payments (sale_id, method, amount) VALUES ($1, $2, $3) RETURNING id`,
+			sale.ID, payment.Method, payment.Amount,
+		).Scan(&payment.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE draft_orders SET status = 'paid', sale_id = $1, updated_at = NOW() WHERE id = $2`, sale.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// getDraftOrder membaca draft order beserta item-nya. tab milik outlet lain
+// dianggap tidak ada kalau context membawa outlet.
+func getDraftOrder(ctx context.Context, q DBTX, id int, forUpdate bool) (*models.DraftOrder, error) {
+	query := `SELECT id, outlet_id, table_number, customer_name, status, sale_id, created_at, updated_at
+			FROM draft_orders
+			WHERE id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	var order models.DraftOrder
+	err := q.QueryRowContext(ctx, query, id).Scan(
+		&order.ID,
+		&order.OutletID,
+		&order.TableNumber,
+		&order.CustomerName,
+		&order.Status,
+		&order.SaleID,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDraftOrderNotFound
+		}
+		return nil, err
+	}
+
+	if outletID, ok := outlet.IDFromContext(ctx); ok && outletID != order.OutletID {
+		return nil, ErrDraftOrderNotFound
+	}
+
+	order.Items, err = scanDraftOrderItems(q.QueryContext(ctx, draftOrderItemSelect+`
+			WHERE i.draft_order_id = $1
+			ORDER BY i.id`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range order.Items {
+		order.Total += item.Subtotal
+	}
+
+	return &order, nil
+}
+
+func scanDraftOrderItems(rows *sql.Rows, err error) ([]models.DraftOrderItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.DraftOrderItem, 0)
+	for rows.Next() {
+		var item models.DraftOrderItem
+		err := rows.Scan(
+			&item.ID,
+			&item.DraftOrderID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+			&item.Price,
+			&item.Discount,
+			&item.Subtotal,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// lockOpenDraftOrder mengunci baris draft order, memastikan statusnya masih open
+// dan milik outlet yang sedang dilayani, lalu mengembalikan outlet_id-nya.
+func lockOpenDraftOrder(ctx context.Context, tx DBTX, id int) (int, error) {
+	var status string
+	var outletID int
+	err := tx.QueryRowContext(ctx,
+		`SELECT status, outlet_id FROM draft_orders WHERE id = $1 FOR UPDATE`, id,
+	).Scan(&status, &outletID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrDraftOrderNotFound
+		}
+		return 0, err
+	}
+
+	if current, ok := outlet.IDFromContext(ctx); ok && current != outletID {
+		return 0, ErrDraftOrderNotFound
+	}
+
+	if status != models.DraftOrderStatusOpen {
+		return 0, ErrDraftOrderNotOpen
+	}
+
+	return outletID, nil
+}
+
+func touchDraftOrder(ctx context.Context, tx DBTX, id int) error {
+	_, err := tx.ExecContext(ctx, `UPDATE draft_orders SET updated_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func insertDraftOrderItem(ctx context.Context, tx DBTX, outletID, orderID int, item *models.DraftOrderItem) error {
+	price, err := reserveStock(ctx, tx, outletID, item.ProductID, item.Quantity, 0)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO draft_order_items
+				(draft_order_id, product_id, quantity, price, discount)
+			VALUES
+				($1, $2, $3, $4, $5)
+			RETURNING id, created_at`
+
+	err = tx.QueryRowContext(ctx, query, orderID, item.ProductID, item.Quantity, price, item.Discount).Scan(
+		&item.ID,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.DraftOrderID = orderID
+	item.Price = price
+	item.Subtotal = float64(item.Quantity)*price - item.Discount
+
+	return nil
+}
+
+// reserveStock memastikan stok produk di outlet masih cukup setelah dikurangi semua reservasi
+// di tab open outlet yang sama (kecuali excludeItemID), dan mengembalikan harga produk
+// yang berlaku di outlet tersebut. baris stok dikunci lewat lockOutletStock supaya dua kasir,
+// atau kasir dan transfer stok, tidak bisa memakai stok yang sama.
+func reserveStock(ctx context.Context, tx DBTX, outletID, productID, quantity, excludeItemID int) (float64, error) {
+	stock, err := lockOutletStock(ctx, tx, outletID, productID)
+	if err != nil {
+		return 0, err
+	}
+
+	var price float64
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(os.price, p.price)
+			FROM products p
+			LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = $2
+			WHERE p.id = $1`, productID, outletID,
+	).Scan(&price)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrProductNotFound
+		}
+		return 0, err
+	}
+
+	reserved, err := reservedStock(ctx, tx, outletID, productID, excludeItemID)
+	if err != nil {
+		return 0, err
+	}
+
+	if stock-reserved < quantity {
+		return 0, ErrInsufficientStock
+	}
+
+	return price, nil
+}
+
+// lockOutletStock mengunci baris stok produk di outlet dan mengembalikan stoknya, 0 kalau produk
+// belum punya stok di outlet itu. semua pengecekan "stok dikurangi reservasi" (reserveStock dan
+// transfer stok) mengunci baris ini. kalau beberapa produk dikunci dalam satu transaksi,
+// urutkan berdasarkan product_id untuk menghindari deadlock.
+func lockOutletStock(ctx context.Context, tx DBTX, outletID, productID int) (int, error) {
+	var stock int
+	err := tx.QueryRowContext(ctx,
+		`SELECT stock FROM outlet_stock WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE`,
+		outletID, productID,
+	).Scan(&stock)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	return stock, nil
+}
+
+// reservedStock menghitung quantity produk yang sedang dipegang tab open di sebuah outlet
+func reservedStock(ctx context.Context, q DBTX, outletID, productID, excludeItemID int) (int, error) {
+	var reserved int
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(SUM(i.quantity), 0)
+			FROM draft_order_items i
+			JOIN draft_orders o ON o.id = i.draft_order_id
+			WHERE o.status = 'open' AND o.outlet_id = $1 AND i.product_id = $2 AND i.id <> $3`,
+		outletID, productID, excludeItemID,
+	).Scan(&reserved)
+	return reserved, err
+}
@@ -0,0 +1,301 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftOrderRepository_Integration_CreateAndGet(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 10)
+
+	order := &models.DraftOrder{
+		TableNumber: strPtr("A3"),
+		Items:       []models.DraftOrderItem{{ProductID: kopi.ID, Quantity: 2, Discount: 1000}},
+	}
+	require.NoError(t, repo.Create(ctx, order))
+	assert.Equal(t, models.DraftOrderStatusOpen, order.Status)
+	assert.Equal(t, 1, order.OutletID)
+	assert.Equal(t, 15000.0, order.Items[0].Price)
+	assert.Equal(t, 29000.0, order.Items[0].Subtotal)
+
+	found, err := repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "A3", *found.TableNumber)
+	require.Len(t, found.Items, 1)
+	assert.Equal(t, "Kopi", found.Items[0].ProductName)
+	assert.Equal(t, 29000.0, found.Total)
+
+	// tab outlet lain tidak terlihat
+	_, err = repo.GetByID(outletCtx(branch.ID), order.ID)
+	assert.ErrorIs(t, err, ErrDraftOrderNotFound)
+	_, err = repo.GetByID(ctx, 99)
+	assert.ErrorIs(t, err, ErrDraftOrderNotFound)
+
+	empty := seedDraftOrder(t, ctx, db)
+	open, err := repo.GetOpen(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, order.ID, open[0].ID)
+	assert.Equal(t, 29000.0, open[0].Total)
+	assert.Equal(t, empty.ID, open[1].ID)
+	assert.NotNil(t, open[1].Items)
+	assert.Empty(t, open[1].Items)
+
+	open, err = repo.GetOpen(outletCtx(branch.ID))
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
+
+func TestDraftOrderRepository_Integration_ReservesStock(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 5)
+	order := seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 3})
+
+	// 3 dari 5 sudah dipegang tab pertama
+	err := repo.Create(ctx, &models.DraftOrder{Items: []models.DraftOrderItem{{ProductID: kopi.ID, Quantity: 3}}})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	err = repo.AddItem(ctx, order.ID, &models.DraftOrderItem{ProductID: kopi.ID, Quantity: 3})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	err = repo.AddItem(ctx, order.ID, &models.DraftOrderItem{ProductID: 99, Quantity: 1})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	// quantity item itu sendiri tidak dihitung saat diubah
+	err = repo.UpdateItem(ctx, order.ID, order.Items[0].ID, &models.DraftOrderItem{Quantity: 5})
+	require.NoError(t, err)
+	err = repo.UpdateItem(ctx, order.ID, order.Items[0].ID, &models.DraftOrderItem{Quantity: 6})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	// stok baru berkurang saat checkout
+	assert.Equal(t, 5, outletStock(t, db, 1, kopi.ID))
+}
+
+func TestDraftOrderRepository_Integration_Items(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 10)
+	teh := seedProduct(t, ctx, db, category.ID, "Teh", 5000, 10)
+	order := seedDraftOrder(t, ctx, db)
+
+	item := &models.DraftOrderItem{ProductID: teh.ID, Quantity: 2}
+	require.NoError(t, repo.AddItem(ctx, order.ID, item))
+	assert.Equal(t, order.ID, item.DraftOrderID)
+	require.NoError(t, repo.AddItem(ctx, order.ID, &models.DraftOrderItem{ProductID: kopi.ID, Quantity: 1}))
+
+	require.NoError(t, repo.UpdateItem(ctx, order.ID, item.ID, &models.DraftOrderItem{Quantity: 4, Discount: 2000}))
+	require.NoError(t, repo.UpdateTag(ctx, order.ID, &models.DraftOrder{TableNumber: strPtr("B1"), CustomerName: strPtr("Umam")}))
+
+	found, err := repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "B1", *found.TableNumber)
+	assert.Equal(t, "Umam", *found.CustomerName)
+	assert.NotNil(t, found.UpdatedAt)
+	require.Len(t, found.Items, 2)
+	assert.Equal(t, 4, found.Items[0].Quantity)
+	assert.Equal(t, 18000.0, found.Items[0].Subtotal)
+	assert.Equal(t, 33000.0, found.Total)
+
+	require.NoError(t, repo.RemoveItem(ctx, order.ID, item.ID))
+	err = repo.RemoveItem(ctx, order.ID, item.ID)
+	assert.ErrorIs(t, err, ErrDraftOrderItemNotFound)
+	err = repo.UpdateItem(ctx, order.ID, item.ID, &models.DraftOrderItem{Quantity: 1})
+	assert.ErrorIs(t, err, ErrDraftOrderItemNotFound)
+
+	found, err = repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Items, 1)
+	assert.Equal(t, "Kopi", found.Items[0].ProductName)
+}
+
+func TestDraftOrderRepository_Integration_MergeAndSplit(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 10)
+	teh := seedProduct(t, ctx, db, category.ID, "Teh", 5000, 10)
+
+	target := seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 4, Discount: 4000})
+	source := seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: teh.ID, Quantity: 1})
+
+	require.NoError(t, repo.Merge(ctx, target.ID, source.ID))
+
+	merged, err := repo.GetByID(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DraftOrderStatusMerged, merged.Status)
+	assert.Empty(t, merged.Items)
+
+	err = repo.Merge(ctx, target.ID, source.ID)
+	assert.ErrorIs(t, err, ErrDraftOrderNotOpen)
+
+	// 1 dari 4 kopi dipindah, diskonnya ikut dibagi
+	newID, err := repo.Split(ctx, target.ID, []models.SplitLine{
+		{ItemID: target.Items[0].ID, Quantity: 1},
+		{ItemID: source.Items[0].ID, Quantity: 1},
+	})
+	require.NoError(t, err)
+
+	split, err := repo.GetByID(ctx, newID)
+	require.NoError(t, err)
+	require.Len(t, split.Items, 2)
+	// item teh dipindah utuh, item kopi dibuat baru untuk quantity yang dipindah
+	assert.Equal(t, source.Items[0].ID, split.Items[0].ID)
+	assert.Equal(t, teh.ID, split.Items[0].ProductID)
+	assert.Equal(t, kopi.ID, split.Items[1].ProductID)
+	assert.Equal(t, 1, split.Items[1].Quantity)
+	assert.Equal(t, 1000.0, split.Items[1].Discount)
+
+	remaining, err := repo.GetByID(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, remaining.Items, 1)
+	assert.Equal(t, 3, remaining.Items[0].Quantity)
+	assert.Equal(t, 3000.0, remaining.Items[0].Discount)
+
+	_, err = repo.Split(ctx, target.ID, []models.SplitLine{{ItemID: target.Items[0].ID, Quantity: 4}})
+	assertAppError(t, err, apperrors.ErrUnprocessable, "INVALID_SPLIT")
+	_, err = repo.Split(ctx, target.ID, []models.SplitLine{{ItemID: 99, Quantity: 1}})
+	assert.ErrorIs(t, err, ErrDraftOrderItemNotFound)
+
+	// tab dari outlet lain tidak boleh digabung (tanpa outlet di context supaya lolos pengecekan akses)
+	other := seedDraftOrder(t, outletCtx(branch.ID), db)
+	err = repo.Merge(context.Background(), target.ID, other.ID)
+	assertAppError(t, err, apperrors.ErrConflict, "OUTLET_MISMATCH")
+	err = repo.Merge(ctx, target.ID, other.ID)
+	assert.ErrorIs(t, err, ErrDraftOrderNotFound)
+}
+
+func TestDraftOrderRepository_Integration_Cancel(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 2)
+	order := seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 2})
+
+	require.NoError(t, repo.Cancel(ctx, order.ID))
+
+	err := repo.Cancel(ctx, order.ID)
+	assert.ErrorIs(t, err, ErrDraftOrderNotOpen)
+	err = repo.AddItem(ctx, order.ID, &models.DraftOrderItem{ProductID: kopi.ID, Quantity: 1})
+	assert.ErrorIs(t, err, ErrDraftOrderNotOpen)
+	err = repo.UpdateTag(ctx, order.ID, &models.DraftOrder{})
+	assert.ErrorIs(t, err, ErrDraftOrderNotOpen)
+	err = repo.Cancel(ctx, 99)
+	assert.ErrorIs(t, err, ErrDraftOrderNotFound)
+
+	// reservasi tab yang dibatalkan dilepas
+	seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 2})
+}
+
+func TestDraftOrderRepository_Integration_Checkout(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 10)
+	customer := seedCustomer(t, db, "08123456789")
+	order := seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 2})
+
+	build := func(order *models.DraftOrder) (*models.Sale, error) {
+		sale := &models.Sale{
+			InvoiceNumber: "INV-TEST-1",
+			CustomerID:    &customer.ID,
+			Subtotal:      order.Total,
+			Total:         order.Total,
+			PaidAmount:    50000,
+			ChangeAmount:  50000 - order.Total,
+			PointsEarned:  30,
+			Payments:      []models.SalePayment{{Method: "cash", Amount: 50000}},
+		}
+		for _, item := range order.Items {
+			sale.Items = append(sale.Items, models.SaleItem{
+				ProductID: item.ProductID, ProductName: item.ProductName, Quantity: item.Quantity,
+				Price: item.Price, Subtotal: item.Subtotal,
+			})
+		}
+		return sale, nil
+	}
+
+	sale, err := repo.Checkout(ctx, order.ID, build)
+	require.NoError(t, err)
+	assert.NotZero(t, sale.ID)
+	assert.Equal(t, 1, sale.OutletID)
+	assert.Equal(t, sale.ID, sale.Items[0].SaleID)
+	assert.NotZero(t, sale.Payments[0].ID)
+	assert.Equal(t, 8, outletStock(t, db, 1, kopi.ID))
+
+	paid, err := repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DraftOrderStatusPaid, paid.Status)
+	assert.Equal(t, sale.ID, *paid.SaleID)
+
+	_, err = repo.Checkout(ctx, order.ID, build)
+	assert.ErrorIs(t, err, ErrDraftOrderNotOpen)
+
+	stored, err := NewSaleRepository(db).GetByID(ctx, sale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-TEST-1", stored.InvoiceNumber)
+	assert.Equal(t, 30000.0, stored.Subtotal)
+	assert.Equal(t, 20000.0, stored.ChangeAmount)
+	assert.Equal(t, customer.ID, *stored.CustomerID)
+	assert.Equal(t, 30, stored.PointsEarned)
+	require.Len(t, stored.Items, 1)
+	assert.Equal(t, "Kopi", stored.Items[0].ProductName)
+	require.Len(t, stored.Payments, 1)
+	assert.Equal(t, "cash", stored.Payments[0].Method)
+}
+
+func TestDraftOrderRepository_Integration_CheckoutRollsBack(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewDraftOrderRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 3)
+	teh := seedProduct(t, ctx, db, category.ID, "Teh", 5000, 3)
+	order := seedDraftOrder(t, ctx, db,
+		models.DraftOrderItem{ProductID: kopi.ID, Quantity: 2},
+		models.DraftOrderItem{ProductID: teh.ID, Quantity: 1},
+	)
+
+	// stok teh diubah di luar tab, checkout harus gagal tanpa mengurangi stok kopi
+	testDB.Exec(t, `UPDATE outlet_stock SET stock = 0 WHERE product_id = $1`, teh.ID)
+
+	_, err := repo.Checkout(ctx, order.ID, func(order *models.DraftOrder) (*models.Sale, error) {
+		sale := &models.Sale{InvoiceNumber: "INV-TEST-2"}
+		for _, item := range order.Items {
+			sale.Items = append(sale.Items, models.SaleItem{ProductID: item.ProductID, ProductName: item.ProductName, Quantity: item.Quantity})
+		}
+		return sale, nil
+	})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Equal(t, 3, outletStock(t, db, 1, kopi.ID))
+
+	found, err := repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DraftOrderStatusOpen, found.Status)
+
+	// error dari build juga membatalkan transaksi
+	_, err = repo.Checkout(ctx, order.ID, func(*models.DraftOrder) (*models.Sale, error) {
+		return nil, apperrors.Unprocessable("INSUFFICIENT_PAYMENT", "insufficient payment")
+	})
+	assertAppError(t, err, apperrors.ErrUnprocessable, "INSUFFICIENT_PAYMENT")
+}
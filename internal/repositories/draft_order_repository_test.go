@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftOrderRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDraftOrderRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .* FROM draft_orders WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "outlet_id", "table_number", "customer_name", "status", "sale_id", "created_at", "updated_at"}).
+			AddRow(1, 1, "A1", nil, "open", nil, now, nil))
+
+	mock.ExpectQuery(`SELECT .* FROM draft_order_items i JOIN products p ON p.id = i.product_id WHERE i.draft_order_id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "draft_order_id", "product_id", "name", "quantity", "price", "discount", "subtotal", "created_at"}).
+			AddRow(1, 1, 1, "Nasi Goreng", 2, 15000.0, 0.0, 30000.0, now).
+			AddRow(2, 1, 2, "Es Teh", 1, 5000.0, 1000.0, 4000.0, now))
+
+	order, err := repo.GetByID(outletContext(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, order.OutletID)
+	assert.Equal(t, "A1", *order.TableNumber)
+	assert.Len(t, order.Items, 2)
+	assert.Equal(t, 34000.0, order.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDraftOrderRepository_Cancel_NotOpen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDraftOrderRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT status, outlet_id FROM draft_orders WHERE id = $1 FOR UPDATE`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "outlet_id"}).AddRow("paid", 1))
+	mock.ExpectRollback()
+
+	err = repo.Cancel(outletContext(), 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "draft order is not open", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDraftOrderRepository_AddItem_InsufficientStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDraftOrderRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT status, outlet_id FROM draft_orders WHERE id = $1 FOR UPDATE`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "outlet_id"}).AddRow("open", 1))
+	// baris stok yang sama dengan yang dikunci transfer stok
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT stock FROM outlet_stock WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE`)).
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(10))
+	mock.ExpectQuery(`SELECT COALESCE\(os.price, p.price\) FROM products p LEFT JOIN outlet_stock os`).
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(15000.0))
+	// 8 sudah direservasi oleh tab lain di outlet yang sama, sisa 2
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(i.quantity\), 0\) FROM draft_order_items i`).
+		WithArgs(1, 5, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(8))
+	mock.ExpectRollback()
+
+	err = repo.AddItem(outletContext(), 1, &models.DraftOrderItem{ProductID: 5, Quantity: 3})
+
+	assert.Error(t, err)
+	assert.Equal(t, "insufficient stock", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDraftOrderRepository_GetByID_OtherOutlet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDraftOrderRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .* FROM draft_orders WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "outlet_id", "table_number", "customer_name", "status", "sale_id", "created_at", "updated_at"}).
+			AddRow(1, 2, "A1", nil, "open", nil, now, nil))
+
+	order, err := repo.GetByID(outletContext(), 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, "draft order not found", err.Error())
+	assert.Nil(t, order)
+}
+
+func TestDraftOrderRepository_GetOpen_RequiresOutlet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewDraftOrderRepository(db)
+
+	orders, err := repo.GetOpen(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, orders)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import "fajar7xx/go-kasir-umam-ds/internal/apperrors"
+
+// error yang dikembalikan repository. cek dengan errors.Is, bukan dengan membandingkan pesan
+var (
+	ErrProductNotFound           = apperrors.NotFound("PRODUCT_NOT_FOUND", "product not found")
+	ErrCategoryNotFound          = apperrors.NotFound("CATEGORY_NOT_FOUND", "category not found")
+	ErrCustomerNotFound          = apperrors.NotFound("CUSTOMER_NOT_FOUND", "customer not found")
+	ErrOutletNotFound            = apperrors.NotFound("OUTLET_NOT_FOUND", "outlet not found")
+	ErrSaleNotFound              = apperrors.NotFound("SALE_NOT_FOUND", "sale not found")
+	ErrDraftOrderNotFound        = apperrors.NotFound("DRAFT_ORDER_NOT_FOUND", "draft order not found")
+	ErrDraftOrderItemNotFound    = apperrors.NotFound("DRAFT_ORDER_ITEM_NOT_FOUND", "draft order item not found")
+	ErrStockTransferNotFound     = apperrors.NotFound("STOCK_TRANSFER_NOT_FOUND", "stock transfer not found")
+	ErrProductImageNotFound      = apperrors.NotFound("PRODUCT_IMAGE_NOT_FOUND", "product image not found")
+	ErrInsufficientStock         = apperrors.Conflict("INSUFFICIENT_STOCK", "insufficient stock")
+	ErrDraftOrderNotOpen         = apperrors.Conflict("DRAFT_ORDER_NOT_OPEN", "draft order is not open")
+	ErrStockTransferNotInTransit = apperrors.Conflict("STOCK_TRANSFER_NOT_IN_TRANSIT", "stock transfer is not in transit")
+	ErrVersionConflict           = apperrors.New(apperrors.ErrPrecondition, "PRECONDITION_FAILED", "resource was modified by another request, reload and try again")
+	ErrInsufficientPoints        = apperrors.Unprocessable("INSUFFICIENT_POINTS", "insufficient loyalty points")
+)
+
+// pesan khusus untuk pelanggaran constraint tertentu, selain itu cukup diterjemahkan oleh apperrors.FromDB
+var (
+	productConstraints = map[string]*apperrors.Error{
+		"products_category_id_fkey":            apperrors.Unprocessable("CATEGORY_NOT_FOUND", "category not found"),
+		"sale_items_product_id_fkey":           apperrors.Conflict("PRODUCT_IN_USE", "product is still used by sales"),
+		"draft_order_items_product_id_fkey":    apperrors.Conflict("PRODUCT_IN_USE", "product is still used by open draft orders"),
+		"stock_transfer_items_product_id_fkey": apperrors.Conflict("PRODUCT_IN_USE", "product is still used by stock transfers"),
+	}
+	categoryConstraints = map[string]*apperrors.Error{
+		"products_category_id_fkey": apperrors.Conflict("CATEGORY_IN_USE", "category still has products"),
+	}
+	customerConstraints = map[string]*apperrors.Error{
+		"customers_phone_key": apperrors.Conflict("PHONE_ALREADY_REGISTERED", "phone number is already registered"),
+	}
+	outletConstraints = map[string]*apperrors.Error{
+		"outlets_code_key": apperrors.Conflict("OUTLET_CODE_TAKEN", "outlet code is already used"),
+	}
+	outletPriceConstraints = map[string]*apperrors.Error{
+		"outlet_stock_outlet_id_fkey":  ErrOutletNotFound,
+		"outlet_stock_product_id_fkey": ErrProductNotFound,
+	}
+	stockTransferConstraints = map[string]*apperrors.Error{
+		"stock_transfers_to_outlet_id_fkey": apperrors.Unprocessable("OUTLET_NOT_FOUND", "destination outlet not found"),
+	}
+)
+
+// translateDBError menerjemahkan error database menjadi error domain,
+// memakai pesan dari constraints kalau constraint yang dilanggar ada di sana
+func translateDBError(err error, constraints map[string]*apperrors.Error) error {
+	for name, appErr := range constraints {
+		if apperrors.IsConstraint(err, name) {
+			return apperrors.Wrap(appErr.Kind, appErr.Code, appErr.Message, err)
+		}
+	}
+	return apperrors.FromDB(err)
+}
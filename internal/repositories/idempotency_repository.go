@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"time"
+)
+
+type IdempotencyRepositoryInterface interface {
+	Reserve(ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration) (*models.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key string, statusCode int, headers map[string]string, body []byte) error
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyRepository struct {
+	db DBTX
+}
+
+func NewIdempotencyRepository(db DBTX) IdempotencyRepositoryInterface {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve mencoba mengklaim key untuk request baru. reserved bernilai true kalau key belum
+// pernah dipakai, sudah kedaluwarsa, atau request sebelumnya macet lebih dari lockTimeout
+// (misalnya server mati di tengah jalan). selain itu record yang sudah ada dikembalikan.
+func (repo *IdempotencyRepository) Reserve(ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration) (*models.IdempotencyRecord, bool, error) {
+	query := `INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+			VALUES ($1, $2, NOW() + $3 * INTERVAL '1 second')
+			ON CONFLICT (key) DO UPDATE SET
+				fingerprint = EXCLUDED.fingerprint,
+				status_code = NULL,
+				response_headers = NULL,
+				response_body = NULL,
+				created_at = NOW(),
+				expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= NOW()
+				OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at <= NOW() - $4 * INTERVAL '1 second')
+			RETURNING key`
+
+	// dicoba dua kali: record bisa saja dihapus (Release) di antara INSERT dan SELECT
+	for attempt := 0; ; attempt++ {
+		var reserved string
+		err := repo.db.QueryRowContext(ctx, query, key, fingerprint, ttl.Seconds(), lockTimeout.Seconds()).Scan(&reserved)
+		if err == nil {
+			return nil, true, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, false, err
+		}
+
+		record, err := repo.get(ctx, key)
+		if err == sql.ErrNoRows && attempt == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return record, false, nil
+	}
+}
+
+func (repo *IdempotencyRepository) get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	var statusCode sql.NullInt64
+	var headers []byte
+	err := repo.db.QueryRowContext(ctx, `SELECT key, fingerprint, status_code, response_headers, response_body, created_at, expires_at
+			FROM idempotency_keys WHERE key = $1`, key,
+	).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		record.StatusCode = &code
+	}
+
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &record.Headers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &record, nil
+}
+
+// Complete menyimpan response dari request pertama supaya bisa dikirim ulang
+func (repo *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, headers map[string]string, body []byte) error {
+	encoded, err := json.Marshal(headers)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.db.ExecContext(ctx, `UPDATE idempotency_keys
+			SET status_code = $1, response_headers = $2, response_body = $3
+			WHERE key = $4`,
+		statusCode, string(encoded), body, key)
+	return err
+}
+
+// Release menghapus key yang gagal diproses (misalnya error 5xx) supaya request bisa diulang
+func (repo *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := repo.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL`, key)
+	return err
+}
+
+// DeleteExpired membersihkan key yang sudah lewat TTL
+func (repo *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := repo.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_Integration_Lifecycle(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewIdempotencyRepository(db)
+	ctx := context.Background()
+
+	record, reserved, err := repo.Reserve(ctx, "abc", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, record)
+
+	// request kedua saat yang pertama masih diproses
+	record, reserved, err = repo.Reserve(ctx, "abc", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	require.NotNil(t, record)
+	assert.False(t, record.Completed())
+	assert.Equal(t, "f1", record.Fingerprint)
+
+	headers := map[string]string{"Content-Type": "application/json", "Location": "/api/v1/products/1"}
+	require.NoError(t, repo.Complete(ctx, "abc", 201, headers, []byte(`{"data":{"id":1}}`)))
+
+	record, reserved, err = repo.Reserve(ctx, "abc", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	require.True(t, record.Completed())
+	assert.Equal(t, 201, *record.StatusCode)
+	assert.Equal(t, headers, record.Headers)
+	assert.Equal(t, `{"data":{"id":1}}`, string(record.Body))
+	assert.True(t, record.ExpiresAt.After(record.CreatedAt))
+
+	// key yang sudah selesai tidak bisa dilepas
+	require.NoError(t, repo.Release(ctx, "abc"))
+	_, reserved, err = repo.Reserve(ctx, "abc", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+}
+
+func TestIdempotencyRepository_Integration_ReleaseAndTakeOver(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewIdempotencyRepository(db)
+	ctx := context.Background()
+
+	_, reserved, err := repo.Reserve(ctx, "released", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// request gagal melepas key sehingga bisa diulang
+	require.NoError(t, repo.Release(ctx, "released"))
+	_, reserved, err = repo.Reserve(ctx, "released", "f2", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	// request yang macet lebih lama dari lockTimeout diambil alih
+	_, reserved, err = repo.Reserve(ctx, "stuck", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	testDB.Exec(t, `UPDATE idempotency_keys SET created_at = NOW() - INTERVAL '2 minutes' WHERE key = 'stuck'`)
+
+	_, reserved, err = repo.Reserve(ctx, "stuck", "f1", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestIdempotencyRepository_Integration_Expired(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewIdempotencyRepository(db)
+	ctx := context.Background()
+
+	for _, key := range []string{"old", "new"} {
+		_, reserved, err := repo.Reserve(ctx, key, "f1", time.Hour, time.Minute)
+		require.NoError(t, err)
+		require.True(t, reserved)
+		require.NoError(t, repo.Complete(ctx, key, 200, nil, []byte(`{}`)))
+	}
+	testDB.Exec(t, `UPDATE idempotency_keys SET expires_at = NOW() - INTERVAL '1 second' WHERE key = 'old'`)
+
+	// key kedaluwarsa boleh dipakai ulang meskipun belum dihapus
+	_, reserved, err := repo.Reserve(ctx, "old", "f2", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	testDB.Exec(t, `UPDATE idempotency_keys SET expires_at = NOW() - INTERVAL '1 second'`)
+	deleted, err := repo.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
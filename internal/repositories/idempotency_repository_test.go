@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepository_Reserve_New(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(db)
+
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+		WithArgs("abc", "f1", 86400.0, 60.0).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("abc"))
+
+	record, reserved, err := repo.Reserve(context.Background(), "abc", "f1", 24*time.Hour, time.Minute)
+
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, record)
+}
+
+func TestIdempotencyRepository_Reserve_Existing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(db)
+
+	now := time.Now()
+	// key masih berlaku, INSERT ... ON CONFLICT tidak mengembalikan baris
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+		WithArgs("abc", "f1", 86400.0, 60.0).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}))
+	mock.ExpectQuery(`SELECT key, fingerprint, status_code, response_headers, response_body, created_at, expires_at`).
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "response_headers", "response_body", "created_at", "expires_at"}).
+			AddRow("abc", "f1", 201, []byte(`{"Content-Type":"application/json"}`), []byte(`{"data":{}}`), now, now.Add(time.Hour)))
+
+	record, reserved, err := repo.Reserve(context.Background(), "abc", "f1", 24*time.Hour, time.Minute)
+
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, record.Completed())
+	assert.Equal(t, 201, *record.StatusCode)
+	assert.Equal(t, "application/json", record.Headers["Content-Type"])
+	assert.Equal(t, []byte(`{"data":{}}`), record.Body)
+}
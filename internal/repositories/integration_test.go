@@ -0,0 +1,125 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/internal/testdb"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// test integrasi repository memakai Postgres sungguhan dengan skema dari migrations,
+// jalankan dengan: go test -tags integration ./internal/repositories/
+
+var testDB *testdb.Instance
+
+func TestMain(m *testing.M) {
+	instance, err := testdb.Start()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	testDB = instance
+
+	code := m.Run()
+
+	if err := instance.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+// integrationDB mengosongkan database dan mengembalikannya untuk satu test
+func integrationDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	testDB.Reset(t)
+	return testDB.DB
+}
+
+// outletCtx mensimulasikan request dengan header X-Outlet-ID
+func outletCtx(id int) context.Context {
+	return outlet.WithID(context.Background(), id)
+}
+
+// assertAppError memastikan err adalah error domain dengan kind dan code tertentu
+func assertAppError(t *testing.T, err error, kind error, code string) {
+	t.Helper()
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, kind)
+	appErr, ok := apperrors.As(err)
+	require.True(t, ok, "expected *apperrors.Error, got %T: %v", err, err)
+	assert.Equal(t, code, appErr.Code)
+}
+
+func seedCategory(t *testing.T, db *sql.DB, name string) *models.Category {
+	t.Helper()
+
+	category := &models.Category{Name: name}
+	require.NoError(t, NewCategoryRepository(db).Create(context.Background(), category))
+	return category
+}
+
+// seedProduct membuat produk dengan stok awal di outlet yang dibawa ctx
+func seedProduct(t *testing.T, ctx context.Context, db *sql.DB, categoryID int, name string, price float64, stock int) *models.ProductResponse {
+	t.Helper()
+
+	product, err := NewProductRepository(db).Create(ctx, &models.Product{
+		Name:       name,
+		Price:      price,
+		Stock:      stock,
+		CategoryID: categoryID,
+	})
+	require.NoError(t, err)
+	return product
+}
+
+func seedOutlet(t *testing.T, db *sql.DB, code string) *models.Outlet {
+	t.Helper()
+
+	o := &models.Outlet{Code: code, Name: "Outlet " + code}
+	require.NoError(t, NewOutletRepository(db).Create(context.Background(), o))
+	return o
+}
+
+func seedCustomer(t *testing.T, db *sql.DB, phone string) *models.Customer {
+	t.Helper()
+
+	customer := &models.Customer{Name: "Pelanggan " + phone, Phone: phone}
+	require.NoError(t, NewCustomerRepository(db).Create(context.Background(), customer))
+	return customer
+}
+
+// seedDraftOrder membuka tab di outlet yang dibawa ctx berisi quantity dari setiap produk
+func seedDraftOrder(t *testing.T, ctx context.Context, db *sql.DB, items ...models.DraftOrderItem) *models.DraftOrder {
+	t.Helper()
+
+	order := &models.DraftOrder{Items: items}
+	require.NoError(t, NewDraftOrderRepository(db).Create(ctx, order))
+	return order
+}
+
+// outletStock membaca stok produk langsung dari tabel
+func outletStock(t *testing.T, db *sql.DB, outletID, productID int) int {
+	t.Helper()
+
+	var stock int
+	err := db.QueryRow(`SELECT stock FROM outlet_stock WHERE outlet_id = $1 AND product_id = $2`,
+		outletID, productID).Scan(&stock)
+	require.NoError(t, err)
+	return stock
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+type LoyaltyRepositoryInterface interface {
+	GetLedger(ctx context.Context, customerID int) ([]models.LoyaltyEntry, error)
+	Balance(ctx context.Context, customerID int) (int, error)
+	Earn(ctx context.Context, entry *models.LoyaltyEntry) error
+	Redeem(ctx context.Context, customerID, points int, saleID *int) (*models.LoyaltyEntry, error)
+	Expire(ctx context.Context, customerID int) error
+	// WithTx mengembalikan repository yang sama tapi memakai transaksi dari TxManager.WithinTx
+	WithTx(tx DBTX) LoyaltyRepositoryInterface
+}
+
+type LoyaltyRepository struct {
+	db DBTX
+}
+
+func NewLoyaltyRepository(db DBTX) LoyaltyRepositoryInterface {
+	return &LoyaltyRepository{
+		db: db,
+	}
+}
+
+func (repo *LoyaltyRepository) WithTx(tx DBTX) LoyaltyRepositoryInterface {
+	return NewLoyaltyRepository(tx)
+}
+
+func (repo *LoyaltyRepository) GetLedger(ctx context.Context, customerID int) ([]models.LoyaltyEntry, error) {
+	query := `SELECT id, customer_id, sale_id, type, points, remaining, expires_at, created_at
+			FROM loyalty_ledger
+			WHERE customer_id = $1
+			ORDER BY created_at DESC, id DESC`
+
+	rows, err := repo.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.LoyaltyEntry, 0)
+	for rows.Next() {
+		var entry models.LoyaltyEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.CustomerID,
+			&entry.SaleID,
+			&entry.Type,
+			&entry.Points,
+			&entry.Remaining,
+			&entry.ExpiresAt,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Balance menjumlahkan sisa poin yang belum kedaluwarsa
+func (repo *LoyaltyRepository) Balance(ctx context.Context, customerID int) (int, error) {
+	query := `SELECT COALESCE(SUM(remaining), 0)
+			FROM loyalty_ledger
+			WHERE customer_id = $1
+				AND type = 'earn'
+				AND (expires_at IS NULL OR expires_at > NOW())`
+
+	var balance int
+	err := repo.db.QueryRowContext(ctx, query, customerID).Scan(&balance)
+	return balance, err
+}
+
+func (repo *LoyaltyRepository) Earn(ctx context.Context, entry *models.LoyaltyEntry) error {
+	entry.Type = models.LoyaltyEntryEarn
+
+	query := `INSERT INTO loyalty_ledger
+				(customer_id, sale_id, type, points, remaining, expires_at)
+			VALUES
+				($1, $2, $3, $4, $4, $5)
+			RETURNING id, remaining, created_at`
+
+	return repo.db.QueryRowContext(ctx, query,
+		entry.CustomerID,
+		entry.SaleID,
+		entry.Type,
+		entry.Points,
+		entry.ExpiresAt,
+	).Scan(
+		&entry.ID,
+		&entry.Remaining,
+		&entry.CreatedAt,
+	)
+}
+
+// Redeem memakai poin mulai dari yang paling cepat kedaluwarsa (FIFO)
+// lalu mencatat entry redeem dengan points negatif.
+func (repo *LoyaltyRepository) Redeem(ctx context.Context, customerID, points int, saleID *int) (*models.LoyaltyEntry, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, remaining
+			FROM loyalty_ledger
+			WHERE customer_id = $1
+				AND type = 'earn'
+				AND remaining > 0
+				AND (expires_at IS NULL OR expires_at > NOW())
+			ORDER BY expires_at NULLS LAST, id
+			FOR UPDATE`, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	type credit struct {
+		id        int
+		remaining int
+	}
+	credits := make([]credit, 0)
+	available := 0
+	for rows.Next() {
+		var c credit
+		if err := rows.Scan(&c.id, &c.remaining); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		credits = append(credits, c)
+		available += c.remaining
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if available < points {
+		return nil, ErrInsufficientPoints
+	}
+
+	left := points
+	for _, c := range credits {
+		if left == 0 {
+			break
+		}
+
+		used := min(c.remaining, left)
+		_, err := tx.ExecContext(ctx,
+			`UPDATE loyalty_ledger SET remaining = remaining - $1 WHERE id = $2`, used, c.id)
+		if err != nil {
+			return nil, err
+		}
+		left -= used
+	}
+
+	entry := &models.LoyaltyEntry{
+		CustomerID: customerID,
+		SaleID:     saleID,
+		Type:       models.LoyaltyEntryRedeem,
+		Points:     -points,
+	}
+	err = tx.QueryRowContext(ctx, `INSERT INTO loyalty_ledger
+				(customer_id, sale_id, type, points)
+			VALUES
+				($1, $2, $3, $4)
+			RETURNING id, created_at`,
+		entry.CustomerID, entry.SaleID, entry.Type, entry.Points,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Expire menutup sisa poin yang sudah lewat masa berlakunya dan mencatatnya
+// sebagai satu entry expire, supaya ledger tetap bisa direkonsiliasi.
+func (repo *LoyaltyRepository) Expire(ctx context.Context, customerID int) error {
+	query := `WITH expired AS (
+				SELECT id, remaining
+				FROM loyalty_ledger
+				WHERE customer_id = $1
+					AND type = 'earn'
+					AND remaining > 0
+					AND expires_at <= NOW()
+				FOR UPDATE
+			), cleared AS (
+				UPDATE loyalty_ledger l
+				SET remaining = 0
+				FROM expired e
+				WHERE l.id = e.id
+			)
+			INSERT INTO loyalty_ledger (customer_id, type, points)
+			SELECT $1, 'expire', -SUM(remaining)
+			FROM expired
+			HAVING SUM(remaining) > 0`
+
+	_, err := repo.db.ExecContext(ctx, query, customerID)
+	return err
+}
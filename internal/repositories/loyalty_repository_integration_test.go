@@ -0,0 +1,96 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyRepository_Integration_EarnAndRedeem(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewLoyaltyRepository(db)
+	ctx := context.Background()
+	customer := seedCustomer(t, db, "08123456789")
+
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(30 * 24 * time.Hour)
+	early := &models.LoyaltyEntry{CustomerID: customer.ID, Points: 30, ExpiresAt: &later}
+	require.NoError(t, repo.Earn(ctx, early))
+	assert.Equal(t, models.LoyaltyEntryEarn, early.Type)
+	assert.Equal(t, 30, early.Remaining)
+
+	expiring := &models.LoyaltyEntry{CustomerID: customer.ID, Points: 20, ExpiresAt: &soon}
+	require.NoError(t, repo.Earn(ctx, expiring))
+
+	balance, err := repo.Balance(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 50, balance)
+
+	// poin yang paling cepat kedaluwarsa dipakai lebih dulu
+	redeemed, err := repo.Redeem(ctx, customer.ID, 25, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.LoyaltyEntryRedeem, redeemed.Type)
+	assert.Equal(t, -25, redeemed.Points)
+
+	ledger, err := repo.GetLedger(ctx, customer.ID)
+	require.NoError(t, err)
+	require.Len(t, ledger, 3)
+	assert.Equal(t, redeemed.ID, ledger[0].ID)
+	remaining := map[int]int{}
+	for _, entry := range ledger {
+		remaining[entry.ID] = entry.Remaining
+	}
+	assert.Equal(t, 0, remaining[expiring.ID])
+	assert.Equal(t, 25, remaining[early.ID])
+
+	_, err = repo.Redeem(ctx, customer.ID, 26, nil)
+	assert.ErrorIs(t, err, ErrInsufficientPoints)
+
+	// poin baru langsung bisa ditukar lagi
+	earned := &models.LoyaltyEntry{CustomerID: customer.ID, Points: 25}
+	require.NoError(t, repo.Earn(ctx, earned))
+	assert.Equal(t, models.LoyaltyEntryEarn, earned.Type)
+
+	balance, err = repo.Balance(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 50, balance)
+}
+
+func TestLoyaltyRepository_Integration_Expire(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewLoyaltyRepository(db)
+	ctx := context.Background()
+	customer := seedCustomer(t, db, "08123456789")
+
+	past := time.Now().Add(-time.Hour)
+	expired := &models.LoyaltyEntry{CustomerID: customer.ID, Points: 40, ExpiresAt: &past}
+	require.NoError(t, repo.Earn(ctx, expired))
+	require.NoError(t, repo.Earn(ctx, &models.LoyaltyEntry{CustomerID: customer.ID, Points: 10}))
+
+	// poin kedaluwarsa tidak dihitung dan tidak bisa ditukar
+	balance, err := repo.Balance(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, balance)
+	_, err = repo.Redeem(ctx, customer.ID, 11, nil)
+	assert.ErrorIs(t, err, ErrInsufficientPoints)
+
+	require.NoError(t, repo.Expire(ctx, customer.ID))
+	// dipanggil ulang tidak mencatat entry expire kedua
+	require.NoError(t, repo.Expire(ctx, customer.ID))
+
+	ledger, err := repo.GetLedger(ctx, customer.ID)
+	require.NoError(t, err)
+	require.Len(t, ledger, 3)
+	assert.Equal(t, models.LoyaltyEntryExpire, ledger[0].Type)
+	assert.Equal(t, -40, ledger[0].Points)
+
+	balance, err = repo.Balance(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, balance)
+}
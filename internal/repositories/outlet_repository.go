@@ -0,0 +1,271 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+type OutletRepositoryInterface interface {
+	GetAll(ctx context.Context) ([]models.Outlet, error)
+	GetByID(ctx context.Context, id int) (*models.Outlet, error)
+	Create(ctx context.Context, outlet *models.Outlet) error
+	Update(ctx context.Context, id int, outlet *models.Outlet) error
+	GetStock(ctx context.Context, outletID int) ([]models.OutletStock, error)
+	GetConsolidatedStock(ctx context.Context) ([]models.ConsolidatedStock, error)
+	SetPrice(ctx context.Context, outletID, productID int, price *float64) error
+	GetMetrics(ctx context.Context) ([]models.OutletMetrics, error)
+}
+
+type OutletRepository struct {
+	db DBTX
+}
+
+func NewOutletRepository(db DBTX) OutletRepositoryInterface {
+	return &OutletRepository{
+		db: db,
+	}
+}
+
+func (repo *OutletRepository) GetAll(ctx context.Context) ([]models.Outlet, error) {
+	rows, err := repo.db.QueryContext(ctx,
+		`SELECT id, code, name, address, created_at, updated_at FROM outlets ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outlets := make([]models.Outlet, 0)
+	for rows.Next() {
+		var outlet models.Outlet
+		err := rows.Scan(
+			&outlet.ID,
+			&outlet.Code,
+			&outlet.Name,
+			&outlet.Address,
+			&outlet.CreatedAt,
+			&outlet.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		outlets = append(outlets, outlet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return outlets, nil
+}
+
+func (repo *OutletRepository) GetByID(ctx context.Context, id int) (*models.Outlet, error) {
+	var outlet models.Outlet
+	err := repo.db.QueryRowContext(ctx,
+		`SELECT id, code, name, address, created_at, updated_at FROM outlets WHERE id = $1`, id,
+	).Scan(
+		&outlet.ID,
+		&outlet.Code,
+		&outlet.Name,
+		&outlet.Address,
+		&outlet.CreatedAt,
+		&outlet.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrOutletNotFound
+		}
+		return nil, err
+	}
+
+	return &outlet, nil
+}
+
+func (repo *OutletRepository) Create(ctx context.Context, outlet *models.Outlet) error {
+	query := `INSERT INTO outlets (code, name, address)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at, updated_at`
+
+	err := repo.db.QueryRowContext(ctx, query, outlet.Code, outlet.Name, outlet.Address).Scan(
+		&outlet.ID,
+		&outlet.CreatedAt,
+		&outlet.UpdatedAt,
+	)
+	if err != nil {
+		return translateDBError(err, outletConstraints)
+	}
+
+	return nil
+}
+
+func (repo *OutletRepository) Update(ctx context.Context, id int, outlet *models.Outlet) error {
+	query := `UPDATE outlets
+			SET code = $1, name = $2, address = $3, updated_at = NOW()
+			WHERE id = $4`
+
+	result, err := repo.db.ExecContext(ctx, query, outlet.Code, outlet.Name, outlet.Address, id)
+	if err != nil {
+		return translateDBError(err, outletConstraints)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrOutletNotFound
+	}
+
+	return nil
+}
+
+// outletStockSelect membaca stok, reservasi open tab dan harga yang berlaku per outlet per produk
+const outletStockSelect = `SELECT
+				os.outlet_id, p.id, p.name, os.stock,
+				COALESCE(r.reserved, 0), COALESCE(os.price, p.price), os.price
+			FROM outlet_stock os
+			JOIN products p ON p.id = os.product_id
+			LEFT JOIN (
+				SELECT o.outlet_id, i.product_id, SUM(i.quantity) AS reserved
+				FROM draft_order_items i
+				JOIN draft_orders o ON o.id = i.draft_order_id
+				WHERE o.status = 'open'
+				GROUP BY o.outlet_id, i.product_id
+			) r ON r.outlet_id = os.outlet_id AND r.product_id = os.product_id`
+
+// GetStock adalah tampilan stok per outlet
+func (repo *OutletRepository) GetStock(ctx context.Context, outletID int) ([]models.OutletStock, error) {
+	return scanOutletStock(repo.db.QueryContext(ctx, outletStockSelect+`
+			WHERE os.outlet_id = $1
+			ORDER BY p.name`, outletID))
+}
+
+// GetConsolidatedStock menggabungkan stok semua outlet per produk, ditambah quantity
+// yang masih in_transit karena barang tersebut sudah keluar dari outlet asal.
+func (repo *OutletRepository) GetConsolidatedStock(ctx context.Context) ([]models.ConsolidatedStock, error) {
+	levels, err := scanOutletStock(repo.db.QueryContext(ctx, outletStockSelect+`
+			ORDER BY p.name, os.outlet_id`))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.db.QueryContext(ctx, `SELECT i.product_id, SUM(i.quantity)
+			FROM stock_transfer_items i
+			JOIN stock_transfers t ON t.id = i.transfer_id
+			WHERE t.status = 'in_transit'
+			GROUP BY i.product_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inTransit := make(map[int]int)
+	for rows.Next() {
+		var productID, quantity int
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			return nil, err
+		}
+		inTransit[productID] = quantity
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stocks := make([]models.ConsolidatedStock, 0)
+	index := make(map[int]int)
+	for _, level := range levels {
+		i, ok := index[level.ProductID]
+		if !ok {
+			i = len(stocks)
+			index[level.ProductID] = i
+			stocks = append(stocks, models.ConsolidatedStock{
+				ProductID:   level.ProductID,
+				ProductName: level.ProductName,
+				InTransit:   inTransit[level.ProductID],
+				Outlets:     make([]models.OutletStock, 0),
+			})
+		}
+
+		stocks[i].Stock += level.Stock
+		stocks[i].Reserved += level.Reserved
+		stocks[i].Outlets = append(stocks[i].Outlets, level)
+	}
+
+	return stocks, nil
+}
+
+// SetPrice mengatur harga khusus produk di sebuah outlet, price nil kembali ke products.price
+func (repo *OutletRepository) SetPrice(ctx context.Context, outletID, productID int, price *float64) error {
+	_, err := repo.db.ExecContext(ctx, `INSERT INTO outlet_stock (outlet_id, product_id, price)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (outlet_id, product_id)
+			DO UPDATE SET price = EXCLUDED.price, updated_at = NOW()`,
+		outletID, productID, price)
+	return translateDBError(err, outletPriceConstraints)
+}
+
+// GetMetrics menghitung produk yang stoknya habis dan open tab per outlet.
+// produk yang belum punya baris outlet_stock di sebuah outlet dihitung sebagai habis.
+func (repo *OutletRepository) GetMetrics(ctx context.Context) ([]models.OutletMetrics, error) {
+	rows, err := repo.db.QueryContext(ctx, `SELECT o.id, o.code,
+				(SELECT COUNT(*) FROM products p
+					LEFT JOIN outlet_stock os ON os.product_id = p.id AND os.outlet_id = o.id
+					WHERE COALESCE(os.stock, 0) <= 0),
+				(SELECT COUNT(*) FROM draft_orders d
+					WHERE d.outlet_id = o.id AND d.status = 'open')
+			FROM outlets o
+			ORDER BY o.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	metrics := make([]models.OutletMetrics, 0)
+	for rows.Next() {
+		var m models.OutletMetrics
+		if err := rows.Scan(&m.OutletID, &m.OutletCode, &m.ProductsOutOfStock, &m.OpenDraftOrders); err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func scanOutletStock(rows *sql.Rows, err error) ([]models.OutletStock, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := make([]models.OutletStock, 0)
+	for rows.Next() {
+		var level models.OutletStock
+		err := rows.Scan(
+			&level.OutletID,
+			&level.ProductID,
+			&level.ProductName,
+			&level.Stock,
+			&level.Reserved,
+			&level.Price,
+			&level.PriceOverride,
+		)
+		if err != nil {
+			return nil, err
+		}
+		level.Available = level.Stock - level.Reserved
+		levels = append(levels, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,127 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutletRepository_Integration_CRUD(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewOutletRepository(db)
+	ctx := context.Background()
+
+	// outlet 1 dibuat oleh migrasi
+	outlets, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, outlets, 1)
+	assert.Equal(t, "MAIN", outlets[0].Code)
+
+	branch := &models.Outlet{Code: "CBG", Name: "Cabang", Address: strPtr("Jl. Melati 1")}
+	require.NoError(t, repo.Create(ctx, branch))
+	assert.Equal(t, 2, branch.ID)
+
+	err = repo.Create(ctx, &models.Outlet{Code: "CBG", Name: "Kembar"})
+	assertAppError(t, err, apperrors.ErrConflict, "OUTLET_CODE_TAKEN")
+
+	require.NoError(t, repo.Update(ctx, branch.ID, &models.Outlet{Code: "CBG2", Name: "Cabang Dua"}))
+	found, err := repo.GetByID(ctx, branch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "CBG2", found.Code)
+	assert.Nil(t, found.Address)
+	assert.NotNil(t, found.UpdatedAt)
+
+	err = repo.Update(ctx, branch.ID, &models.Outlet{Code: "MAIN", Name: "Cabang"})
+	assertAppError(t, err, apperrors.ErrConflict, "OUTLET_CODE_TAKEN")
+
+	err = repo.Update(ctx, 99, &models.Outlet{Code: "X", Name: "X"})
+	assert.ErrorIs(t, err, ErrOutletNotFound)
+
+	_, err = repo.GetByID(ctx, 99)
+	assert.ErrorIs(t, err, ErrOutletNotFound)
+}
+
+func TestOutletRepository_Integration_StockAndPrices(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewOutletRepository(db)
+	ctx := context.Background()
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+	teh := seedProduct(t, outletCtx(1), db, category.ID, "Teh", 5000, 4)
+	seedProduct(t, outletCtx(branch.ID), db, category.ID, "Air", 3000, 0)
+	seedDraftOrder(t, outletCtx(1), db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 3})
+
+	// harga khusus outlet, NULL kembali ke harga produk
+	price := 17000.0
+	require.NoError(t, repo.SetPrice(ctx, 1, kopi.ID, &price))
+	require.NoError(t, repo.SetPrice(ctx, branch.ID, teh.ID, &price))
+	require.NoError(t, repo.SetPrice(ctx, branch.ID, teh.ID, nil))
+
+	stock, err := repo.GetStock(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, stock, 2)
+	assert.Equal(t, models.OutletStock{
+		OutletID: 1, ProductID: kopi.ID, ProductName: "Kopi",
+		Stock: 10, Reserved: 3, Available: 7, Price: 17000, PriceOverride: &price,
+	}, stock[0])
+	assert.Equal(t, "Teh", stock[1].ProductName)
+	assert.Nil(t, stock[1].PriceOverride)
+
+	// SetPrice membuat baris stok 0 di outlet yang belum pernah menyimpan produk itu
+	stock, err = repo.GetStock(ctx, branch.ID)
+	require.NoError(t, err)
+	require.Len(t, stock, 2)
+	assert.Equal(t, "Air", stock[0].ProductName)
+	assert.Equal(t, "Teh", stock[1].ProductName)
+	assert.Equal(t, 0, stock[1].Stock)
+	assert.Equal(t, 5000.0, stock[1].Price)
+
+	err = repo.SetPrice(ctx, 99, kopi.ID, &price)
+	assertAppError(t, err, apperrors.ErrNotFound, "OUTLET_NOT_FOUND")
+	err = repo.SetPrice(ctx, 1, 99, &price)
+	assertAppError(t, err, apperrors.ErrNotFound, "PRODUCT_NOT_FOUND")
+
+	transfer := &models.StockTransfer{
+		FromOutletID: 1, ToOutletID: branch.ID,
+		Items: []models.StockTransferItem{{ProductID: kopi.ID, Quantity: 2}},
+	}
+	require.NoError(t, NewStockTransferRepository(db).Create(outletCtx(1), transfer))
+
+	consolidated, err := repo.GetConsolidatedStock(ctx)
+	require.NoError(t, err)
+	require.Len(t, consolidated, 3)
+	assert.Equal(t, "Air", consolidated[0].ProductName)
+	assert.Equal(t, "Kopi", consolidated[1].ProductName)
+	assert.Equal(t, 8, consolidated[1].Stock)
+	assert.Equal(t, 3, consolidated[1].Reserved)
+	assert.Equal(t, 2, consolidated[1].InTransit)
+	require.Len(t, consolidated[1].Outlets, 1)
+	assert.Equal(t, "Teh", consolidated[2].ProductName)
+	assert.Len(t, consolidated[2].Outlets, 2)
+}
+
+func TestOutletRepository_Integration_GetMetrics(t *testing.T) {
+	db := integrationDB(t)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+	seedProduct(t, outletCtx(1), db, category.ID, "Teh", 5000, 0)
+	seedDraftOrder(t, outletCtx(1), db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 1})
+	seedDraftOrder(t, outletCtx(1), db)
+
+	metrics, err := NewOutletRepository(db).GetMetrics(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.OutletMetrics{
+		{OutletID: 1, OutletCode: "MAIN", ProductsOutOfStock: 1, OpenDraftOrders: 2},
+		// outlet yang belum pernah menyimpan produk dianggap kehabisan semua produk
+		{OutletID: branch.ID, OutletCode: "CBG", ProductsOutOfStock: 2, OpenDraftOrders: 0},
+	}, metrics)
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutletRepository_GetMetrics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewOutletRepository(db)
+
+	mock.ExpectQuery(`SELECT o.id, o.code`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "out_of_stock", "open_draft_orders"}).
+			AddRow(1, "PST", 3, 2).
+			AddRow(2, "CBG", 0, 0))
+
+	metrics, err := repo.GetMetrics(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.OutletMetrics{
+		{OutletID: 1, OutletCode: "PST", ProductsOutOfStock: 3, OpenDraftOrders: 2},
+		{OutletID: 2, OutletCode: "CBG", ProductsOutOfStock: 0, OpenDraftOrders: 0},
+	}, metrics)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// updateBuilder menyusun UPDATE parsial yang hanya menyentuh kolom yang dikirim di PATCH
+type updateBuilder struct {
+	sets []string
+	args []interface{}
+}
+
+func (b *updateBuilder) set(column string, value interface{}) {
+	b.args = append(b.args, value)
+	b.sets = append(b.sets, column+" = $"+strconv.Itoa(len(b.args)))
+}
+
+// query menghasilkan "UPDATE table SET ..., updated_at = NOW(), version = version + 1
+// WHERE id = $n AND version = $m". updated_at dan version selalu diperbarui sehingga
+// patch kosong pun tetap memastikan datanya ada dan versinya cocok.
+func (b *updateBuilder) query(table string, id, version int) (string, []interface{}) {
+	args := append(b.args, id, version)
+	sets := append(b.sets, "updated_at = NOW()", "version = version + 1")
+	return "UPDATE " + table + " SET " + strings.Join(sets, ", ") +
+		" WHERE id = $" + strconv.Itoa(len(args)-1) + " AND version = $" + strconv.Itoa(len(args)), args
+}
+
+// notFoundOrStale dipanggil saat UPDATE/DELETE bersyarat versi tidak mengenai baris apa pun,
+// untuk membedakan data yang memang tidak ada dengan data yang sudah diubah request lain
+func notFoundOrStale(ctx context.Context, q DBTX, table string, id int, notFound error) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return notFound
+	}
+	return ErrVersionConflict
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"strconv"
+	"strings"
+)
+
+type ProductImageRepositoryInterface interface {
+	GetByProductIDs(ctx context.Context, productIDs []int) (map[int][]models.ProductImage, error)
+	GetByID(ctx context.Context, productID, imageID int) (*models.ProductImage, error)
+	Create(ctx context.Context, image *models.ProductImage) error
+	Delete(ctx context.Context, productID, imageID int) error
+}
+
+type ProductImageRepository struct {
+	db DBTX
+}
+
+func NewProductImageRepository(db DBTX) ProductImageRepositoryInterface {
+	return &ProductImageRepository{
+		db: db,
+	}
+}
+
+const productImageSelect = `SELECT id, product_id, storage_key, content_type, width, height, size_bytes, thumbnails, created_at
+			FROM product_images`
+
+// GetByProductIDs mengambil gambar beberapa produk sekaligus, dikelompokkan per product_id
+func (repo *ProductImageRepository) GetByProductIDs(ctx context.Context, productIDs []int) (map[int][]models.ProductImage, error) {
+	images := make(map[int][]models.ProductImage)
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+
+	// id dikirim sebagai array literal '{1,2,3}' supaya tidak bergantung pada dukungan slice di driver
+	ids := make([]string, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+
+	rows, err := repo.db.QueryContext(ctx, productImageSelect+`
+			WHERE product_id = ANY($1::int[])
+			ORDER BY product_id, id`, "{"+strings.Join(ids, ",")+"}")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image, err := scanProductImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images[image.ProductID] = append(images[image.ProductID], *image)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (repo *ProductImageRepository) GetByID(ctx context.Context, productID, imageID int) (*models.ProductImage, error) {
+	image, err := scanProductImage(repo.db.QueryRowContext(ctx, productImageSelect+`
+			WHERE id = $1 AND product_id = $2`, imageID, productID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProductImageNotFound
+		}
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (repo *ProductImageRepository) Create(ctx context.Context, image *models.ProductImage) error {
+	thumbnails, err := json.Marshal(image.ThumbnailKeys)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO product_images
+				(product_id, storage_key, content_type, width, height, size_bytes, thumbnails)
+			VALUES
+				($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at`
+
+	return repo.db.QueryRowContext(ctx, query,
+		image.ProductID,
+		image.Key,
+		image.ContentType,
+		image.Width,
+		image.Height,
+		image.SizeBytes,
+		string(thumbnails),
+	).Scan(
+		&image.ID,
+		&image.CreatedAt,
+	)
+}
+
+func (repo *ProductImageRepository) Delete(ctx context.Context, productID, imageID int) error {
+	result, err := repo.db.ExecContext(ctx,
+		`DELETE FROM product_images WHERE id = $1 AND product_id = $2`, imageID, productID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrProductImageNotFound
+	}
+
+	return nil
+}
+
+func scanProductImage(row rowScanner) (*models.ProductImage, error) {
+	var image models.ProductImage
+	var thumbnails []byte
+	err := row.Scan(
+		&image.ID,
+		&image.ProductID,
+		&image.Key,
+		&image.ContentType,
+		&image.Width,
+		&image.Height,
+		&image.SizeBytes,
+		&thumbnails,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	image.ThumbnailKeys = make(map[string]string)
+	if len(thumbnails) > 0 {
+		if err := json.Unmarshal(thumbnails, &image.ThumbnailKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	return &image, nil
+}
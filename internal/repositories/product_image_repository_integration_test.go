@@ -0,0 +1,70 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductImageRepository_Integration(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewProductImageRepository(db)
+	ctx := context.Background()
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 5)
+	teh := seedProduct(t, outletCtx(1), db, category.ID, "Teh", 5000, 5)
+
+	image := &models.ProductImage{
+		ProductID:     kopi.ID,
+		Key:           "products/1/ab12.jpg",
+		ContentType:   "image/jpeg",
+		Width:         800,
+		Height:        600,
+		SizeBytes:     52000,
+		ThumbnailKeys: map[string]string{"128": "products/1/ab12/128.jpg"},
+	}
+	require.NoError(t, repo.Create(ctx, image))
+	assert.NotZero(t, image.ID)
+	assert.False(t, image.CreatedAt.IsZero())
+
+	second := &models.ProductImage{ProductID: kopi.ID, Key: "products/1/cd34.png", ContentType: "image/png", Width: 1, Height: 1, SizeBytes: 1}
+	require.NoError(t, repo.Create(ctx, second))
+
+	found, err := repo.GetByID(ctx, kopi.ID, image.ID)
+	require.NoError(t, err)
+	assert.Equal(t, image.Key, found.Key)
+	assert.Equal(t, image.ThumbnailKeys, found.ThumbnailKeys)
+	assert.Equal(t, 800, found.Width)
+
+	// gambar hanya ditemukan lewat produk pemiliknya
+	_, err = repo.GetByID(ctx, teh.ID, image.ID)
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+
+	images, err := repo.GetByProductIDs(ctx, []int{kopi.ID, teh.ID})
+	require.NoError(t, err)
+	require.Len(t, images[kopi.ID], 2)
+	assert.Equal(t, image.ID, images[kopi.ID][0].ID)
+	assert.Equal(t, map[string]string{}, images[kopi.ID][1].ThumbnailKeys)
+	assert.Empty(t, images[teh.ID])
+
+	images, err = repo.GetByProductIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, images)
+
+	err = repo.Delete(ctx, teh.ID, image.ID)
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+	require.NoError(t, repo.Delete(ctx, kopi.ID, image.ID))
+	_, err = repo.GetByID(ctx, kopi.ID, image.ID)
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+
+	// gambar ikut terhapus bersama produknya
+	require.NoError(t, NewProductRepository(db).Delete(ctx, kopi.ID, kopi.Version))
+	images, err = repo.GetByProductIDs(ctx, []int{kopi.ID})
+	require.NoError(t, err)
+	assert.Empty(t, images)
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var productImageColumns = []string{
+	"id", "product_id", "storage_key", "content_type", "width", "height", "size_bytes", "thumbnails", "created_at",
+}
+
+func TestProductImageRepository_GetByProductIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductImageRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(productImageColumns).
+		AddRow(1, 1, "products/1/aa/original.png", "image/png", 800, 600, 1024, []byte(`{"128":"products/1/aa/128.png"}`), now).
+		AddRow(2, 2, "products/2/bb/original.jpg", "image/jpeg", 640, 480, 2048, []byte(`{}`), now)
+
+	mock.ExpectQuery(`FROM product_images WHERE product_id = ANY\(\$1::int\[\]\)`).
+		WithArgs("{1,2}").
+		WillReturnRows(rows)
+
+	images, err := repo.GetByProductIDs(context.Background(), []int{1, 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, images[1], 1)
+	assert.Equal(t, "products/1/aa/128.png", images[1][0].ThumbnailKeys["128"])
+	assert.Len(t, images[2], 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductImageRepository_GetByProductIDs_Empty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductImageRepository(db)
+
+	images, err := repo.GetByProductIDs(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductImageRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductImageRepository(db)
+
+	now := time.Now()
+	image := &models.ProductImage{
+		ProductID:     1,
+		Key:           "products/1/aa/original.png",
+		ContentType:   "image/png",
+		Width:         800,
+		Height:        600,
+		SizeBytes:     1024,
+		ThumbnailKeys: map[string]string{"128": "products/1/aa/128.png"},
+	}
+
+	mock.ExpectQuery(`INSERT INTO product_images`).
+		WithArgs(1, image.Key, image.ContentType, 800, 600, int64(1024), `{"128":"products/1/aa/128.png"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+
+	err = repo.Create(context.Background(), image)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, image.ID)
+}
+
+func TestProductImageRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductImageRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM product_images WHERE id = $1 AND product_id = $2`)).
+		WithArgs(9, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), 1, 9)
+
+	assert.EqualError(t, err, "product image not found")
+}
+
+func TestProductImageRepository_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductImageRepository(db)
+
+	mock.ExpectQuery(`FROM product_images WHERE id = \$1 AND product_id = \$2`).
+		WithArgs(9, 1).
+		WillReturnError(sql.ErrNoRows)
+
+	image, err := repo.GetByID(context.Background(), 1, 9)
+
+	assert.EqualError(t, err, "product image not found")
+	assert.Nil(t, image)
+}
@@ -3,7 +3,8 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
 	"fajar7xx/go-kasir-umam-ds/models"
 )
 
@@ -14,40 +15,33 @@ type ProductRepositoryInterface interface {
 	GetByID(ctx context.Context, id int) (*models.ProductResponse, error)
 	Create(ctx context.Context, product *models.Product) (*models.ProductResponse, error)
 	Update(ctx context.Context, id int, product *models.Product) error
-	Delete(ctx context.Context, id int) error
+	Patch(ctx context.Context, id int, patch models.ProductPatch) error
+	Delete(ctx context.Context, id int, version int) error
 }
 
 // 2. ini adalah konkret (si pelakunya)
 type ProductRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 // constructor mengembalikan pointer ke struct,
 // tapi struc ini secara implisit sudahg memenuhi interface diatas
-func NewProductRepository(db *sql.DB) ProductRepositoryInterface {
+func NewProductRepository(db DBTX) ProductRepositoryInterface {
 	return &ProductRepository{
 		db: db,
 	}
 }
 
 func (repo *ProductRepository) GetAll(ctx context.Context) ([]models.ProductResponse, error) {
-	query := `select
-				  p.id,
-				  p.name,
-				  p.description,
-				  p.price,
-				  p.stock,
-				  p.category_id,
-				  p.created_at,
-				  p.updated_at,
-				  c.id as category_id,
-				  c.name as category_name,
-				  c.description as category_description
-				from
-				  products p
-				  join categories c on p.category_id = c.id;`
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := productSelect + `
+				order by p.id`
 
-	rows, err := repo.db.QueryContext(ctx, query)
+	rows, err := repo.db.QueryContext(ctx, query, outletID)
 	if err != nil {
 		return nil, err
 	}
@@ -66,10 +60,13 @@ func (repo *ProductRepository) GetAll(ctx context.Context) ([]models.ProductResp
 			&p.Name,
 			&p.Description,
 			&p.Price,
+			&p.BasePrice,
 			&p.Stock,
+			&p.OutletID,
 			&p.CategoryID,
 			&p.CreatedAt,
 			&p.UpdatedAt,
+			&p.Version,
 			&categoryID,
 			&categoryName,
 			&categoryDescription)
@@ -95,22 +92,13 @@ func (repo *ProductRepository) GetAll(ctx context.Context) ([]models.ProductResp
 }
 
 func (repo *ProductRepository) GetByID(ctx context.Context, id int) (*models.ProductResponse, error) {
-	query := `select
-				  p.id,
-				  p.name,
-				  p.description,
-				  p.price,
-				  p.stock,
-				  p.category_id,
-				  p.created_at,
-				  p.updated_at,
-				  c.id as category_id,
-				  c.name as category_name,
-				  c.description as category_description
-				from
-				  products p
-				  join categories c on p.category_id = c.id
-				where p.id = $1`
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := productSelect + `
+				where p.id = $2`
 
 	// row := repo.db.QueryRow(query, id)
 
@@ -131,15 +119,18 @@ func (repo *ProductRepository) GetByID(ctx context.Context, id int) (*models.Pro
 	// )
 
 	// QueryRowContext untuk single row + context
-	err := repo.db.QueryRowContext(ctx, query, id).Scan(
+	err = repo.db.QueryRowContext(ctx, query, outletID, id).Scan(
 		&p.ID,
 		&p.Name,
 		&p.Description,
 		&p.Price,
+		&p.BasePrice,
 		&p.Stock,
+		&p.OutletID,
 		&p.CategoryID,
 		&p.CreatedAt,
 		&p.UpdatedAt,
+		&p.Version,
 		&categoryID,
 		&categoryName,
 		&categoryDescription,
@@ -148,7 +139,7 @@ func (repo *ProductRepository) GetByID(ctx context.Context, id int) (*models.Pro
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// kita bisa return error khusus atau error bawaan sql
-			return nil, errors.New("product not found")
+			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
@@ -162,28 +153,29 @@ func (repo *ProductRepository) GetByID(ctx context.Context, id int) (*models.Pro
 	return &p, nil
 }
 
+// Create menyimpan produk (dipakai bersama semua outlet) dan stok awalnya di outlet yang sedang dilayani
 func (repo *ProductRepository) Create(ctx context.Context, product *models.Product) (*models.ProductResponse, error) {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO products
-				(name, price, stock, description, category_id)
+				(name, price, description, category_id)
 			VALUES
-				($1, $2, $3, $4, $5)
+				($1, $2, $3, $4)
 			RETURNING id, created_at, updated_at`
 
-	// err := repo.db.QueryRow(query,
-	// 	product.Name,
-	// 	product.Price,
-	// 	product.Stock,
-	// 	product.Description,
-	// 	product.CategoryID,
-	// ).Scan(&product.ID,
-	// 	&product.CreatedAt,
-	// 	&product.UpdatedAt)
-
 	// QueryRowContext untuk INSERT ... RETURNING
-	err := repo.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		product.Name,
 		product.Price,
-		product.Stock,
 		product.Description,
 		product.CategoryID,
 	).Scan(
@@ -191,47 +183,106 @@ func (repo *ProductRepository) Create(ctx context.Context, product *models.Produ
 		&product.CreatedAt,
 		&product.UpdatedAt,
 	)
-
 	if err != nil {
+		return nil, translateDBError(err, productConstraints)
+	}
+
+	if err := setOutletStock(ctx, tx, outletID, product.ID, product.Stock); err != nil {
+		return nil, apperrors.FromDB(err)
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return repo.GetByID(ctx, product.ID)
 }
 
+// Update mengubah data produk bersama, sedangkan stock hanya berlaku untuk outlet yang sedang dilayani
 func (repo *ProductRepository) Update(ctx context.Context, id int, product *models.Product) error {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE products
 				SET
 				name = $1,
 				price=$2,
-				stock=$3,
-				description=$4,
-				category_id=$5,
-				updated_at = NOW()
-				WHERE id = $6`
-
-	// result, err := repo.db.Exec(query,
-	// 	product.Name,
-	// 	product.Price,
-	// 	product.Stock,
-	// 	product.Description,
-	// 	product.CategoryID,
-	// 	id,
-	// )
+				description=$3,
+				category_id=$4,
+				updated_at = NOW(),
+				version = version + 1
+				WHERE id = $5 AND version = $6`
 
 	// ExecContext untuk UPDATE
-	result, err := repo.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		product.Name,
 		product.Price,
-		product.Stock,
 		product.Description,
 		product.CategoryID,
 		id,
+		product.Version,
 	)
 
+	if err != nil {
+		return translateDBError(err, productConstraints)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return notFoundOrStale(ctx, tx, "products", id, ErrProductNotFound)
+	}
+
+	if err := setOutletStock(ctx, tx, outletID, id, product.Stock); err != nil {
+		return apperrors.FromDB(err)
+	}
+
+	return tx.Commit()
+}
+
+// Patch hanya mengubah kolom yang dikirim. stock tetap berlaku untuk outlet yang sedang dilayani.
+func (repo *ProductRepository) Patch(ctx context.Context, id int, patch models.ProductPatch) error {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var update updateBuilder
+	if patch.Name.Set {
+		update.set("name", patch.Name.SQLValue())
+	}
+	if patch.Description.Set {
+		update.set("description", patch.Description.SQLValue())
+	}
+	if patch.Price.Set {
+		update.set("price", patch.Price.SQLValue())
+	}
+	if patch.CategoryID.Set {
+		update.set("category_id", patch.CategoryID.SQLValue())
+	}
+
+	tx, err := beginTx(ctx, repo.db)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	query, args := update.query("products", id, patch.Version)
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateDBError(err, productConstraints)
+	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
@@ -239,20 +290,26 @@ func (repo *ProductRepository) Update(ctx context.Context, id int, product *mode
 	}
 
 	if rows == 0 {
-		return errors.New("product not found")
+		return notFoundOrStale(ctx, tx, "products", id, ErrProductNotFound)
 	}
 
-	return nil
+	if patch.Stock.Set {
+		if err := setOutletStock(ctx, tx, outletID, id, patch.Stock.Value); err != nil {
+			return apperrors.FromDB(err)
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (repo *ProductRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE from products where id = $1`
+func (repo *ProductRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `DELETE from products where id = $1 and version = $2`
 
 	// result, err := repo.db.Exec(query, id)
 	// // ExecContext untuk DELETE
-	result, err := repo.db.ExecContext(ctx, query, id)
+	result, err := repo.db.ExecContext(ctx, query, id, version)
 	if err != nil {
-		return err
+		return translateDBError(err, productConstraints)
 	}
 
 	rows, err := result.RowsAffected()
@@ -261,8 +318,39 @@ func (repo *ProductRepository) Delete(ctx context.Context, id int) error {
 	}
 
 	if rows == 0 {
-		return errors.New("product not found")
+		return notFoundOrStale(ctx, repo.db, "products", id, ErrProductNotFound)
 	}
 
 	return nil
 }
+
+// productSelect membaca produk dengan harga dan stok milik outlet $1.
+// produk yang belum pernah distok di outlet tersebut tetap tampil dengan stok 0.
+const productSelect = `select
+				  p.id,
+				  p.name,
+				  p.description,
+				  coalesce(os.price, p.price) as price,
+				  p.price as base_price,
+				  coalesce(os.stock, 0) as stock,
+				  $1::int as outlet_id,
+				  p.category_id,
+				  p.created_at,
+				  p.updated_at,
+				  p.version,
+				  c.id as category_id,
+				  c.name as category_name,
+				  c.description as category_description
+				from
+				  products p
+				  join categories c on p.category_id = c.id
+				  left join outlet_stock os on os.product_id = p.id and os.outlet_id = $1`
+
+func setOutletStock(ctx context.Context, tx DBTX, outletID, productID, stock int) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO outlet_stock (outlet_id, product_id, stock)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (outlet_id, product_id)
+			DO UPDATE SET stock = EXCLUDED.stock, updated_at = NOW()`,
+		outletID, productID, stock)
+	return err
+}
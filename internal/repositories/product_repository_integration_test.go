@@ -0,0 +1,165 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductRepository_Integration_CreateAndGet(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewProductRepository(db)
+	category := seedCategory(t, db, "Minuman")
+	second := seedOutlet(t, db, "CBG")
+
+	product, err := repo.Create(outletCtx(1), &models.Product{
+		Name:        "Kopi Susu",
+		Description: strPtr("Gula aren"),
+		Price:       18000,
+		Stock:       12,
+		CategoryID:  category.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Kopi Susu", product.Name)
+	assert.Equal(t, 18000.0, product.Price)
+	assert.Equal(t, 18000.0, product.BasePrice)
+	assert.Equal(t, 12, product.Stock)
+	assert.Equal(t, 1, product.OutletID)
+	assert.Equal(t, 1, product.Version)
+	assert.Equal(t, models.CategorySummary{ID: category.ID, Name: "Minuman"}, product.Category)
+
+	// produk dipakai bersama, tapi outlet lain belum punya stok
+	other, err := repo.GetByID(outletCtx(second.ID), product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, other.Stock)
+	assert.Equal(t, second.ID, other.OutletID)
+
+	products, err := repo.GetAll(outletCtx(1))
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, product.ID, products[0].ID)
+	assert.Equal(t, "Gula aren", *products[0].Description)
+
+	_, err = repo.GetByID(outletCtx(1), 99)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestProductRepository_Integration_RequiresOutlet(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetAll(ctx)
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+
+	_, err = repo.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+
+	_, err = repo.Create(ctx, &models.Product{Name: "Es Teh", Price: 5000, CategoryID: 1})
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+}
+
+func TestProductRepository_Integration_CreateUnknownCategory(t *testing.T) {
+	db := integrationDB(t)
+
+	_, err := NewProductRepository(db).Create(outletCtx(1), &models.Product{
+		Name: "Es Teh", Price: 5000, Stock: 1, CategoryID: 99,
+	})
+
+	assertAppError(t, err, apperrors.ErrUnprocessable, "CATEGORY_NOT_FOUND")
+}
+
+func TestProductRepository_Integration_Update(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewProductRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	other := seedCategory(t, db, "Kopi")
+	product := seedProduct(t, ctx, db, category.ID, "Kopi", 15000, 5)
+
+	err := repo.Update(ctx, product.ID, &models.Product{
+		Name: "Kopi Hitam", Price: 16000, Stock: 8, CategoryID: other.ID, Version: 1,
+	})
+	require.NoError(t, err)
+
+	updated, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Kopi Hitam", updated.Name)
+	assert.Equal(t, 16000.0, updated.Price)
+	assert.Equal(t, 8, updated.Stock)
+	assert.Equal(t, "Kopi", updated.Category.Name)
+	assert.Equal(t, 2, updated.Version)
+	assert.NotNil(t, updated.UpdatedAt)
+
+	// versi lama ditolak dan stok tidak ikut berubah
+	err = repo.Update(ctx, product.ID, &models.Product{
+		Name: "Basi", Price: 1, Stock: 1, CategoryID: category.ID, Version: 1,
+	})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, 8, outletStock(t, db, 1, product.ID))
+
+	err = repo.Update(ctx, 99, &models.Product{Name: "x", Price: 1, CategoryID: category.ID, Version: 1})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	err = repo.Update(ctx, product.ID, &models.Product{Name: "x", Price: 1, CategoryID: 99, Version: 2})
+	assertAppError(t, err, apperrors.ErrUnprocessable, "CATEGORY_NOT_FOUND")
+}
+
+func TestProductRepository_Integration_Patch(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewProductRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	product := seedProduct(t, ctx, db, category.ID, "Es Teh", 5000, 10)
+
+	err := repo.Patch(ctx, product.ID, models.ProductPatch{
+		Price:   models.PatchField[float64]{Set: true, Value: 6000},
+		Stock:   models.PatchField[int]{Set: true, Value: 4},
+		Version: 1,
+	})
+	require.NoError(t, err)
+
+	patched, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Es Teh", patched.Name)
+	assert.Equal(t, 6000.0, patched.Price)
+	assert.Equal(t, 4, patched.Stock)
+	assert.Equal(t, 2, patched.Version)
+
+	// patch kosong tetap memeriksa versi
+	err = repo.Patch(ctx, product.ID, models.ProductPatch{Version: 1})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	err = repo.Patch(ctx, 99, models.ProductPatch{Version: 1})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestProductRepository_Integration_Delete(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewProductRepository(db)
+	ctx := outletCtx(1)
+	category := seedCategory(t, db, "Minuman")
+	product := seedProduct(t, ctx, db, category.ID, "Es Teh", 5000, 10)
+	inUse := seedProduct(t, ctx, db, category.ID, "Es Jeruk", 7000, 10)
+	seedDraftOrder(t, ctx, db, models.DraftOrderItem{ProductID: inUse.ID, Quantity: 1})
+
+	err := repo.Delete(ctx, product.ID, 2)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	require.NoError(t, repo.Delete(ctx, product.ID, 1))
+	_, err = repo.GetByID(ctx, product.ID)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	err = repo.Delete(ctx, product.ID, 1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	err = repo.Delete(ctx, inUse.ID, 1)
+	assertAppError(t, err, apperrors.ErrConflict, "PRODUCT_IN_USE")
+}
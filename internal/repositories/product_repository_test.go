@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
 	"fajar7xx/go-kasir-umam-ds/models"
 	"regexp"
 	"testing"
@@ -11,6 +13,16 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var productColumns = []string{
+	"id", "name", "description", "price", "base_price", "stock", "outlet_id", "category_id", "created_at", "updated_at", "version",
+	"category_id", "category_name", "category_description",
+}
+
+// outletContext mensimulasikan request dari outlet 1
+func outletContext() context.Context {
+	return outlet.WithID(context.Background(), 1)
+}
+
 func TestProductRepository_GetAll(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -22,22 +34,41 @@ func TestProductRepository_GetAll(t *testing.T) {
 
 	now := time.Now()
 	desc := "Delicious Food"
-	rows := sqlmock.NewRows([]string{"id", "name", "description", "price", "stock", "category_id", "created_at", "updated_at"}).
-		AddRow(1, "Nasi Goreng", &desc, 15000.0, 10, 1, now, now).
-		AddRow(2, "Es Teh", nil, 3000.0, 20, 2, now, now)
+	rows := sqlmock.NewRows(productColumns).
+		AddRow(1, "Nasi Goreng", &desc, 15000.0, 15000.0, 10, 1, 1, now, now, 1, 1, "Food", nil).
+		AddRow(2, "Es Teh", nil, 3500.0, 3000.0, 20, 1, 2, now, now, 1, 2, "Beverage", nil)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, price, stock, category_id, created_at, updated_at FROM products`)
-	mock.ExpectQuery(query).WillReturnRows(rows)
+	query := `select .* from products p join categories c on p.category_id = c.id left join outlet_stock os .* order by p.id`
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
 
-	products, err := repo.GetAll()
+	products, err := repo.GetAll(outletContext())
 
 	assert.NoError(t, err)
 	assert.Len(t, products, 2)
 	assert.Equal(t, "Nasi Goreng", products[0].Name)
 	assert.NotNil(t, products[0].Description)
 	assert.Equal(t, "Delicious Food", *products[0].Description)
+	assert.Equal(t, "Food", products[0].Category.Name)
 	assert.Equal(t, "Es Teh", products[1].Name)
 	assert.Nil(t, products[1].Description)
+	assert.Equal(t, 3500.0, products[1].Price)
+	assert.Equal(t, 3000.0, products[1].BasePrice)
+}
+
+func TestProductRepository_GetAll_RequiresOutlet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+
+	products, err := repo.GetAll(context.Background())
+
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+	assert.Nil(t, products)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestProductRepository_GetByID(t *testing.T) {
@@ -51,13 +82,13 @@ func TestProductRepository_GetByID(t *testing.T) {
 
 	now := time.Now()
 	desc := "Delicious Food"
-	rows := sqlmock.NewRows([]string{"id", "name", "description", "price", "stock", "category_id", "created_at", "updated_at"}).
-		AddRow(1, "Nasi Goreng", &desc, 15000.0, 10, 1, now, now)
+	rows := sqlmock.NewRows(productColumns).
+		AddRow(1, "Nasi Goreng", &desc, 15000.0, 15000.0, 10, 1, 1, now, now, 1, 1, "Food", nil)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, price, stock, category_id, created_at, updated_at FROM products WHERE id = $1`)
-	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+	query := `select .* from products p join categories c on p.category_id = c.id left join outlet_stock os .* where p.id = \$2`
+	mock.ExpectQuery(query).WithArgs(1, 1).WillReturnRows(rows)
 
-	product, err := repo.GetByID(1)
+	product, err := repo.GetByID(outletContext(), 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, product)
@@ -74,10 +105,10 @@ func TestProductRepository_GetByID_NotFound(t *testing.T) {
 
 	repo := NewProductRepository(db)
 
-	query := regexp.QuoteMeta(`SELECT id, name, description, price, stock, category_id, created_at, updated_at FROM products WHERE id = $1`)
-	mock.ExpectQuery(query).WithArgs(1).WillReturnError(sql.ErrNoRows)
+	query := `select .* from products p join categories c on p.category_id = c.id left join outlet_stock os .* where p.id = \$2`
+	mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(sql.ErrNoRows)
 
-	product, err := repo.GetByID(1)
+	product, err := repo.GetByID(outletContext(), 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "product not found", err.Error())
@@ -103,16 +134,29 @@ func TestProductRepository_Create(t *testing.T) {
 		CategoryID:  1,
 	}
 
-	query := regexp.QuoteMeta(`INSERT INTO products (name, price, stock, description, category_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`)
+	query := regexp.QuoteMeta(`INSERT INTO products (name, price, description, category_id) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(query).
-		WithArgs(product.Name, product.Price, product.Stock, product.Description, product.CategoryID).
+		WithArgs(product.Name, product.Price, product.Description, product.CategoryID).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, now, now))
+	mock.ExpectExec(`INSERT INTO outlet_stock`).
+		WithArgs(1, 1, product.Stock).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	err = repo.Create(product)
+	// Create membaca ulang produk beserta kategorinya
+	mock.ExpectQuery(`select .* from products p join categories c on p.category_id = c.id left join outlet_stock os .* where p.id = \$2`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows(productColumns).
+			AddRow(1, "Nasi Goreng", &desc, 15000.0, 15000.0, 10, 1, 1, now, now, 1, 1, "Food", nil))
+
+	created, err := repo.Create(outletContext(), product)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, product.ID)
+	assert.Equal(t, 1, created.ID)
+	assert.Equal(t, "Food", created.Category.Name)
 	assert.False(t, product.CreatedAt.IsZero())
 }
 
@@ -132,14 +176,20 @@ func TestProductRepository_Update(t *testing.T) {
 		Price:       16000.0,
 		Stock:       15,
 		CategoryID:  1,
+		Version:     3,
 	}
 
-	query := regexp.QuoteMeta(`UPDATE products SET name = $1, price=$2, stock=$3, description=$4, category_id=$5 , updated_at = NOW() WHERE id = $6`)
+	query := regexp.QuoteMeta(`UPDATE products SET name = $1, price=$2, description=$3, category_id=$4, updated_at = NOW(), version = version + 1 WHERE id = $5 AND version = $6`)
+	mock.ExpectBegin()
 	mock.ExpectExec(query).
-		WithArgs(product.Name, product.Price, product.Stock, product.Description, product.CategoryID, 1).
+		WithArgs(product.Name, product.Price, product.Description, product.CategoryID, 1, 3).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO outlet_stock`).
+		WithArgs(1, 1, product.Stock).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	err = repo.Update(1, product)
+	err = repo.Update(outletContext(), 1, product)
 
 	assert.NoError(t, err)
 }
@@ -160,14 +210,20 @@ func TestProductRepository_Update_NotFound(t *testing.T) {
 		Price:       16000.0,
 		Stock:       15,
 		CategoryID:  1,
+		Version:     3,
 	}
 
-	query := regexp.QuoteMeta(`UPDATE products SET name = $1, price=$2, stock=$3, description=$4, category_id=$5 , updated_at = NOW() WHERE id = $6`)
+	query := regexp.QuoteMeta(`UPDATE products SET name = $1, price=$2, description=$3, category_id=$4, updated_at = NOW(), version = version + 1 WHERE id = $5 AND version = $6`)
+	mock.ExpectBegin()
 	mock.ExpectExec(query).
-		WithArgs(product.Name, product.Price, product.Stock, product.Description, product.CategoryID, 1).
+		WithArgs(product.Name, product.Price, product.Description, product.CategoryID, 1, 3).
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
 
-	err = repo.Update(1, product)
+	err = repo.Update(outletContext(), 1, product)
 
 	assert.Error(t, err)
 	assert.Equal(t, "product not found", err.Error())
@@ -182,12 +238,12 @@ func TestProductRepository_Delete(t *testing.T) {
 
 	repo := NewProductRepository(db)
 
-	query := regexp.QuoteMeta(`DELETE from products where id = $1`)
+	query := regexp.QuoteMeta(`DELETE from products where id = $1 and version = $2`)
 	mock.ExpectExec(query).
-		WithArgs(1).
+		WithArgs(1, 3).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.Delete(1)
+	err = repo.Delete(context.Background(), 1, 3)
 
 	assert.NoError(t, err)
 }
@@ -201,13 +257,101 @@ func TestProductRepository_Delete_NotFound(t *testing.T) {
 
 	repo := NewProductRepository(db)
 
-	query := regexp.QuoteMeta(`DELETE from products where id = $1`)
+	query := regexp.QuoteMeta(`DELETE from products where id = $1 and version = $2`)
 	mock.ExpectExec(query).
-		WithArgs(1).
+		WithArgs(1, 3).
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-	err = repo.Delete(1)
+	err = repo.Delete(context.Background(), 1, 3)
 
 	assert.Error(t, err)
 	assert.Equal(t, "product not found", err.Error())
 }
+
+func TestProductRepository_Patch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+
+	patch := models.ProductPatch{
+		Price:   models.PatchField[float64]{Set: true, Value: 12000},
+		Stock:   models.PatchField[int]{Set: true, Value: 4},
+		Version: 3,
+	}
+
+	// hanya kolom yang dikirim yang diubah
+	query := regexp.QuoteMeta(`UPDATE products SET price = $1, updated_at = NOW(), version = version + 1 WHERE id = $2 AND version = $3`)
+	mock.ExpectBegin()
+	mock.ExpectExec(query).
+		WithArgs(12000.0, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO outlet_stock`).
+		WithArgs(1, 1, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Patch(outletContext(), 1, patch)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_Patch_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE products SET updated_at = NOW(), version = version + 1 WHERE id = $1 AND version = $2`)).
+		WithArgs(1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	err = repo.Patch(outletContext(), 1, models.ProductPatch{})
+
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestProductRepository_Patch_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+
+	patch := models.ProductPatch{
+		Price:   models.PatchField[float64]{Set: true, Value: 12000},
+		Version: 3,
+	}
+
+	// supervisor lain sudah menyimpan perubahan, version di database sudah 4
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE products SET price = $1, updated_at = NOW(), version = version + 1 WHERE id = $2 AND version = $3`)).
+		WithArgs(12000.0, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err = repo.Patch(outletContext(), 1, patch)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
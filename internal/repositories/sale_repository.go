@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+type SaleRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Sale, error)
+}
+
+type SaleRepository struct {
+	db DBTX
+}
+
+func NewSaleRepository(db DBTX) SaleRepositoryInterface {
+	return &SaleRepository{
+		db: db,
+	}
+}
+
+// GetByID hanya mengembalikan sale milik outlet di context, sale outlet lain dianggap tidak ada
+func (repo *SaleRepository) GetByID(ctx context.Context, id int) (*models.Sale, error) {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT
+				id, invoice_number, outlet_id, customer_id, subtotal, discount_total, tax_total,
+				total, paid_amount, change_amount, points_earned, points_redeemed, created_at
+			FROM sales
+			WHERE id = $1 AND outlet_id = $2`
+
+	var sale models.Sale
+	err = repo.db.QueryRowContext(ctx, query, id, outletID).Scan(
+		&sale.ID,
+		&sale.InvoiceNumber,
+		&sale.OutletID,
+		&sale.CustomerID,
+		&sale.Subtotal,
+		&sale.DiscountTotal,
+		&sale.TaxTotal,
+		&sale.Total,
+		&sale.PaidAmount,
+		&sale.ChangeAmount,
+		&sale.PointsEarned,
+		&sale.PointsRedeemed,
+		&sale.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSaleNotFound
+		}
+		return nil, err
+	}
+
+	sale.Items, err = repo.getItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sale.Payments, err = repo.getPayments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sale, nil
+}
+
+func (repo *SaleRepository) getItems(ctx context.Context, saleID int) ([]models.SaleItem, error) {
+	query := `SELECT
+				id, sale_id, product_id, product_name, quantity, price, discount, subtotal
+			FROM sale_items
+			WHERE sale_id = $1
+			ORDER BY id`
+
+	rows, err := repo.db.QueryContext(ctx, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.SaleItem, 0)
+	for rows.Next() {
+		var item models.SaleItem
+		err := rows.Scan(
+			&item.ID,
+			&item.SaleID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+			&item.Price,
+			&item.Discount,
+			&item.Subtotal,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repo *SaleRepository) getPayments(ctx context.Context, saleID int) ([]models.SalePayment, error) {
+	query := `SELECT id, sale_id, method, amount
+			FROM sale_payments
+			WHERE sale_id = $1
+			ORDER BY id`
+
+	rows, err := repo.db.QueryContext(ctx, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]models.SalePayment, 0)
+	for rows.Next() {
+		var payment models.SalePayment
+		err := rows.Scan(
+			&payment.ID,
+			&payment.SaleID,
+			&payment.Method,
+			&payment.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
@@ -0,0 +1,57 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaleRepository_Integration_GetByID(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewSaleRepository(db)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+	order := seedDraftOrder(t, outletCtx(1), db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 1})
+
+	sale, err := NewDraftOrderRepository(db).Checkout(outletCtx(1), order.ID, func(order *models.DraftOrder) (*models.Sale, error) {
+		return &models.Sale{
+			InvoiceNumber: "INV-TEST-1",
+			Subtotal:      15000,
+			TaxTotal:      1650,
+			Total:         16650,
+			PaidAmount:    16650,
+			Items:         []models.SaleItem{{ProductID: kopi.ID, ProductName: "Kopi", Quantity: 1, Price: 15000, Subtotal: 15000}},
+			Payments: []models.SalePayment{
+				{Method: "cash", Amount: 10000},
+				{Method: "qris", Amount: 6650},
+			},
+		}, nil
+	})
+	require.NoError(t, err)
+
+	found, err := repo.GetByID(outletCtx(1), sale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-TEST-1", found.InvoiceNumber)
+	assert.Equal(t, 1650.0, found.TaxTotal)
+	assert.Nil(t, found.CustomerID)
+	require.Len(t, found.Items, 1)
+	assert.Equal(t, 15000.0, found.Items[0].Price)
+	require.Len(t, found.Payments, 2)
+	assert.Equal(t, "qris", found.Payments[1].Method)
+	assert.Equal(t, 6650.0, found.Payments[1].Amount)
+
+	_, err = repo.GetByID(context.Background(), sale.ID)
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+
+	_, err = repo.GetByID(outletCtx(branch.ID), sale.ID)
+	assert.ErrorIs(t, err, ErrSaleNotFound)
+	_, err = repo.GetByID(outletCtx(1), 99)
+	assert.ErrorIs(t, err, ErrSaleNotFound)
+}
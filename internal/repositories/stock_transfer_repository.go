@@ -0,0 +1,349 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"sort"
+)
+
+type StockTransferRepositoryInterface interface {
+	GetAll(ctx context.Context, status string) ([]models.StockTransfer, error)
+	GetByID(ctx context.Context, id int) (*models.StockTransfer, error)
+	Create(ctx context.Context, transfer *models.StockTransfer) error
+	Receive(ctx context.Context, id int) error
+	Cancel(ctx context.Context, id int) error
+}
+
+type StockTransferRepository struct {
+	db DBTX
+}
+
+func NewStockTransferRepository(db DBTX) StockTransferRepositoryInterface {
+	return &StockTransferRepository{
+		db: db,
+	}
+}
+
+const stockTransferSelect = `SELECT id, from_outlet_id, to_outlet_id, status, note, created_at, received_at, updated_at
+			FROM stock_transfers`
+
+const stockTransferItemSelect = `SELECT i.id, i.transfer_id, i.product_id, p.name, i.quantity
+			FROM stock_transfer_items i
+			JOIN products p ON p.id = i.product_id`
+
+// GetAll mengembalikan transfer yang melibatkan outlet yang sedang dilayani (sebagai asal
+// maupun tujuan).
+func (repo *StockTransferRepository) GetAll(ctx context.Context, status string) ([]models.StockTransfer, error) {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := stockTransferSelect + `
+			WHERE ($1 = '' OR status = $1)
+				AND (from_outlet_id = $2 OR to_outlet_id = $2)
+			ORDER BY created_at DESC, id DESC`
+
+	rows, err := repo.db.QueryContext(ctx, query, status, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]models.StockTransfer, 0)
+	ids := make([]int, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		transfer, err := scanStockTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		index[transfer.ID] = len(transfers)
+		ids = append(ids, transfer.ID)
+		transfers = append(transfers, *transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return transfers, nil
+	}
+
+	items, err := scanStockTransferItems(repo.db.QueryContext(ctx, stockTransferItemSelect+`
+			WHERE i.transfer_id = ANY($1)
+			ORDER BY i.id`, ids))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		i := index[item.TransferID]
+		transfers[i].Items = append(transfers[i].Items, item)
+	}
+
+	return transfers, nil
+}
+
+// GetByID mengembalikan transfer dari/ke outlet yang sedang dilayani, transfer antar outlet
+// lain dianggap tidak ada.
+func (repo *StockTransferRepository) GetByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := getStockTransfer(ctx, repo.db, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.FromOutletID != outletID && transfer.ToOutletID != outletID {
+		return nil, ErrStockTransferNotFound
+	}
+
+	return transfer, nil
+}
+
+// Create mengirim barang dari outlet asal: stok asal langsung dikurangi dan transfer
+// berstatus in_transit sampai diterima outlet tujuan.
+func (repo *StockTransferRepository) Create(ctx context.Context, transfer *models.StockTransfer) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// kunci stok dengan urutan product_id yang sama untuk menghindari deadlock
+	items := make([]models.StockTransferItem, len(transfer.Items))
+	copy(items, transfer.Items)
+	sort.Slice(items, func(i, j int) bool { return items[i].ProductID < items[j].ProductID })
+
+	for _, item := range items {
+		// baris yang sama dengan yang dikunci reserveStock saat kasir menambah item ke open tab
+		stock, err := lockOutletStock(ctx, tx, transfer.FromOutletID, item.ProductID)
+		if err != nil {
+			return err
+		}
+
+		// barang yang sedang dipegang open tab tidak boleh ikut dikirim
+		reserved, err := reservedStock(ctx, tx, transfer.FromOutletID, item.ProductID, 0)
+		if err != nil {
+			return err
+		}
+
+		if stock-reserved < item.Quantity {
+			return ErrInsufficientStock
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE outlet_stock SET stock = stock - $1, updated_at = NOW()
+				WHERE outlet_id = $2 AND product_id = $3`,
+			item.Quantity, transfer.FromOutletID, item.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `INSERT INTO stock_transfers (from_outlet_id, to_outlet_id, note)
+			VALUES ($1, $2, $3)
+			RETURNING id, status, created_at`
+
+	err = tx.QueryRowContext(ctx, query, transfer.FromOutletID, transfer.ToOutletID, transfer.Note).Scan(
+		&transfer.ID,
+		&transfer.Status,
+		&transfer.CreatedAt,
+	)
+	if err != nil {
+		return translateDBError(err, stockTransferConstraints)
+	}
+
+	for i := range transfer.Items {
+		item := &transfer.Items[i]
+		item.TransferID = transfer.ID
+		err := tx.QueryRowContext(ctx, `INSERT INTO stock_transfer_items (transfer_id, product_id, quantity)
+				VALUES ($1, $2, $3)
+				RETURNING id`,
+			transfer.ID, item.ProductID, item.Quantity,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Receive menambahkan stok ke outlet tujuan dan menutup transfer.
+// hanya outlet tujuan yang boleh menerima.
+func (repo *StockTransferRepository) Receive(ctx context.Context, id int) error {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	transfer, err := getStockTransfer(ctx, tx, id, true)
+	if err != nil {
+		return err
+	}
+
+	if outletID != transfer.ToOutletID {
+		return apperrors.Forbidden("WRONG_OUTLET", "transfer can only be received by the destination outlet")
+	}
+
+	if transfer.Status != models.StockTransferStatusInTransit {
+		return ErrStockTransferNotInTransit
+	}
+
+	for _, item := range transfer.Items {
+		_, err := tx.ExecContext(ctx, `INSERT INTO outlet_stock (outlet_id, product_id, stock)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (outlet_id, product_id)
+				DO UPDATE SET stock = outlet_stock.stock + EXCLUDED.stock, updated_at = NOW()`,
+			transfer.ToOutletID, item.ProductID, item.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE stock_transfers
+			SET status = 'received', received_at = NOW(), updated_at = NOW()
+			WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Cancel membatalkan transfer yang masih in_transit dan mengembalikan stok ke outlet asal
+func (repo *StockTransferRepository) Cancel(ctx context.Context, id int) error {
+	outletID, err := outlet.RequireID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	transfer, err := getStockTransfer(ctx, tx, id, true)
+	if err != nil {
+		return err
+	}
+
+	if outletID != transfer.FromOutletID {
+		return apperrors.Forbidden("WRONG_OUTLET", "transfer can only be cancelled by the source outlet")
+	}
+
+	if transfer.Status != models.StockTransferStatusInTransit {
+		return ErrStockTransferNotInTransit
+	}
+
+	for _, item := range transfer.Items {
+		_, err := tx.ExecContext(ctx, `UPDATE outlet_stock SET stock = stock + $1, updated_at = NOW()
+				WHERE outlet_id = $2 AND product_id = $3`,
+			item.Quantity, transfer.FromOutletID, item.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE stock_transfers SET status = 'cancelled', updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func getStockTransfer(ctx context.Context, q DBTX, id int, forUpdate bool) (*models.StockTransfer, error) {
+	query := stockTransferSelect + `
+			WHERE id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	transfer, err := scanStockTransfer(q.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStockTransferNotFound
+		}
+		return nil, err
+	}
+
+	transfer.Items, err = scanStockTransferItems(q.QueryContext(ctx, stockTransferItemSelect+`
+			WHERE i.transfer_id = $1
+			ORDER BY i.id`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// rowScanner dipenuhi oleh *sql.Row maupun *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanStockTransfer(row rowScanner) (*models.StockTransfer, error) {
+	transfer := models.StockTransfer{Items: make([]models.StockTransferItem, 0)}
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.FromOutletID,
+		&transfer.ToOutletID,
+		&transfer.Status,
+		&transfer.Note,
+		&transfer.CreatedAt,
+		&transfer.ReceivedAt,
+		&transfer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func scanStockTransferItems(rows *sql.Rows, err error) ([]models.StockTransferItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.StockTransferItem, 0)
+	for rows.Next() {
+		var item models.StockTransferItem
+		err := rows.Scan(
+			&item.ID,
+			&item.TransferID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
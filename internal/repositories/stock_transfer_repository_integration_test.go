@@ -0,0 +1,199 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockTransferRepository_Integration_Receive(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewStockTransferRepository(db)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+	teh := seedProduct(t, outletCtx(1), db, category.ID, "Teh", 5000, 10)
+	// teh sudah pernah distok di cabang, kopi belum
+	testDB.Exec(t, `INSERT INTO outlet_stock (outlet_id, product_id, stock) VALUES ($1, $2, 3)`, branch.ID, teh.ID)
+
+	transfer := &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   branch.ID,
+		Note:         strPtr("Restock"),
+		Items: []models.StockTransferItem{
+			{ProductID: teh.ID, Quantity: 4},
+			{ProductID: kopi.ID, Quantity: 6},
+		},
+	}
+	require.NoError(t, repo.Create(outletCtx(1), transfer))
+	assert.Equal(t, models.StockTransferStatusInTransit, transfer.Status)
+	assert.Equal(t, transfer.ID, transfer.Items[0].TransferID)
+	assert.NotZero(t, transfer.Items[1].ID)
+
+	// stok asal langsung berkurang
+	assert.Equal(t, 4, outletStock(t, db, 1, kopi.ID))
+	assert.Equal(t, 6, outletStock(t, db, 1, teh.ID))
+
+	found, err := repo.GetByID(outletCtx(1), transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Restock", *found.Note)
+	require.Len(t, found.Items, 2)
+	assert.Equal(t, "Teh", found.Items[0].ProductName)
+	assert.Nil(t, found.ReceivedAt)
+
+	// hanya outlet tujuan yang boleh menerima
+	err = repo.Receive(outletCtx(1), transfer.ID)
+	assertAppError(t, err, apperrors.ErrForbidden, "WRONG_OUTLET")
+
+	require.NoError(t, repo.Receive(outletCtx(branch.ID), transfer.ID))
+	assert.Equal(t, 6, outletStock(t, db, branch.ID, kopi.ID))
+	assert.Equal(t, 7, outletStock(t, db, branch.ID, teh.ID))
+
+	found, err = repo.GetByID(outletCtx(branch.ID), transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StockTransferStatusReceived, found.Status)
+	assert.NotNil(t, found.ReceivedAt)
+
+	err = repo.Receive(outletCtx(branch.ID), transfer.ID)
+	assert.ErrorIs(t, err, ErrStockTransferNotInTransit)
+	err = repo.Cancel(outletCtx(1), transfer.ID)
+	assert.ErrorIs(t, err, ErrStockTransferNotInTransit)
+}
+
+func TestStockTransferRepository_Integration_Cancel(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewStockTransferRepository(db)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+
+	transfer := &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   branch.ID,
+		Items:        []models.StockTransferItem{{ProductID: kopi.ID, Quantity: 4}},
+	}
+	require.NoError(t, repo.Create(outletCtx(1), transfer))
+
+	// hanya outlet asal yang boleh membatalkan
+	err := repo.Cancel(outletCtx(branch.ID), transfer.ID)
+	assertAppError(t, err, apperrors.ErrForbidden, "WRONG_OUTLET")
+
+	require.NoError(t, repo.Cancel(outletCtx(1), transfer.ID))
+	assert.Equal(t, 10, outletStock(t, db, 1, kopi.ID))
+
+	err = repo.Receive(outletCtx(branch.ID), transfer.ID)
+	assert.ErrorIs(t, err, ErrStockTransferNotInTransit)
+
+	_, err = repo.GetByID(outletCtx(1), 99)
+	assert.ErrorIs(t, err, ErrStockTransferNotFound)
+	err = repo.Receive(outletCtx(1), 99)
+	assert.ErrorIs(t, err, ErrStockTransferNotFound)
+	err = repo.Cancel(outletCtx(1), 99)
+	assert.ErrorIs(t, err, ErrStockTransferNotFound)
+
+	// outlet wajib ada di context
+	_, err = repo.GetByID(context.Background(), transfer.ID)
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+	err = repo.Receive(context.Background(), transfer.ID)
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+	err = repo.Cancel(context.Background(), transfer.ID)
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+}
+
+func TestStockTransferRepository_Integration_CreateRejected(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewStockTransferRepository(db)
+	branch := seedOutlet(t, db, "CBG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 5)
+	teh := seedProduct(t, outletCtx(1), db, category.ID, "Teh", 5000, 5)
+	seedDraftOrder(t, outletCtx(1), db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 3})
+
+	// stok yang dipegang open tab tidak boleh dikirim, dan transaksi dibatalkan seluruhnya
+	err := repo.Create(outletCtx(1), &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   branch.ID,
+		Items: []models.StockTransferItem{
+			{ProductID: teh.ID, Quantity: 2},
+			{ProductID: kopi.ID, Quantity: 3},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Equal(t, 5, outletStock(t, db, 1, teh.ID))
+
+	// produk yang tidak pernah distok di outlet asal
+	err = repo.Create(outletCtx(branch.ID), &models.StockTransfer{
+		FromOutletID: branch.ID,
+		ToOutletID:   1,
+		Items:        []models.StockTransferItem{{ProductID: kopi.ID, Quantity: 1}},
+	})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	err = repo.Create(outletCtx(1), &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   99,
+		Items:        []models.StockTransferItem{{ProductID: kopi.ID, Quantity: 1}},
+	})
+	assertAppError(t, err, apperrors.ErrUnprocessable, "OUTLET_NOT_FOUND")
+	assert.Equal(t, 5, outletStock(t, db, 1, kopi.ID))
+}
+
+func TestStockTransferRepository_Integration_GetAll(t *testing.T) {
+	db := integrationDB(t)
+	repo := NewStockTransferRepository(db)
+	branch := seedOutlet(t, db, "CBG")
+	warehouse := seedOutlet(t, db, "GDG")
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+
+	create := func(to int) *models.StockTransfer {
+		transfer := &models.StockTransfer{
+			FromOutletID: 1,
+			ToOutletID:   to,
+			Items:        []models.StockTransferItem{{ProductID: kopi.ID, Quantity: 1}},
+		}
+		require.NoError(t, repo.Create(outletCtx(1), transfer))
+		return transfer
+	}
+	toBranch := create(branch.ID)
+	toWarehouse := create(warehouse.ID)
+	require.NoError(t, repo.Receive(outletCtx(warehouse.ID), toWarehouse.ID))
+
+	all, err := repo.GetAll(outletCtx(1), "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	// yang terbaru lebih dulu, lengkap dengan item
+	assert.Equal(t, toWarehouse.ID, all[0].ID)
+	require.Len(t, all[0].Items, 1)
+	assert.Equal(t, "Kopi", all[0].Items[0].ProductName)
+
+	inTransit, err := repo.GetAll(outletCtx(1), models.StockTransferStatusInTransit)
+	require.NoError(t, err)
+	require.Len(t, inTransit, 1)
+	assert.Equal(t, toBranch.ID, inTransit[0].ID)
+
+	// hanya transfer dari/ke outlet yang sedang dilayani
+	forBranch, err := repo.GetAll(outletCtx(branch.ID), "")
+	require.NoError(t, err)
+	require.Len(t, forBranch, 1)
+	assert.Equal(t, toBranch.ID, forBranch[0].ID)
+
+	// transfer ke cabang tidak terlihat dari gudang
+	_, err = repo.GetByID(outletCtx(warehouse.ID), toBranch.ID)
+	assert.ErrorIs(t, err, ErrStockTransferNotFound)
+
+	_, err = repo.GetAll(context.Background(), "")
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+
+	none, err := repo.GetAll(outletCtx(1), models.StockTransferStatusCancelled)
+	require.NoError(t, err)
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var stockTransferColumns = []string{
+	"id", "from_outlet_id", "to_outlet_id", "status", "note", "created_at", "received_at", "updated_at",
+}
+
+func TestStockTransferRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewStockTransferRepository(db)
+
+	transfer := &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   2,
+		Items:        []models.StockTransferItem{{ProductID: 5, Quantity: 3}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT stock FROM outlet_stock WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE`)).
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(10))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(i.quantity\), 0\) FROM draft_order_items i`).
+		WithArgs(1, 5, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(2))
+	mock.ExpectExec(`UPDATE outlet_stock SET stock = stock - \$1`).
+		WithArgs(3, 1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO stock_transfers`).
+		WithArgs(1, 2, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(7, "in_transit", time.Now()))
+	mock.ExpectQuery(`INSERT INTO stock_transfer_items`).
+		WithArgs(7, 5, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err = repo.Create(context.Background(), transfer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, transfer.ID)
+	assert.Equal(t, models.StockTransferStatusInTransit, transfer.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockTransferRepository_Create_ReservedStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewStockTransferRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT stock FROM outlet_stock WHERE outlet_id = $1 AND product_id = $2 FOR UPDATE`)).
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(10))
+	// 8 sedang dipegang open tab, yang bisa dikirim hanya 2
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(i.quantity\), 0\) FROM draft_order_items i`).
+		WithArgs(1, 5, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(8))
+	mock.ExpectRollback()
+
+	err = repo.Create(context.Background(), &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   2,
+		Items:        []models.StockTransferItem{{ProductID: 5, Quantity: 3}},
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, "insufficient stock", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockTransferRepository_Receive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewStockTransferRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM stock_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(stockTransferColumns).AddRow(7, 1, 2, "in_transit", nil, time.Now(), nil, nil))
+	mock.ExpectQuery(`SELECT .* FROM stock_transfer_items i JOIN products p ON p.id = i.product_id WHERE i.transfer_id = \$1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transfer_id", "product_id", "name", "quantity"}).AddRow(1, 7, 5, "Es Teh", 3))
+	mock.ExpectExec(`INSERT INTO outlet_stock`).
+		WithArgs(2, 5, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE stock_transfers SET status = 'received'`).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Receive(outlet.WithID(context.Background(), 2), 7)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockTransferRepository_Receive_WrongOutlet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewStockTransferRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM stock_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(stockTransferColumns).AddRow(7, 1, 2, "in_transit", nil, time.Now(), nil, nil))
+	mock.ExpectQuery(`SELECT .* FROM stock_transfer_items i`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transfer_id", "product_id", "name", "quantity"}))
+	mock.ExpectRollback()
+
+	err = repo.Receive(outletContext(), 7)
+
+	assert.Error(t, err)
+	assert.Equal(t, "transfer can only be received by the destination outlet", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutletRepository_GetConsolidatedStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewOutletRepository(db)
+
+	override := 4000.0
+	mock.ExpectQuery(`SELECT .* FROM outlet_stock os JOIN products p ON p.id = os.product_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"outlet_id", "product_id", "name", "stock", "reserved", "price", "price_override"}).
+			AddRow(1, 5, "Es Teh", 10, 2, 3000.0, nil).
+			AddRow(2, 5, "Es Teh", 4, 0, 4000.0, &override))
+	mock.ExpectQuery(`SELECT i.product_id, SUM\(i.quantity\) FROM stock_transfer_items i`).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "sum"}).AddRow(5, 3))
+
+	stocks, err := repo.GetConsolidatedStock(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, stocks, 1)
+	assert.Equal(t, 14, stocks[0].Stock)
+	assert.Equal(t, 2, stocks[0].Reserved)
+	assert.Equal(t, 3, stocks[0].InTransit)
+	assert.Len(t, stocks[0].Outlets, 2)
+	assert.Equal(t, 8, stocks[0].Outlets[0].Available)
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"log/slog"
+	"time"
+)
+
+// DBTX dipenuhi oleh *sql.DB maupun *sql.Tx. repository dibuat dari DBTX, jadi repository yang
+// sama bisa dipakai langsung ke database atau di dalam transaksi dari TxManager (lihat WithTx)
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// TxManager menjalankan beberapa operasi repository (bisa dari repository yang berbeda)
+// dalam satu transaksi database
+type TxManager interface {
+	// WithinTx menjalankan fn dalam transaksi. repository yang dipakai di dalam fn dibuat dari tx
+	// lewat WithTx. commit kalau fn berhasil, rollback kalau fn error atau panic.
+	// fn bisa dijalankan ulang saat serialization failure atau deadlock, jadi jangan
+	// mengubah state di luar database di dalamnya
+	WithinTx(ctx context.Context, fn func(tx DBTX) error) error
+}
+
+type TxConfig struct {
+	Isolation sql.IsolationLevel // LevelDefault = read committed
+	// MaxAttempts jumlah percobaan maksimal untuk error yang bisa diulang, minimal 1
+	MaxAttempts int
+	// RetryDelay jeda sebelum percobaan ke-2, dikali nomor percobaan untuk percobaan berikutnya
+	RetryDelay time.Duration
+}
+
+type SQLTxManager struct {
+	db     *sql.DB
+	config TxConfig
+}
+
+func NewTxManager(db *sql.DB, config TxConfig) TxManager {
+	return &SQLTxManager{
+		db:     db,
+		config: config,
+	}
+}
+
+func (m *SQLTxManager) WithinTx(ctx context.Context, fn func(tx DBTX) error) error {
+	attempts := max(m.config.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || attempt >= attempts || !apperrors.IsRetryable(err) {
+			return err
+		}
+
+		slog.WarnContext(ctx, "retrying transaction", "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(m.config.RetryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+func (m *SQLTxManager) run(ctx context.Context, fn func(tx DBTX) error) error {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: m.config.Isolation})
+	if err != nil {
+		return apperrors.FromDB(err)
+	}
+
+	// panic tetap diteruskan setelah transaksi dibatalkan
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(ctx, tx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		rollback(ctx, tx)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		// ctx selesai sebelum commit: database/sql sudah membatalkan transaksinya sendiri
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return apperrors.FromDB(err)
+	}
+	return nil
+}
+
+// rollback membatalkan transaksi. kalau ctx sudah dibatalkan, database/sql sudah melakukan
+// rollback sendiri dan Rollback mengembalikan sql.ErrTxDone, itu bukan kegagalan
+func rollback(ctx context.Context, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		slog.ErrorContext(ctx, "transaction rollback failed", "error", err)
+	}
+}
+
+// txBeginner dipenuhi *sql.DB, tidak oleh *sql.Tx
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// localTx transaksi milik satu method repository. kalau repository dibuat dari transaksi
+// TxManager, method ikut transaksi itu dan Commit/Rollback diserahkan ke WithinTx
+type localTx struct {
+	DBTX
+	tx *sql.Tx // nil kalau ikut transaksi luar
+}
+
+func beginTx(ctx context.Context, db DBTX) (*localTx, error) {
+	beginner, ok := db.(txBeginner)
+	if !ok {
+		return &localTx{DBTX: db}, nil
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &localTx{DBTX: tx, tx: tx}, nil
+}
+
+func (t *localTx) Commit() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+func (t *localTx) Rollback() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Rollback()
+}
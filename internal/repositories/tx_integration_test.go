@@ -0,0 +1,68 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_Integration_CommitAndRollback(t *testing.T) {
+	db := integrationDB(t)
+	manager := NewTxManager(db, TxConfig{MaxAttempts: 1})
+	loyaltyRepo := NewLoyaltyRepository(db)
+	draftOrderRepo := NewDraftOrderRepository(db)
+	category := seedCategory(t, db, "Minuman")
+	kopi := seedProduct(t, outletCtx(1), db, category.ID, "Kopi", 15000, 10)
+	customer := seedCustomer(t, db, "08123456789")
+	require.NoError(t, loyaltyRepo.Earn(context.Background(), &models.LoyaltyEntry{CustomerID: customer.ID, Points: 50}))
+	order := seedDraftOrder(t, outletCtx(1), db, models.DraftOrderItem{ProductID: kopi.ID, Quantity: 2})
+
+	// penukaran poin ikut batal karena checkout gagal di transaksi yang sama
+	failure := errors.New("payment declined")
+	ctx := outletCtx(1)
+	err := manager.WithinTx(ctx, func(tx DBTX) error {
+		if _, err := loyaltyRepo.WithTx(tx).Redeem(ctx, customer.ID, 20, nil); err != nil {
+			return err
+		}
+		_, err := draftOrderRepo.WithTx(tx).Checkout(ctx, order.ID, func(order *models.DraftOrder) (*models.Sale, error) {
+			return nil, failure
+		})
+		return err
+	})
+	assert.ErrorIs(t, err, failure)
+
+	balance, err := loyaltyRepo.Balance(context.Background(), customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 50, balance)
+	assert.Equal(t, 10, outletStock(t, db, 1, kopi.ID))
+
+	err = manager.WithinTx(ctx, func(tx DBTX) error {
+		if _, err := loyaltyRepo.WithTx(tx).Redeem(ctx, customer.ID, 20, nil); err != nil {
+			return err
+		}
+		_, err := draftOrderRepo.WithTx(tx).Checkout(ctx, order.ID, func(order *models.DraftOrder) (*models.Sale, error) {
+			return &models.Sale{
+				InvoiceNumber: "INV-TX-1",
+				CustomerID:    &customer.ID,
+				Subtotal:      30000,
+				Total:         30000,
+				PaidAmount:    30000,
+				Items:         []models.SaleItem{{ProductID: kopi.ID, ProductName: "Kopi", Quantity: 2, Price: 15000, Subtotal: 30000}},
+				Payments:      []models.SalePayment{{Method: "cash", Amount: 30000}},
+			}, nil
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	balance, err = loyaltyRepo.Balance(context.Background(), customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 30, balance)
+	assert.Equal(t, 8, outletStock(t, db, 1, kopi.ID))
+}
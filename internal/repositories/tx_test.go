@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTxManager(t *testing.T, maxAttempts int) (TxManager, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	manager := NewTxManager(db, TxConfig{MaxAttempts: maxAttempts, RetryDelay: time.Millisecond})
+	return manager, mock, func() { db.Close() }
+}
+
+func TestTxManager_RepositoriesShareTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	manager := NewTxManager(db, TxConfig{MaxAttempts: 1})
+
+	// satu BEGIN/COMMIT untuk semua repository, termasuk method yang biasanya membuka transaksi sendiri
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(4, time.Now(), nil),
+	)
+	mock.ExpectQuery(`INSERT INTO draft_orders`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(7, models.DraftOrderStatusOpen, time.Now()),
+	)
+	mock.ExpectCommit()
+
+	ctx := outlet.WithID(context.Background(), 1)
+	err = manager.WithinTx(ctx, func(tx DBTX) error {
+		if err := NewCustomerRepository(tx).Create(ctx, &models.Customer{Name: "Budi", Phone: "0812"}); err != nil {
+			return err
+		}
+		return NewDraftOrderRepository(db).WithTx(tx).Create(ctx, &models.DraftOrder{})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_RollbackOnError(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 3)
+	defer closeDB()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	failure := errors.New("stock movement failed")
+	err := manager.WithinTx(context.Background(), func(tx DBTX) error {
+		return failure
+	})
+
+	assert.Same(t, failure, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_RetriesSerializationFailure(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 3)
+	defer closeDB()
+
+	// percobaan pertama gagal saat query, kedua saat commit, ketiga berhasil
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(&pgconn.PgError{Code: apperrors.PgSerializationFailure})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	calls := 0
+	err := manager.WithinTx(context.Background(), func(tx DBTX) error {
+		calls++
+		if calls == 1 {
+			return apperrors.FromDB(&pgconn.PgError{Code: apperrors.PgDeadlockDetected})
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_GivesUpAfterMaxAttempts(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 2)
+	defer closeDB()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	calls := 0
+	err := manager.WithinTx(context.Background(), func(tx DBTX) error {
+		calls++
+		return &pgconn.PgError{Code: apperrors.PgSerializationFailure}
+	})
+
+	assert.True(t, apperrors.IsRetryable(err))
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_DoesNotRetryOtherErrors(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 3)
+	defer closeDB()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	calls := 0
+	err := manager.WithinTx(context.Background(), func(tx DBTX) error {
+		calls++
+		return ErrInsufficientStock
+	})
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_StopsRetryingWhenContextDone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	manager := NewTxManager(db, TxConfig{MaxAttempts: 3, RetryDelay: time.Hour})
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err = manager.WithinTx(ctx, func(tx DBTX) error {
+		calls++
+		cancel()
+		return &pgconn.PgError{Code: apperrors.PgSerializationFailure}
+	})
+
+	assert.True(t, apperrors.IsRetryable(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestTxManager_ContextCanceledBeforeCommit(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 3)
+	defer closeDB()
+
+	// database/sql membatalkan transaksi sendiri begitu ctx selesai
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := manager.WithinTx(ctx, func(tx DBTX) error {
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTxManager_RollbackOnPanic(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 3)
+	defer closeDB()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		manager.WithinTx(context.Background(), func(tx DBTX) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_RepositoryRollbackLeftToManager(t *testing.T) {
+	manager, mock, closeDB := newTestTxManager(t, 3)
+	defer closeDB()
+
+	// Redeem biasanya membuka transaksi sendiri, di dalam WithinTx ikut transaksi luar
+	// sehingga hanya ada satu BEGIN dan satu ROLLBACK
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, remaining FROM loyalty_ledger`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "remaining"}).AddRow(1, 5),
+	)
+	mock.ExpectRollback()
+
+	repo := NewLoyaltyRepository(nil)
+	err := manager.WithinTx(context.Background(), func(tx DBTX) error {
+		_, err := repo.WithTx(tx).Redeem(context.Background(), 1, 10, nil)
+		return err
+	})
+
+	assert.ErrorIs(t, err, ErrInsufficientPoints)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,155 @@
+// Package router membungkus http.ServeMux (pola method Go 1.22+ seperti "GET /products/{id}")
+// dengan middleware chain, route group untuk prefix seperti /api/v1, dan response 404/405
+// dalam format utils.ErrorResponse. OPTIONS dijawab otomatis dengan header Allow.
+package router
+
+import (
+	"bytes"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Middleware membungkus handler, sama dengan bentuk middleware.Timeout, outlet.Require, dst.
+type Middleware func(http.Handler) http.Handler
+
+// Chain memasang middleware ke h, middleware pertama menjadi yang paling luar
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+type Router struct {
+	mux         *http.ServeMux
+	middlewares []Middleware
+	patterns    []string
+
+	once    sync.Once
+	handler http.Handler
+}
+
+func New() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+// Use menambah middleware global yang membungkus semua request, termasuk 404 dan 405.
+// harus dipanggil sebelum request pertama.
+func (rt *Router) Use(middlewares ...Middleware) {
+	rt.middlewares = append(rt.middlewares, middlewares...)
+}
+
+// Group membuat group dengan prefix path dan middleware yang hanya berlaku untuk route di dalamnya
+func (rt *Router) Group(prefix string, middlewares ...Middleware) *Group {
+	return &Group{router: rt, prefix: prefix, middlewares: middlewares}
+}
+
+// Handle mendaftarkan route tanpa prefix, misalnya "GET /livez"
+func (rt *Router) Handle(pattern string, h http.Handler) {
+	rt.Group("").Handle(pattern, h)
+}
+
+func (rt *Router) HandleFunc(pattern string, h http.HandlerFunc) {
+	rt.Group("").Handle(pattern, h)
+}
+
+// Routes mengembalikan semua pola yang terdaftar (lengkap dengan prefix group) sesuai urutan daftar
+func (rt *Router) Routes() []string {
+	return append([]string(nil), rt.patterns...)
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.once.Do(func() {
+		rt.handler = Chain(http.HandlerFunc(rt.serve), rt.middlewares...)
+	})
+	rt.handler.ServeHTTP(w, r)
+}
+
+func (rt *Router) serve(w http.ResponseWriter, r *http.Request) {
+	h, pattern := rt.mux.Handler(r)
+	if pattern != "" {
+		// ServeHTTP (bukan h) supaya r.Pattern dan PathValue terisi
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+
+	// tidak ada route yang cocok: ServeMux menjawab 404 atau 405 (dengan Allow).
+	// jawabannya direkam lalu diganti dengan body JSON.
+	rec := &recorder{header: make(http.Header), status: http.StatusOK}
+	h.ServeHTTP(rec, r)
+
+	switch rec.status {
+	case http.StatusNotFound:
+		utils.SendError(w, "NOT_FOUND", "Route not found", http.StatusNotFound)
+	case http.StatusMethodNotAllowed:
+		allow := rec.header.Get("Allow") + ", " + http.MethodOptions
+		w.Header().Set("Allow", allow)
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		utils.SendError(w, "METHOD_NOT_ALLOWED", "Method not allowed, use one of: "+allow, http.StatusMethodNotAllowed)
+	default:
+		for key, values := range rec.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	}
+}
+
+// Group sekumpulan route dengan prefix dan middleware yang sama
+type Group struct {
+	router      *Router
+	prefix      string
+	middlewares []Middleware
+}
+
+// Group membuat sub-group, middleware group induk tetap berlaku dan dijalankan lebih dulu
+func (g *Group) Group(prefix string, middlewares ...Middleware) *Group {
+	return &Group{
+		router:      g.router,
+		prefix:      g.prefix + prefix,
+		middlewares: append(append([]Middleware{}, g.middlewares...), middlewares...),
+	}
+}
+
+// With sama seperti Group tanpa prefix, untuk middleware yang hanya dipakai sebagian route
+func (g *Group) With(middlewares ...Middleware) *Group {
+	return g.Group("", middlewares...)
+}
+
+// Handle mendaftarkan pattern "METHOD /path" relatif terhadap prefix group
+func (g *Group) Handle(pattern string, h http.Handler) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		// route tanpa method (misalnya file statis) menerima semua method
+		method, path = "", pattern
+	}
+
+	full := g.prefix + path
+	if method != "" {
+		full = method + " " + full
+	}
+	g.router.mux.Handle(full, Chain(h, g.middlewares...))
+	g.router.patterns = append(g.router.patterns, full)
+}
+
+func (g *Group) HandleFunc(pattern string, h http.HandlerFunc) {
+	g.Handle(pattern, h)
+}
+
+// recorder menyimpan response dari handler bawaan ServeMux untuk 404/405/redirect
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header { return rec.header }
+
+func (rec *recorder) WriteHeader(status int) { rec.status = status }
+
+func (rec *recorder) Write(b []byte) (int, error) { return rec.body.Write(b) }
@@ -0,0 +1,100 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tag(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middleware", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(r.Pattern + " " + r.PathValue("id")))
+}
+
+func newTestRouter() *Router {
+	rt := New()
+	rt.Use(tag("global"))
+	rt.HandleFunc("GET /livez", ok)
+
+	api := rt.Group("/api/v1", tag("api"))
+	api.HandleFunc("GET /products", ok)
+	api.HandleFunc("POST /products", ok)
+	api.With(tag("outlet")).HandleFunc("GET /products/{id}", ok)
+	api.HandleFunc("DELETE /products/{id}", ok)
+	return rt
+}
+
+func serve(rt *Router, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestRouter_GroupsAndMiddleware(t *testing.T) {
+	rt := newTestRouter()
+
+	w := serve(rt, http.MethodGet, "/api/v1/products/7")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GET /api/v1/products/{id} 7", w.Body.String())
+	assert.Equal(t, []string{"global", "api", "outlet"}, w.Header().Values("X-Middleware"))
+
+	w = serve(rt, http.MethodDelete, "/api/v1/products/7")
+	assert.Equal(t, []string{"global", "api"}, w.Header().Values("X-Middleware"))
+
+	w = serve(rt, http.MethodGet, "/livez")
+	assert.Equal(t, []string{"global"}, w.Header().Values("X-Middleware"))
+}
+
+func TestRouter_Routes(t *testing.T) {
+	assert.Equal(t, []string{
+		"GET /livez",
+		"GET /api/v1/products",
+		"POST /api/v1/products",
+		"GET /api/v1/products/{id}",
+		"DELETE /api/v1/products/{id}",
+	}, newTestRouter().Routes())
+}
+
+func TestRouter_NotFound(t *testing.T) {
+	w := serve(newTestRouter(), http.MethodGet, "/api/v1/nothing")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"NOT_FOUND","message":"Route not found"}}`, w.Body.String())
+	// middleware global tetap jalan untuk 404
+	assert.Equal(t, []string{"global"}, w.Header().Values("X-Middleware"))
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	w := serve(newTestRouter(), http.MethodPut, "/api/v1/products")
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, POST, OPTIONS", w.Header().Get("Allow"))
+	assert.JSONEq(t, `{"error":{"code":"METHOD_NOT_ALLOWED","message":"Method not allowed, use one of: GET, HEAD, POST, OPTIONS"}}`, w.Body.String())
+}
+
+func TestRouter_Options(t *testing.T) {
+	w := serve(newTestRouter(), http.MethodOptions, "/api/v1/products/7")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestChain_Order(t *testing.T) {
+	h := Chain(http.HandlerFunc(ok), tag("first"), tag("second"))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"first", "second"}, w.Header().Values("X-Middleware"))
+}
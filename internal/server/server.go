@@ -0,0 +1,59 @@
+// Package server menjalankan http.Server sampai menerima sinyal shutdown, lalu menghentikannya
+// dengan rapi: /readyz dibuat 503 dulu, koneksi baru ditolak, dan request yang sedang berjalan
+// ditunggu selesai sampai batas waktu.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+type ShutdownConfig struct {
+	// DrainDelay jeda antara /readyz menjadi 503 dan berhenti menerima koneksi, supaya
+	// load balancer sempat mengeluarkan instance ini dari daftar target
+	DrainDelay time.Duration
+	// Timeout batas menunggu request yang sedang berjalan, setelah itu koneksi diputus paksa
+	Timeout time.Duration
+	// OnDrain dipanggil saat shutdown dimulai (health.Checker.Drain)
+	OnDrain func()
+}
+
+// Run melayani listener sampai ctx selesai (SIGTERM/SIGINT) atau server gagal.
+// mengembalikan nil kalau shutdown selesai sebelum Timeout.
+func Run(ctx context.Context, server *http.Server, listener net.Listener, config ShutdownConfig) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutdown started, draining", "drain_delay", config.DrainDelay.String(), "timeout", config.Timeout.String())
+	if config.OnDrain != nil {
+		config.OnDrain()
+	}
+	time.Sleep(config.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// masih ada request yang belum selesai setelah Timeout
+		server.Close()
+		return fmt.Errorf("shutdown: %w", err)
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	return listener
+}
+
+func TestRun_WaitsForInFlightRequests(t *testing.T) {
+	listener := listen(t)
+	started := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(w, "done")
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	drained := false
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- Run(ctx, server, listener, ShutdownConfig{Timeout: time.Second, OnDrain: func() { drained = true }})
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- result{body: string(body)}
+	}()
+
+	<-started
+	cancel()
+
+	got := <-response
+	assert.NoError(t, got.err)
+	assert.Equal(t, "done", got.body)
+	assert.NoError(t, <-runErr)
+	assert.True(t, drained)
+}
+
+func TestRun_TimeoutExceeded(t *testing.T) {
+	listener := listen(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- Run(ctx, server, listener, ShutdownConfig{Timeout: 50 * time.Millisecond})
+	}()
+
+	go http.Get("http://" + listener.Addr().String())
+	<-started
+	cancel()
+
+	assert.ErrorIs(t, <-runErr, context.DeadlineExceeded)
+}
+
+func TestRun_ServeError(t *testing.T) {
+	listener := listen(t)
+	listener.Close()
+
+	err := Run(context.Background(), &http.Server{}, listener, ShutdownConfig{})
+	assert.Error(t, err)
+}
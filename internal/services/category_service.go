@@ -1,16 +1,20 @@
 package services
 
 import (
+	"context"
 	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/internal/validation"
 	"fajar7xx/go-kasir-umam-ds/models"
 )
 
 type CategoryServiceInterface interface {
-	GetAll() ([]models.Category, error)
-	GetByID(id int) (*models.Category, error)
-	Create(category *models.Category) error
-	Update(id int, category *models.Category) (*models.Category, error)
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]models.Category, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
+	Update(ctx context.Context, id int, category *models.Category) (*models.Category, error)
+	Patch(ctx context.Context, id int, patch models.CategoryPatch) (*models.Category, error)
+	Delete(ctx context.Context, id int, version int) error
 }
 
 type CategoryService struct {
@@ -23,25 +27,45 @@ func NewCategoryService(categoryRepo repositories.CategoryRepositoryInterface) C
 	}
 }
 
-func (serv *CategoryService) GetAll() ([]models.Category, error) {
-	return serv.categoryRepo.GetAll()
+func (serv *CategoryService) GetAll(ctx context.Context) ([]models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetAll")
+	defer span.End()
+
+	return serv.categoryRepo.GetAll(ctx)
 }
 
-func (serv *CategoryService) GetByID(id int) (*models.Category, error) {
-	return serv.categoryRepo.GetByID(id)
+func (serv *CategoryService) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetByID")
+	defer span.End()
+
+	return serv.categoryRepo.GetByID(ctx, id)
 }
 
-func (serv *CategoryService) Create(category *models.Category) error {
-	return serv.categoryRepo.Create(category)
+func (serv *CategoryService) Create(ctx context.Context, category *models.Category) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.Create")
+	defer span.End()
+
+	if err := validation.Struct(category).Err(); err != nil {
+		return err
+	}
+
+	return serv.categoryRepo.Create(ctx, category)
 }
 
-func (serv *CategoryService) Update(id int, category *models.Category) (*models.Category, error) {
-	err := serv.categoryRepo.Update(id, category)
+func (serv *CategoryService) Update(ctx context.Context, id int, category *models.Category) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.Update")
+	defer span.End()
+
+	if err := validation.Struct(category).Err(); err != nil {
+		return nil, err
+	}
+
+	err := serv.categoryRepo.Update(ctx, id, category)
 	if err != nil {
 		return nil, err
 	}
 
-	updatedCategory, err := serv.categoryRepo.GetByID(id)
+	updatedCategory, err := serv.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +73,35 @@ func (serv *CategoryService) Update(id int, category *models.Category) (*models.
 	return updatedCategory, nil
 }
 
-func (serv *CategoryService) Delete(id int) error {
-	return serv.categoryRepo.Delete(id)
+// Patch menerapkan JSON merge patch: hanya field yang dikirim yang divalidasi dan disimpan
+func (serv *CategoryService) Patch(ctx context.Context, id int, patch models.CategoryPatch) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.Patch")
+	defer span.End()
+
+	category, err := serv.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch.Apply(category)
+	sent := map[string]bool{
+		"name":        patch.Name.Set,
+		"description": patch.Description.Set,
+	}
+	if err := onlySent(validation.Struct(category), sent).Err(); err != nil {
+		return nil, err
+	}
+
+	if err := serv.categoryRepo.Patch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+
+	return serv.categoryRepo.GetByID(ctx, id)
+}
+
+func (serv *CategoryService) Delete(ctx context.Context, id int, version int) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.Delete")
+	defer span.End()
+
+	return serv.categoryRepo.Delete(ctx, id, version)
 }
@@ -1,13 +1,18 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
 	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/validation"
 	"fajar7xx/go-kasir-umam-ds/models"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCategoryService_GetAll(t *testing.T) {
@@ -20,9 +25,9 @@ func TestCategoryService_GetAll(t *testing.T) {
 		{ID: 2, Name: "Beverage", CreatedAt: now},
 	}
 
-	mockRepo.On("GetAll").Return(expectedCategories, nil)
+	mockRepo.On("GetAll", mock.Anything).Return(expectedCategories, nil)
 
-	categories, err := service.GetAll()
+	categories, err := service.GetAll(context.Background())
 
 	assert.NoError(t, err)
 	assert.Len(t, categories, 2)
@@ -34,9 +39,9 @@ func TestCategoryService_GetAll_Error(t *testing.T) {
 	mockRepo := new(mocks.CategoryRepositoryMock)
 	service := NewCategoryService(mockRepo)
 
-	mockRepo.On("GetAll").Return(nil, errors.New("database error"))
+	mockRepo.On("GetAll", mock.Anything).Return(nil, errors.New("database error"))
 
-	categories, err := service.GetAll()
+	categories, err := service.GetAll(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, categories)
@@ -50,9 +55,9 @@ func TestCategoryService_GetByID(t *testing.T) {
 	now := time.Now()
 	expectedCategory := &models.Category{ID: 1, Name: "Food", CreatedAt: now}
 
-	mockRepo.On("GetByID", 1).Return(expectedCategory, nil)
+	mockRepo.On("GetByID", mock.Anything, 1).Return(expectedCategory, nil)
 
-	category, err := service.GetByID(1)
+	category, err := service.GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, category.ID)
@@ -66,9 +71,9 @@ func TestCategoryService_Create(t *testing.T) {
 
 	category := &models.Category{Name: "Food"}
 
-	mockRepo.On("Create", category).Return(nil)
+	mockRepo.On("Create", mock.Anything, category).Return(nil)
 
-	err := service.Create(category)
+	err := service.Create(context.Background(), category)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -83,11 +88,11 @@ func TestCategoryService_Update(t *testing.T) {
 	updatedCategory := &models.Category{ID: 1, Name: "Food Updated"}
 
 	// Expect Update to be called
-	mockRepo.On("Update", id, category).Return(nil)
+	mockRepo.On("Update", mock.Anything, id, category).Return(nil)
 	// Expect GetByID to be called after Update
-	mockRepo.On("GetByID", id).Return(updatedCategory, nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(updatedCategory, nil)
 
-	result, err := service.Update(id, category)
+	result, err := service.Update(context.Background(), id, category)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Food Updated", result.Name)
@@ -101,9 +106,9 @@ func TestCategoryService_Update_Error(t *testing.T) {
 	id := 1
 	category := &models.Category{Name: "Food Updated"}
 
-	mockRepo.On("Update", id, category).Return(errors.New("update failed"))
+	mockRepo.On("Update", mock.Anything, id, category).Return(errors.New("update failed"))
 
-	result, err := service.Update(id, category)
+	result, err := service.Update(context.Background(), id, category)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -115,10 +120,26 @@ func TestCategoryService_Delete(t *testing.T) {
 	service := NewCategoryService(mockRepo)
 
 	id := 1
-	mockRepo.On("Delete", id).Return(nil)
+	mockRepo.On("Delete", mock.Anything, id, 2).Return(nil)
 
-	err := service.Delete(id)
+	err := service.Delete(context.Background(), id, 2)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCategoryService_Create_Validation(t *testing.T) {
+	mockRepo := new(mocks.CategoryRepositoryMock)
+	service := NewCategoryService(mockRepo)
+
+	long := strings.Repeat("a", 1001)
+	err := service.Create(context.Background(), &models.Category{Description: &long})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	appErr, _ := apperrors.As(err)
+	assert.Equal(t, []validation.FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "description", Message: "must be at most 1000 characters"},
+	}, appErr.Details)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"strings"
+	"time"
+)
+
+type CustomerServiceInterface interface {
+	GetAll(ctx context.Context) ([]models.Customer, error)
+	GetByID(ctx context.Context, id int) (*models.Customer, error)
+	GetByPhone(ctx context.Context, phone string) (*models.Customer, error)
+	Create(ctx context.Context, customer *models.Customer) error
+	Update(ctx context.Context, id int, customer *models.Customer) (*models.Customer, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type CustomerService struct {
+	customerRepo repositories.CustomerRepositoryInterface
+}
+
+func NewCustomerService(customerRepo repositories.CustomerRepositoryInterface) CustomerServiceInterface {
+	return &CustomerService{
+		customerRepo: customerRepo,
+	}
+}
+
+func (serv *CustomerService) GetAll(ctx context.Context) ([]models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.GetAll")
+	defer span.End()
+
+	return serv.customerRepo.GetAll(ctx)
+}
+
+func (serv *CustomerService) GetByID(ctx context.Context, id int) (*models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.GetByID")
+	defer span.End()
+
+	return serv.customerRepo.GetByID(ctx, id)
+}
+
+// GetByPhone dipakai kasir untuk mencari member dari nomor HP,
+// format +62 / spasi / strip dinormalisasi dulu
+func (serv *CustomerService) GetByPhone(ctx context.Context, phone string) (*models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.GetByPhone")
+	defer span.End()
+
+	normalized, err := normalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.customerRepo.GetByPhone(ctx, normalized)
+}
+
+func (serv *CustomerService) Create(ctx context.Context, customer *models.Customer) error {
+	ctx, span := tracing.Start(ctx, "CustomerService.Create")
+	defer span.End()
+
+	if err := validateCustomer(customer); err != nil {
+		return err
+	}
+
+	return serv.customerRepo.Create(ctx, customer)
+}
+
+func (serv *CustomerService) Update(ctx context.Context, id int, customer *models.Customer) (*models.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.Update")
+	defer span.End()
+
+	if err := validateCustomer(customer); err != nil {
+		return nil, err
+	}
+
+	err := serv.customerRepo.Update(ctx, id, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.customerRepo.GetByID(ctx, id)
+}
+
+func (serv *CustomerService) Delete(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "CustomerService.Delete")
+	defer span.End()
+
+	return serv.customerRepo.Delete(ctx, id)
+}
+
+func validateCustomer(customer *models.Customer) error {
+	customer.Name = strings.TrimSpace(customer.Name)
+	if customer.Name == "" {
+		return apperrors.Validation("customer name is required")
+	}
+
+	phone, err := normalizePhone(customer.Phone)
+	if err != nil {
+		return err
+	}
+	customer.Phone = phone
+
+	if customer.Email != nil && *customer.Email != "" && !strings.Contains(*customer.Email, "@") {
+		return apperrors.Validation("invalid email address")
+	}
+
+	if customer.Birthday != nil && *customer.Birthday != "" {
+		if _, err := time.Parse(time.DateOnly, *customer.Birthday); err != nil {
+			return apperrors.Validation("birthday must use YYYY-MM-DD format")
+		}
+	}
+
+	return nil
+}
+
+// normalizePhone mengubah 0812-3456 7890 / +62 812 3456 7890 menjadi 081234567890
+func normalizePhone(phone string) (string, error) {
+	replacer := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+	phone = replacer.Replace(strings.TrimSpace(phone))
+
+	if strings.HasPrefix(phone, "+62") {
+		phone = "0" + strings.TrimPrefix(phone, "+62")
+	}
+
+	if len(phone) < 8 || len(phone) > 15 {
+		return "", apperrors.Validation("invalid phone number")
+	}
+
+	for _, r := range phone {
+		if r < '0' || r > '9' {
+			return "", apperrors.Validation("invalid phone number")
+		}
+	}
+
+	return phone, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCustomerService_GetByPhone_Normalized(t *testing.T) {
+	mockRepo := new(mocks.CustomerRepositoryMock)
+	service := NewCustomerService(mockRepo)
+
+	expected := &models.Customer{ID: 1, Name: "Budi", Phone: "081234567890"}
+	mockRepo.On("GetByPhone", mock.Anything, "081234567890").Return(expected, nil)
+
+	customer, err := service.GetByPhone(context.Background(), "+62 812-3456-7890")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi", customer.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerService_Create(t *testing.T) {
+	mockRepo := new(mocks.CustomerRepositoryMock)
+	service := NewCustomerService(mockRepo)
+
+	birthday := "1990-05-01"
+	customer := &models.Customer{Name: " Budi ", Phone: "0812 3456 7890", Birthday: &birthday}
+	mockRepo.On("Create", mock.Anything, customer).Return(nil)
+
+	err := service.Create(context.Background(), customer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi", customer.Name)
+	assert.Equal(t, "081234567890", customer.Phone)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerService_Create_Invalid(t *testing.T) {
+	mockRepo := new(mocks.CustomerRepositoryMock)
+	service := NewCustomerService(mockRepo)
+
+	birthday := "01-05-1990"
+	email := "budi"
+	cases := map[string]*models.Customer{
+		"missing name":    {Phone: "081234567890"},
+		"invalid phone":   {Name: "Budi", Phone: "08-abc"},
+		"invalid email":   {Name: "Budi", Phone: "081234567890", Email: &email},
+		"invalid birhday": {Name: "Budi", Phone: "081234567890", Birthday: &birthday},
+	}
+
+	for name, customer := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := service.Create(context.Background(), customer)
+			assert.Error(t, err)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fmt"
+	"math"
+	"time"
+)
+
+type DraftOrderServiceInterface interface {
+	GetOpen(ctx context.Context) ([]models.DraftOrder, error)
+	GetByID(ctx context.Context, id int) (*models.DraftOrder, error)
+	Create(ctx context.Context, order *models.DraftOrder) (*models.DraftOrder, error)
+	UpdateTag(ctx context.Context, id int, order *models.DraftOrder) (*models.DraftOrder, error)
+	AddItem(ctx context.Context, id int, item *models.DraftOrderItem) (*models.DraftOrder, error)
+	UpdateItem(ctx context.Context, id, itemID int, item *models.DraftOrderItem) (*models.DraftOrder, error)
+	RemoveItem(ctx context.Context, id, itemID int) (*models.DraftOrder, error)
+	Merge(ctx context.Context, targetID, sourceID int) (*models.DraftOrder, error)
+	Split(ctx context.Context, id int, lines []models.SplitLine) (*models.DraftOrder, error)
+	Cancel(ctx context.Context, id int) error
+	Checkout(ctx context.Context, id int, req *models.CheckoutRequest) (*models.Sale, error)
+}
+
+type DraftOrderService struct {
+	draftOrderRepo repositories.DraftOrderRepositoryInterface
+	// loyaltyRepo dipakai di dalam transaksi checkout, loyaltyService untuk aturan poin
+	loyaltyRepo    repositories.LoyaltyRepositoryInterface
+	loyaltyService LoyaltyServiceInterface
+	txManager      repositories.TxManager
+	// taxRate adalah pajak penjualan dalam pecahan, misalnya 0.11 untuk PPN 11%
+	taxRate float64
+}
+
+func NewDraftOrderService(
+	draftOrderRepo repositories.DraftOrderRepositoryInterface,
+	loyaltyRepo repositories.LoyaltyRepositoryInterface,
+	loyaltyService LoyaltyServiceInterface,
+	txManager repositories.TxManager,
+	taxRate float64,
+) DraftOrderServiceInterface {
+	return &DraftOrderService{
+		draftOrderRepo: draftOrderRepo,
+		loyaltyRepo:    loyaltyRepo,
+		loyaltyService: loyaltyService,
+		txManager:      txManager,
+		taxRate:        taxRate,
+	}
+}
+
+func (serv *DraftOrderService) GetOpen(ctx context.Context) ([]models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.GetOpen")
+	defer span.End()
+
+	return serv.draftOrderRepo.GetOpen(ctx)
+}
+
+func (serv *DraftOrderService) GetByID(ctx context.Context, id int) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.GetByID")
+	defer span.End()
+
+	return serv.draftOrderRepo.GetByID(ctx, id)
+}
+
+func (serv *DraftOrderService) Create(ctx context.Context, order *models.DraftOrder) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.Create")
+	defer span.End()
+
+	for _, item := range order.Items {
+		if err := validateDraftOrderItem(&item); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := serv.draftOrderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, order.ID)
+}
+
+func (serv *DraftOrderService) UpdateTag(ctx context.Context, id int, order *models.DraftOrder) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.UpdateTag")
+	defer span.End()
+
+	if err := serv.draftOrderRepo.UpdateTag(ctx, id, order); err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, id)
+}
+
+func (serv *DraftOrderService) AddItem(ctx context.Context, id int, item *models.DraftOrderItem) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.AddItem")
+	defer span.End()
+
+	if err := validateDraftOrderItem(item); err != nil {
+		return nil, err
+	}
+
+	if err := serv.draftOrderRepo.AddItem(ctx, id, item); err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, id)
+}
+
+func (serv *DraftOrderService) UpdateItem(ctx context.Context, id, itemID int, item *models.DraftOrderItem) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.UpdateItem")
+	defer span.End()
+
+	if item.Quantity <= 0 {
+		return nil, apperrors.Validation("quantity must be greater than 0")
+	}
+	if item.Discount < 0 {
+		return nil, apperrors.Validation("discount must not be negative")
+	}
+
+	if err := serv.draftOrderRepo.UpdateItem(ctx, id, itemID, item); err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, id)
+}
+
+func (serv *DraftOrderService) RemoveItem(ctx context.Context, id, itemID int) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.RemoveItem")
+	defer span.End()
+
+	if err := serv.draftOrderRepo.RemoveItem(ctx, id, itemID); err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, id)
+}
+
+func (serv *DraftOrderService) Merge(ctx context.Context, targetID, sourceID int) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.Merge")
+	defer span.End()
+
+	if targetID == sourceID {
+		return nil, apperrors.Validation("cannot merge a draft order into itself")
+	}
+
+	if err := serv.draftOrderRepo.Merge(ctx, targetID, sourceID); err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, targetID)
+}
+
+func (serv *DraftOrderService) Split(ctx context.Context, id int, lines []models.SplitLine) (*models.DraftOrder, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.Split")
+	defer span.End()
+
+	if len(lines) == 0 {
+		return nil, apperrors.Validation("at least one item is required to split")
+	}
+
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, apperrors.Validation("split quantity must be greater than 0")
+		}
+	}
+
+	newID, err := serv.draftOrderRepo.Split(ctx, id, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.draftOrderRepo.GetByID(ctx, newID)
+}
+
+func (serv *DraftOrderService) Cancel(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.Cancel")
+	defer span.End()
+
+	return serv.draftOrderRepo.Cancel(ctx, id)
+}
+
+func (serv *DraftOrderService) Checkout(ctx context.Context, id int, req *models.CheckoutRequest) (*models.Sale, error) {
+	ctx, span := tracing.Start(ctx, "DraftOrderService.Checkout")
+	defer span.End()
+
+	if len(req.Payments) == 0 {
+		return nil, apperrors.Validation("at least one payment is required")
+	}
+
+	for _, payment := range req.Payments {
+		if payment.Method == "" {
+			return nil, apperrors.Validation("payment method is required")
+		}
+		if payment.Amount <= 0 {
+			return nil, apperrors.Validation("payment amount must be greater than 0")
+		}
+	}
+
+	if req.Discount < 0 {
+		return nil, apperrors.Validation("discount must not be negative")
+	}
+
+	if req.RedeemPoints < 0 {
+		return nil, apperrors.Validation("redeem points must not be negative")
+	}
+
+	if req.RedeemPoints > 0 && req.CustomerID == nil {
+		return nil, apperrors.Validation("customer ID is required to redeem points")
+	}
+
+	// sale, penukaran poin dan poin yang didapat disimpan dalam satu transaksi: kalau poin tidak
+	// cukup atau gagal dicatat, sale ikut batal dan kasir bisa mengulang
+	var sale *models.Sale
+	err := serv.txManager.WithinTx(ctx, func(tx repositories.DBTX) error {
+		var err error
+		sale, err = serv.draftOrderRepo.WithTx(tx).Checkout(ctx, id, func(order *models.DraftOrder) (*models.Sale, error) {
+			return serv.buildSale(order, req, time.Now())
+		})
+		if err != nil {
+			return err
+		}
+
+		// sale disimpan lebih dulu supaya entry redeem di ledger terhubung ke sale-nya
+		loyaltyRepo := serv.loyaltyRepo.WithTx(tx)
+		if sale.PointsRedeemed > 0 {
+			if _, err := loyaltyRepo.Redeem(ctx, *sale.CustomerID, sale.PointsRedeemed, &sale.ID); err != nil {
+				return err
+			}
+		}
+
+		if sale.CustomerID != nil && sale.PointsEarned > 0 {
+			return loyaltyRepo.Earn(ctx, serv.loyaltyService.EarnEntry(*sale.CustomerID, sale.ID, sale.PointsEarned))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// buildSale menghitung total sale dari isi draft order:
+// subtotal - diskon (termasuk potongan dari poin), ditambah pajak, lalu dibandingkan dengan pembayaran.
+func (serv *DraftOrderService) buildSale(order *models.DraftOrder, req *models.CheckoutRequest, now time.Time) (*models.Sale, error) {
+	if len(order.Items) == 0 {
+		return nil, apperrors.Unprocessable("EMPTY_DRAFT_ORDER", "draft order has no items")
+	}
+
+	sale := &models.Sale{
+		InvoiceNumber:  fmt.Sprintf("INV-%s-%06d", now.Format("20060102"), order.ID),
+		CustomerID:     req.CustomerID,
+		PointsRedeemed: req.RedeemPoints,
+		Items:          make([]models.SaleItem, 0, len(order.Items)),
+		Payments:       req.Payments,
+	}
+
+	for _, item := range order.Items {
+		sale.Items = append(sale.Items, models.SaleItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+			Discount:    item.Discount,
+			Subtotal:    item.Subtotal,
+		})
+		sale.Subtotal += item.Subtotal
+	}
+
+	sale.DiscountTotal = req.Discount + serv.loyaltyService.RedeemAmount(req.RedeemPoints)
+	if sale.DiscountTotal > sale.Subtotal {
+		return nil, apperrors.Unprocessable("INVALID_DISCOUNT", "discount exceeds subtotal")
+	}
+
+	sale.TaxTotal = math.Round((sale.Subtotal - sale.DiscountTotal) * serv.taxRate)
+	sale.Total = sale.Subtotal - sale.DiscountTotal + sale.TaxTotal
+
+	for _, payment := range req.Payments {
+		sale.PaidAmount += payment.Amount
+	}
+
+	if sale.PaidAmount < sale.Total {
+		return nil, apperrors.Unprocessable("INSUFFICIENT_PAYMENT", "insufficient payment")
+	}
+	sale.ChangeAmount = sale.PaidAmount - sale.Total
+
+	if sale.CustomerID != nil {
+		sale.PointsEarned = serv.loyaltyService.PointsFor(sale.Subtotal - sale.DiscountTotal)
+	}
+
+	return sale, nil
+}
+
+func validateDraftOrderItem(item *models.DraftOrderItem) error {
+	if item.ProductID == 0 {
+		return apperrors.Validation("product ID is required")
+	}
+	if item.Quantity <= 0 {
+		return apperrors.Validation("quantity must be greater than 0")
+	}
+	if item.Discount < 0 {
+		return apperrors.Validation("discount must not be negative")
+	}
+	return nil
+}
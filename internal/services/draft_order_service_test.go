@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func openDraftOrder() *models.DraftOrder {
+	return &models.DraftOrder{
+		ID:     7,
+		Status: models.DraftOrderStatusOpen,
+		Items: []models.DraftOrderItem{
+			{ID: 1, ProductID: 1, ProductName: "Nasi Goreng", Quantity: 2, Price: 15000, Subtotal: 30000},
+			{ID: 2, ProductID: 2, ProductName: "Es Teh", Quantity: 1, Price: 5000, Discount: 1000, Subtotal: 4000},
+		},
+		Total: 34000,
+	}
+}
+
+func TestDraftOrderService_AddItem(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	item := &models.DraftOrderItem{ProductID: 1, Quantity: 2}
+	mockRepo.On("AddItem", mock.Anything, 7, item).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, 7).Return(openDraftOrder(), nil)
+
+	order, err := service.AddItem(context.Background(), 7, item)
+
+	assert.NoError(t, err)
+	assert.Len(t, order.Items, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDraftOrderService_AddItem_InvalidQuantity(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	_, err := service.AddItem(context.Background(), 7, &models.DraftOrderItem{ProductID: 1, Quantity: 0})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "AddItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderService_AddItem_InsufficientStock(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	item := &models.DraftOrderItem{ProductID: 1, Quantity: 100}
+	mockRepo.On("AddItem", mock.Anything, 7, item).Return(errors.New("insufficient stock"))
+
+	order, err := service.AddItem(context.Background(), 7, item)
+
+	assert.EqualError(t, err, "insufficient stock")
+	assert.Nil(t, order)
+}
+
+func TestDraftOrderService_Merge_Self(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	_, err := service.Merge(context.Background(), 7, 7)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderService_Split(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	lines := []models.SplitLine{{ItemID: 1, Quantity: 1}}
+	newOrder := &models.DraftOrder{ID: 8, Status: models.DraftOrderStatusOpen}
+	mockRepo.On("Split", mock.Anything, 7, lines).Return(8, nil)
+	mockRepo.On("GetByID", mock.Anything, 8).Return(newOrder, nil)
+
+	order, err := service.Split(context.Background(), 7, lines)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, order.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDraftOrderService_Checkout(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0.1)
+
+	req := &models.CheckoutRequest{
+		Discount: 4000,
+		Payments: []models.SalePayment{{Method: "cash", Amount: 50000}},
+	}
+	mockRepo.On("Checkout", mock.Anything, 7, mock.Anything).Return(openDraftOrder(), nil)
+
+	sale, err := service.Checkout(context.Background(), 7, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 34000.0, sale.Subtotal)
+	assert.Equal(t, 4000.0, sale.DiscountTotal)
+	assert.Equal(t, 3000.0, sale.TaxTotal)
+	assert.Equal(t, 33000.0, sale.Total)
+	assert.Equal(t, 17000.0, sale.ChangeAmount)
+	assert.Len(t, sale.Items, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDraftOrderService_Checkout_InsufficientPayment(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	req := &models.CheckoutRequest{
+		Payments: []models.SalePayment{{Method: "cash", Amount: 10000}},
+	}
+	mockRepo.On("Checkout", mock.Anything, 7, mock.Anything).Return(openDraftOrder(), nil)
+
+	sale, err := service.Checkout(context.Background(), 7, req)
+
+	assert.EqualError(t, err, "insufficient payment")
+	assert.Nil(t, sale)
+}
+
+func TestDraftOrderService_Checkout_NoPayment(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	_, err := service.Checkout(context.Background(), 7, &models.CheckoutRequest{})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Checkout", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderService_Checkout_WithLoyalty(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	mockLoyaltyRepo := new(mocks.LoyaltyRepositoryMock)
+	loyalty := NewLoyaltyService(mockLoyaltyRepo, LoyaltyConfig{EarnRate: 0.001, RedeemValue: 100})
+	service := NewDraftOrderService(mockRepo, mockLoyaltyRepo, loyalty, new(mocks.TxManagerMock), 0)
+
+	customerID := 3
+	req := &models.CheckoutRequest{
+		CustomerID:   &customerID,
+		RedeemPoints: 20,
+		Payments:     []models.SalePayment{{Method: "cash", Amount: 40000}},
+	}
+	redeemed := &models.LoyaltyEntry{ID: 1, CustomerID: customerID, Type: models.LoyaltyEntryRedeem, Points: -20}
+
+	mockRepo.On("Checkout", mock.Anything, 7, mock.Anything).Return(openDraftOrder(), nil)
+	mockLoyaltyRepo.On("Redeem", mock.Anything, customerID, 20, mock.MatchedBy(func(saleID *int) bool {
+		return saleID != nil
+	})).Return(redeemed, nil)
+	// 34.000 - 2.000 (20 poin x Rp100) = 32.000 -> 32 poin
+	mockLoyaltyRepo.On("Earn", mock.Anything, mock.MatchedBy(func(entry *models.LoyaltyEntry) bool {
+		return entry.CustomerID == customerID && entry.Points == 32
+	})).Return(nil)
+
+	sale, err := service.Checkout(context.Background(), 7, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2000.0, sale.DiscountTotal)
+	assert.Equal(t, 32000.0, sale.Total)
+	assert.Equal(t, 32, sale.PointsEarned)
+	assert.Equal(t, 20, sale.PointsRedeemed)
+	mockLoyaltyRepo.AssertExpectations(t)
+}
+
+func TestDraftOrderService_Checkout_InsufficientPointsFailsCheckout(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	mockLoyaltyRepo := new(mocks.LoyaltyRepositoryMock)
+	mockTx := new(mocks.TxManagerMock)
+	loyalty := NewLoyaltyService(mockLoyaltyRepo, LoyaltyConfig{EarnRate: 0.001, RedeemValue: 100})
+	service := NewDraftOrderService(mockRepo, mockLoyaltyRepo, loyalty, mockTx, 0)
+
+	customerID := 3
+	req := &models.CheckoutRequest{
+		CustomerID:   &customerID,
+		RedeemPoints: 20,
+		Payments:     []models.SalePayment{{Method: "cash", Amount: 40000}},
+	}
+
+	// sale yang sudah disimpan ikut di-rollback oleh WithinTx
+	mockRepo.On("Checkout", mock.Anything, 7, mock.Anything).Return(openDraftOrder(), nil)
+	mockLoyaltyRepo.On("Redeem", mock.Anything, customerID, 20, mock.Anything).Return(nil, repositories.ErrInsufficientPoints)
+
+	sale, err := service.Checkout(context.Background(), 7, req)
+
+	assert.ErrorIs(t, err, repositories.ErrInsufficientPoints)
+	assert.Nil(t, sale)
+	mockLoyaltyRepo.AssertExpectations(t)
+	mockLoyaltyRepo.AssertNotCalled(t, "Earn", mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderService_Checkout_FailureSkipsLoyalty(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	mockLoyaltyRepo := new(mocks.LoyaltyRepositoryMock)
+	loyalty := NewLoyaltyService(mockLoyaltyRepo, LoyaltyConfig{EarnRate: 0.001, RedeemValue: 100})
+	service := NewDraftOrderService(mockRepo, mockLoyaltyRepo, loyalty, new(mocks.TxManagerMock), 0)
+
+	customerID := 3
+	req := &models.CheckoutRequest{
+		CustomerID:   &customerID,
+		RedeemPoints: 20,
+		Payments:     []models.SalePayment{{Method: "cash", Amount: 40000}},
+	}
+
+	mockRepo.On("Checkout", mock.Anything, 7, mock.Anything).Return(nil, errors.New("insufficient stock"))
+
+	sale, err := service.Checkout(context.Background(), 7, req)
+
+	assert.EqualError(t, err, "insufficient stock")
+	assert.Nil(t, sale)
+	mockLoyaltyRepo.AssertNotCalled(t, "Redeem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockLoyaltyRepo.AssertNotCalled(t, "Earn", mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderService_Checkout_EarnFailureFailsCheckout(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	mockLoyaltyRepo := new(mocks.LoyaltyRepositoryMock)
+	loyalty := NewLoyaltyService(mockLoyaltyRepo, LoyaltyConfig{EarnRate: 0.001})
+	service := NewDraftOrderService(mockRepo, mockLoyaltyRepo, loyalty, new(mocks.TxManagerMock), 0)
+
+	customerID := 3
+	req := &models.CheckoutRequest{
+		CustomerID: &customerID,
+		Payments:   []models.SalePayment{{Method: "cash", Amount: 40000}},
+	}
+
+	// poin dicatat di transaksi checkout, kalau gagal sale ikut di-rollback
+	mockRepo.On("Checkout", mock.Anything, 7, mock.Anything).Return(openDraftOrder(), nil)
+	mockLoyaltyRepo.On("Earn", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+	sale, err := service.Checkout(context.Background(), 7, req)
+
+	assert.EqualError(t, err, "connection reset")
+	assert.Nil(t, sale)
+}
+
+func TestDraftOrderService_Checkout_RedeemWithoutCustomer(t *testing.T) {
+	mockRepo := new(mocks.DraftOrderRepositoryMock)
+	service := NewDraftOrderService(mockRepo, new(mocks.LoyaltyRepositoryMock), NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{}), new(mocks.TxManagerMock), 0)
+
+	req := &models.CheckoutRequest{
+		RedeemPoints: 10,
+		Payments:     []models.SalePayment{{Method: "cash", Amount: 40000}},
+	}
+
+	_, err := service.Checkout(context.Background(), 7, req)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Checkout", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDraftOrderService_BuildSale_EmptyOrder(t *testing.T) {
+	service := &DraftOrderService{loyaltyService: NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{})}
+	req := &models.CheckoutRequest{Payments: []models.SalePayment{{Method: "cash", Amount: 1000}}}
+
+	_, err := service.buildSale(&models.DraftOrder{ID: 1}, req, time.Now())
+
+	assert.EqualError(t, err, "draft order has no items")
+}
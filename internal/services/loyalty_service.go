@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"math"
+	"time"
+)
+
+// LoyaltyConfig mengatur nilai tukar poin member
+type LoyaltyConfig struct {
+	EarnRate    float64 // poin yang didapat per rupiah belanja, misalnya 0.0001 = 1 poin per Rp10.000
+	RedeemValue float64 // potongan rupiah per poin yang ditukar
+	ExpiryDays  int     // masa berlaku poin, 0 berarti tidak kedaluwarsa
+}
+
+type LoyaltyServiceInterface interface {
+	GetAccount(ctx context.Context, customerID int) (*models.LoyaltyAccount, error)
+	PointsFor(amount float64) int
+	RedeemAmount(points int) float64
+	EarnEntry(customerID, saleID, points int) *models.LoyaltyEntry
+}
+
+type LoyaltyService struct {
+	loyaltyRepo repositories.LoyaltyRepositoryInterface
+	config      LoyaltyConfig
+}
+
+func NewLoyaltyService(loyaltyRepo repositories.LoyaltyRepositoryInterface, config LoyaltyConfig) LoyaltyServiceInterface {
+	return &LoyaltyService{
+		loyaltyRepo: loyaltyRepo,
+		config:      config,
+	}
+}
+
+func (serv *LoyaltyService) GetAccount(ctx context.Context, customerID int) (*models.LoyaltyAccount, error) {
+	ctx, span := tracing.Start(ctx, "LoyaltyService.GetAccount")
+	defer span.End()
+
+	// catat dulu poin yang sudah kedaluwarsa supaya ledger sesuai dengan saldo
+	if err := serv.loyaltyRepo.Expire(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	balance, err := serv.loyaltyRepo.Balance(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := serv.loyaltyRepo.GetLedger(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoyaltyAccount{
+		CustomerID:  customerID,
+		Balance:     balance,
+		RedeemValue: serv.config.RedeemValue,
+		Entries:     entries,
+	}, nil
+}
+
+// PointsFor menghitung poin yang didapat dari nominal belanja (dibulatkan ke bawah)
+func (serv *LoyaltyService) PointsFor(amount float64) int {
+	if amount <= 0 || serv.config.EarnRate <= 0 {
+		return 0
+	}
+	return int(math.Floor(amount * serv.config.EarnRate))
+}
+
+// RedeemAmount menghitung potongan rupiah dari jumlah poin yang ditukar
+func (serv *LoyaltyService) RedeemAmount(points int) float64 {
+	return float64(points) * serv.config.RedeemValue
+}
+
+// EarnEntry menyusun entry earn untuk poin dari sebuah sale, lengkap dengan masa berlakunya.
+// disimpan oleh pemanggil lewat LoyaltyRepository.Earn, biasanya di dalam transaksi checkout
+func (serv *LoyaltyService) EarnEntry(customerID, saleID, points int) *models.LoyaltyEntry {
+	return &models.LoyaltyEntry{
+		CustomerID: customerID,
+		SaleID:     &saleID,
+		Points:     points,
+		ExpiresAt:  serv.expiresAt(),
+	}
+}
+
+func (serv *LoyaltyService) expiresAt() *time.Time {
+	if serv.config.ExpiryDays <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().AddDate(0, 0, serv.config.ExpiryDays)
+	return &expiresAt
+}
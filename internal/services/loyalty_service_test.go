@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyService_PointsFor(t *testing.T) {
+	service := NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{EarnRate: 0.0001})
+
+	assert.Equal(t, 0, service.PointsFor(9999))
+	assert.Equal(t, 1, service.PointsFor(10000))
+	assert.Equal(t, 12, service.PointsFor(125000))
+	assert.Equal(t, 0, service.PointsFor(-5000))
+}
+
+func TestLoyaltyService_EarnEntry_SetsExpiry(t *testing.T) {
+	service := NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{ExpiryDays: 365})
+
+	entry := service.EarnEntry(1, 10, 5)
+
+	assert.Equal(t, 1, entry.CustomerID)
+	assert.Equal(t, 10, *entry.SaleID)
+	assert.Equal(t, 5, entry.Points)
+	require.NotNil(t, entry.ExpiresAt)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 365), *entry.ExpiresAt, time.Minute)
+
+	// tanpa masa berlaku poin tidak kedaluwarsa
+	service = NewLoyaltyService(new(mocks.LoyaltyRepositoryMock), LoyaltyConfig{})
+	assert.Nil(t, service.EarnEntry(1, 10, 5).ExpiresAt)
+}
+
+func TestLoyaltyService_GetAccount(t *testing.T) {
+	mockRepo := new(mocks.LoyaltyRepositoryMock)
+	service := NewLoyaltyService(mockRepo, LoyaltyConfig{RedeemValue: 100})
+
+	entries := []models.LoyaltyEntry{{ID: 1, CustomerID: 1, Type: models.LoyaltyEntryEarn, Points: 50, Remaining: 50}}
+	mockRepo.On("Expire", mock.Anything, 1).Return(nil)
+	mockRepo.On("Balance", mock.Anything, 1).Return(50, nil)
+	mockRepo.On("GetLedger", mock.Anything, 1).Return(entries, nil)
+
+	account, err := service.GetAccount(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 50, account.Balance)
+	assert.Equal(t, 100.0, account.RedeemValue)
+	assert.Len(t, account.Entries, 1)
+	mockRepo.AssertExpectations(t)
+}
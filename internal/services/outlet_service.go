@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"strings"
+)
+
+type OutletServiceInterface interface {
+	GetAll(ctx context.Context) ([]models.Outlet, error)
+	GetByID(ctx context.Context, id int) (*models.Outlet, error)
+	Create(ctx context.Context, outlet *models.Outlet) error
+	Update(ctx context.Context, id int, outlet *models.Outlet) (*models.Outlet, error)
+	GetStock(ctx context.Context, outletID int) ([]models.OutletStock, error)
+	GetConsolidatedStock(ctx context.Context) ([]models.ConsolidatedStock, error)
+	SetPrice(ctx context.Context, outletID, productID int, price *float64) error
+}
+
+type OutletService struct {
+	outletRepo repositories.OutletRepositoryInterface
+}
+
+func NewOutletService(outletRepo repositories.OutletRepositoryInterface) OutletServiceInterface {
+	return &OutletService{
+		outletRepo: outletRepo,
+	}
+}
+
+func (serv *OutletService) GetAll(ctx context.Context) ([]models.Outlet, error) {
+	ctx, span := tracing.Start(ctx, "OutletService.GetAll")
+	defer span.End()
+
+	return serv.outletRepo.GetAll(ctx)
+}
+
+func (serv *OutletService) GetByID(ctx context.Context, id int) (*models.Outlet, error) {
+	ctx, span := tracing.Start(ctx, "OutletService.GetByID")
+	defer span.End()
+
+	return serv.outletRepo.GetByID(ctx, id)
+}
+
+func (serv *OutletService) Create(ctx context.Context, outlet *models.Outlet) error {
+	ctx, span := tracing.Start(ctx, "OutletService.Create")
+	defer span.End()
+
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+
+	return serv.outletRepo.Create(ctx, outlet)
+}
+
+func (serv *OutletService) Update(ctx context.Context, id int, outlet *models.Outlet) (*models.Outlet, error) {
+	ctx, span := tracing.Start(ctx, "OutletService.Update")
+	defer span.End()
+
+	if err := validateOutlet(outlet); err != nil {
+		return nil, err
+	}
+
+	if err := serv.outletRepo.Update(ctx, id, outlet); err != nil {
+		return nil, err
+	}
+
+	return serv.outletRepo.GetByID(ctx, id)
+}
+
+func (serv *OutletService) GetStock(ctx context.Context, outletID int) ([]models.OutletStock, error) {
+	ctx, span := tracing.Start(ctx, "OutletService.GetStock")
+	defer span.End()
+
+	if _, err := serv.outletRepo.GetByID(ctx, outletID); err != nil {
+		return nil, err
+	}
+
+	return serv.outletRepo.GetStock(ctx, outletID)
+}
+
+func (serv *OutletService) GetConsolidatedStock(ctx context.Context) ([]models.ConsolidatedStock, error) {
+	ctx, span := tracing.Start(ctx, "OutletService.GetConsolidatedStock")
+	defer span.End()
+
+	return serv.outletRepo.GetConsolidatedStock(ctx)
+}
+
+func (serv *OutletService) SetPrice(ctx context.Context, outletID, productID int, price *float64) error {
+	ctx, span := tracing.Start(ctx, "OutletService.SetPrice")
+	defer span.End()
+
+	if price != nil && *price < 0 {
+		return apperrors.Validation("price must not be negative")
+	}
+
+	if _, err := serv.outletRepo.GetByID(ctx, outletID); err != nil {
+		return err
+	}
+
+	return serv.outletRepo.SetPrice(ctx, outletID, productID, price)
+}
+
+func validateOutlet(outlet *models.Outlet) error {
+	outlet.Code = strings.ToUpper(strings.TrimSpace(outlet.Code))
+	if outlet.Code == "" {
+		return apperrors.Validation("outlet code is required")
+	}
+
+	outlet.Name = strings.TrimSpace(outlet.Name)
+	if outlet.Name == "" {
+		return apperrors.Validation("outlet name is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/imaging"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/storage"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+var (
+	ErrImageTooLarge = apperrors.New(apperrors.ErrTooLarge, "FILE_TOO_LARGE", "image file is too large")
+	ErrImageEmpty    = apperrors.Validation("image file is empty")
+)
+
+// ImageConfig mengatur batas upload dan ukuran thumbnail (sisi terpanjang, pixel)
+type ImageConfig struct {
+	MaxBytes       int64
+	ThumbnailSizes []int
+}
+
+type ProductImageServiceInterface interface {
+	Upload(ctx context.Context, productID int, file io.Reader) (*models.ProductImage, error)
+	Delete(ctx context.Context, productID, imageID int) error
+}
+
+type ProductImageService struct {
+	productRepo repositories.ProductRepositoryInterface
+	imageRepo   repositories.ProductImageRepositoryInterface
+	store       storage.BlobStore
+	config      ImageConfig
+}
+
+func NewProductImageService(
+	productRepo repositories.ProductRepositoryInterface,
+	imageRepo repositories.ProductImageRepositoryInterface,
+	store storage.BlobStore,
+	config ImageConfig,
+) ProductImageServiceInterface {
+	return &ProductImageService{
+		productRepo: productRepo,
+		imageRepo:   imageRepo,
+		store:       store,
+		config:      config,
+	}
+}
+
+// Upload memvalidasi gambar, menyimpan file asli beserta thumbnail di BlobStore,
+// lalu mencatatnya di database. kalau salah satu langkah gagal, file yang sudah
+// terlanjur tersimpan dihapus lagi.
+func (serv *ProductImageService) Upload(ctx context.Context, productID int, file io.Reader) (*models.ProductImage, error) {
+	ctx, span := tracing.Start(ctx, "ProductImageService.Upload")
+	defer span.End()
+
+	if _, err := serv.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, serv.config.MaxBytes+1))
+	if err != nil {
+		// body multipart melewati batas http.MaxBytesReader dari handler
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, ErrImageTooLarge
+		}
+		return nil, err
+	}
+	if int64(len(data)) > serv.config.MaxBytes {
+		return nil, ErrImageTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrImageEmpty
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := newImagePrefix(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.ProductImage{
+		ProductID:     productID,
+		Key:           prefix + "/original." + img.Ext,
+		ContentType:   img.ContentType,
+		Width:         img.Bounds().Dx(),
+		Height:        img.Bounds().Dy(),
+		SizeBytes:     len(data),
+		ThumbnailKeys: make(map[string]string),
+	}
+
+	stored := make([]string, 0, len(serv.config.ThumbnailSizes)+1)
+	cleanup := func() {
+		serv.deleteBlobs(context.WithoutCancel(ctx), stored)
+	}
+
+	if err := serv.store.Put(ctx, image.Key, data, image.ContentType); err != nil {
+		return nil, err
+	}
+	stored = append(stored, image.Key)
+
+	for _, size := range serv.config.ThumbnailSizes {
+		thumb, contentType, ext, err := imaging.Encode(imaging.Thumbnail(img, size), img.ContentType)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s/%d.%s", prefix, size, ext)
+		if err := serv.store.Put(ctx, key, thumb, contentType); err != nil {
+			cleanup()
+			return nil, err
+		}
+		stored = append(stored, key)
+		image.ThumbnailKeys[strconv.Itoa(size)] = key
+	}
+
+	if err := serv.imageRepo.Create(ctx, image); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	resolveImageURLs(serv.store, image)
+	return image, nil
+}
+
+func (serv *ProductImageService) Delete(ctx context.Context, productID, imageID int) error {
+	ctx, span := tracing.Start(ctx, "ProductImageService.Delete")
+	defer span.End()
+
+	image, err := serv.imageRepo.GetByID(ctx, productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := serv.imageRepo.Delete(ctx, productID, imageID); err != nil {
+		return err
+	}
+
+	// baris sudah terhapus, file yang gagal dihapus hanya jadi sampah di storage
+	keys := []string{image.Key}
+	for _, key := range image.ThumbnailKeys {
+		keys = append(keys, key)
+	}
+	serv.deleteBlobs(ctx, keys)
+
+	return nil
+}
+
+func (serv *ProductImageService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := serv.store.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "failed to delete blob", "key", key, "error", err)
+		}
+	}
+}
+
+// newImagePrefix membuat folder unik per upload, misalnya products/12/9f86d081884c7d65
+func newImagePrefix(productID int) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s", productID, hex.EncodeToString(b)), nil
+}
+
+// resolveImageURLs mengisi URL publik dari key di storage
+func resolveImageURLs(store storage.BlobStore, image *models.ProductImage) {
+	image.URL = store.URL(image.Key)
+	image.Thumbnails = make(map[string]string, len(image.ThumbnailKeys))
+	for size, key := range image.ThumbnailKeys {
+		image.Thumbnails[size] = store.URL(key)
+	}
+}
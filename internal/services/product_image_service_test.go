@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/imaging"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestImageService() (ProductImageServiceInterface, *mocks.ProductRepositoryMock, *mocks.ProductImageRepositoryMock, *mocks.BlobStoreMock) {
+	productRepo := new(mocks.ProductRepositoryMock)
+	imageRepo := new(mocks.ProductImageRepositoryMock)
+	store := new(mocks.BlobStoreMock)
+	service := NewProductImageService(productRepo, imageRepo, store, ImageConfig{
+		MaxBytes:       1 << 20,
+		ThumbnailSizes: []int{64, 128},
+	})
+	return service, productRepo, imageRepo, store
+}
+
+func TestProductImageService_Upload(t *testing.T) {
+	service, productRepo, imageRepo, store := newTestImageService()
+
+	productRepo.On("GetByID", mock.Anything, 1).Return(&models.ProductResponse{ID: 1}, nil)
+	store.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "products/1/")
+	}), mock.Anything, "image/png").Return(nil)
+	imageRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.ProductImage")).Return(nil)
+
+	image, err := service.Upload(context.Background(), 1, bytes.NewReader(testPNG(t, 300, 150)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", image.ContentType)
+	assert.Equal(t, 300, image.Width)
+	assert.True(t, strings.HasSuffix(image.URL, "/original.png"))
+	assert.True(t, strings.HasSuffix(image.Thumbnails["64"], "/64.png"))
+	assert.True(t, strings.HasSuffix(image.Thumbnails["128"], "/128.png"))
+	store.AssertNumberOfCalls(t, "Put", 3)
+}
+
+func TestProductImageService_Upload_TooLarge(t *testing.T) {
+	service, productRepo, _, store := newTestImageService()
+
+	productRepo.On("GetByID", mock.Anything, 1).Return(&models.ProductResponse{ID: 1}, nil)
+
+	_, err := service.Upload(context.Background(), 1, bytes.NewReader(make([]byte, 2<<20)))
+
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+	store.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductImageService_Upload_BodyLimit(t *testing.T) {
+	service, productRepo, _, _ := newTestImageService()
+
+	productRepo.On("GetByID", mock.Anything, 1).Return(&models.ProductResponse{ID: 1}, nil)
+
+	// batas body dari handler terlampaui sebelum file selesai dibaca
+	body := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(bytes.NewReader(make([]byte, 1024))), 512)
+	_, err := service.Upload(context.Background(), 1, body)
+
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+	assert.ErrorIs(t, err, apperrors.ErrTooLarge)
+}
+
+func TestProductImageService_Upload_Unsupported(t *testing.T) {
+	service, productRepo, _, _ := newTestImageService()
+
+	productRepo.On("GetByID", mock.Anything, 1).Return(&models.ProductResponse{ID: 1}, nil)
+
+	_, err := service.Upload(context.Background(), 1, strings.NewReader("%PDF-1.4 not an image"))
+
+	assert.ErrorIs(t, err, imaging.ErrUnsupportedType)
+}
+
+func TestProductImageService_Upload_CleansUpOnFailure(t *testing.T) {
+	service, productRepo, imageRepo, store := newTestImageService()
+
+	productRepo.On("GetByID", mock.Anything, 1).Return(&models.ProductResponse{ID: 1}, nil)
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	imageRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	_, err := service.Upload(context.Background(), 1, bytes.NewReader(testPNG(t, 100, 100)))
+
+	assert.Error(t, err)
+	store.AssertNumberOfCalls(t, "Delete", 3)
+}
+
+func TestProductImageService_Delete(t *testing.T) {
+	service, _, imageRepo, store := newTestImageService()
+
+	imageRepo.On("GetByID", mock.Anything, 1, 3).Return(&models.ProductImage{
+		ID:            3,
+		ProductID:     1,
+		Key:           "products/1/ab/original.jpg",
+		ThumbnailKeys: map[string]string{"64": "products/1/ab/64.jpg"},
+	}, nil)
+	imageRepo.On("Delete", mock.Anything, 1, 3).Return(nil)
+	store.On("Delete", mock.Anything, "products/1/ab/original.jpg").Return(nil)
+	store.On("Delete", mock.Anything, "products/1/ab/64.jpg").Return(nil)
+
+	err := service.Delete(context.Background(), 1, 3)
+
+	assert.NoError(t, err)
+	store.AssertExpectations(t)
+}
@@ -2,7 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
 	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/storage"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/internal/validation"
 	"fajar7xx/go-kasir-umam-ds/models"
 )
 
@@ -13,7 +18,10 @@ type ProductServiceInterface interface {
 	GetByID(ctx context.Context, id int) (*models.ProductResponse, error)
 	Create(ctx context.Context, product *models.Product) (*models.ProductResponse, error)
 	Update(ctx context.Context, id int, product *models.Product) (*models.ProductResponse, error)
-	Delete(ctx context.Context, id int) error
+	Patch(ctx context.Context, id int, patch models.ProductPatch) (*models.ProductResponse, error)
+	Delete(ctx context.Context, id int, version int) error
+	// Validate dipakai juga oleh endpoint import/bulk supaya aturannya sama
+	Validate(ctx context.Context, product *models.Product) (validation.Errors, error)
 }
 
 // 2. Struct Implementasi (Concrete)
@@ -21,7 +29,10 @@ type ProductService struct {
 	// productRepo *repositories.ProductRepository
 	// BEST PRACTICE: Gunakan Interface, bukan struct konkret (*ProductRepository).
 	// Ini memungkinkan kita mengganti repo dengan Mock saat Unit Testing.
-	productRepo repositories.ProductRepositoryInterface
+	productRepo  repositories.ProductRepositoryInterface
+	categoryRepo repositories.CategoryRepositoryInterface
+	imageRepo    repositories.ProductImageRepositoryInterface
+	store        storage.BlobStore
 }
 
 // 3. Constructor
@@ -32,38 +43,198 @@ type ProductService struct {
 //			productRepo: productRepo,
 //		}
 //	}
-func NewProductService(productRepo repositories.ProductRepositoryInterface) ProductServiceInterface {
+func NewProductService(
+	productRepo repositories.ProductRepositoryInterface,
+	categoryRepo repositories.CategoryRepositoryInterface,
+	imageRepo repositories.ProductImageRepositoryInterface,
+	store storage.BlobStore,
+) ProductServiceInterface {
 	return &ProductService{
-		productRepo: productRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		imageRepo:    imageRepo,
+		store:        store,
 	}
 }
 
 func (serv *ProductService) GetAll(ctx context.Context) ([]models.ProductResponse, error) {
-	return serv.productRepo.GetAll(ctx)
+	ctx, span := tracing.Start(ctx, "ProductService.GetAll")
+	defer span.End()
+
+	products, err := serv.productRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := serv.attachImages(ctx, products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
 }
 
 func (serv *ProductService) GetByID(ctx context.Context, id int) (*models.ProductResponse, error) {
-	return serv.productRepo.GetByID(ctx, id)
+	ctx, span := tracing.Start(ctx, "ProductService.GetByID")
+	defer span.End()
+
+	product, err := serv.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []models.ProductResponse{*product}
+	if err := serv.attachImages(ctx, products); err != nil {
+		return nil, err
+	}
+
+	return &products[0], nil
 }
 
 func (serv *ProductService) Create(ctx context.Context, product *models.Product) (*models.ProductResponse, error) {
-	return serv.productRepo.Create(ctx, product)
+	ctx, span := tracing.Start(ctx, "ProductService.Create")
+	defer span.End()
+
+	if err := serv.validate(ctx, product); err != nil {
+		return nil, err
+	}
+
+	created, err := serv.productRepo.Create(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	// produk baru belum punya gambar
+	created.Images = make([]models.ProductImage, 0)
+	return created, nil
 }
 
 func (serv *ProductService) Update(ctx context.Context, id int, product *models.Product) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Update")
+	defer span.End()
+
+	if err := serv.validate(ctx, product); err != nil {
+		return nil, err
+	}
+
 	err := serv.productRepo.Update(ctx, id, product)
 	if err != nil {
 		return nil, err
 	}
 
-	updatedProduct, err := serv.productRepo.GetByID(ctx, id)
+	return serv.GetByID(ctx, id)
+}
+
+// Patch menerapkan JSON merge patch: hanya field yang dikirim yang divalidasi dan disimpan
+func (serv *ProductService) Patch(ctx context.Context, id int, patch models.ProductPatch) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Patch")
+	defer span.End()
+
+	current, err := serv.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return updatedProduct, nil
+	product := &models.Product{
+		ID:          current.ID,
+		Name:        current.Name,
+		Description: current.Description,
+		Price:       current.BasePrice,
+		Stock:       current.Stock,
+		CategoryID:  current.CategoryID,
+	}
+	patch.Apply(product)
+
+	errs, err := serv.Validate(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	// field yang tidak dikirim tidak ikut dinilai, misalnya stok 0 di outlet ini tidak
+	// boleh menggagalkan patch yang hanya mengubah harga
+	sent := map[string]bool{
+		"name":        patch.Name.Set,
+		"description": patch.Description.Set,
+		"price":       patch.Price.Set,
+		"stock":       patch.Stock.Set,
+		"category_id": patch.CategoryID.Set,
+	}
+	if err := onlySent(errs, sent).Err(); err != nil {
+		return nil, err
+	}
+
+	if err := serv.productRepo.Patch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+
+	return serv.GetByID(ctx, id)
+}
+
+func (serv *ProductService) Delete(ctx context.Context, id int, version int) error {
+	ctx, span := tracing.Start(ctx, "ProductService.Delete")
+	defer span.End()
+
+	return serv.productRepo.Delete(ctx, id, version)
 }
 
-func (serv *ProductService) Delete(ctx context.Context, id int) error {
-	return serv.productRepo.Delete(ctx, id)
+// Validate memeriksa tag validate di models.Product lalu memastikan category_id benar-benar ada.
+// error kedua hanya terisi kalau pengecekan itu sendiri gagal (misalnya database mati).
+func (serv *ProductService) Validate(ctx context.Context, product *models.Product) (validation.Errors, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Validate")
+	defer span.End()
+
+	errs := validation.Struct(product)
+
+	if product.CategoryID != 0 {
+		_, err := serv.categoryRepo.GetByID(ctx, product.CategoryID)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			errs.Add("category_id", "category not found")
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return errs, nil
+}
+
+// validate menggabungkan hasil Validate menjadi satu error untuk Create dan Update
+func (serv *ProductService) validate(ctx context.Context, product *models.Product) error {
+	errs, err := serv.Validate(ctx, product)
+	if err != nil {
+		return err
+	}
+	return errs.Err()
+}
+
+// onlySent membuang error untuk field yang tidak ada di body PATCH
+func onlySent(errs validation.Errors, sent map[string]bool) validation.Errors {
+	var filtered validation.Errors
+	for _, fieldErr := range errs {
+		if sent[fieldErr.Field] {
+			filtered = append(filtered, fieldErr)
+		}
+	}
+	return filtered
+}
+
+// attachImages mengisi Images setiap produk (termasuk URL publiknya) dengan satu query
+func (serv *ProductService) attachImages(ctx context.Context, products []models.ProductResponse) error {
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	images, err := serv.imageRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Images = make([]models.ProductImage, 0, len(images[products[i].ID]))
+		for _, image := range images[products[i].ID] {
+			resolveImageURLs(serv.store, &image)
+			products[i].Images = append(products[i].Images, image)
+		}
+	}
+
+	return nil
 }
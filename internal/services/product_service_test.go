@@ -1,28 +1,49 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
 	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/validation"
 	"fajar7xx/go-kasir-umam-ds/models"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+// newTestProductService memakai image repository tanpa gambar apa pun
+func newTestProductService(productRepo *mocks.ProductRepositoryMock) ProductServiceInterface {
+	categoryRepo := new(mocks.CategoryRepositoryMock)
+	categoryRepo.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Food"}, nil).Maybe()
+	imageRepo := new(mocks.ProductImageRepositoryMock)
+	imageRepo.On("GetByProductIDs", mock.Anything, mock.Anything).Return(map[int][]models.ProductImage{}, nil).Maybe()
+	return NewProductService(productRepo, categoryRepo, imageRepo, new(mocks.BlobStoreMock))
+}
+
+// validProduct membuat produk yang lolos validasi dengan kategori 1
+func validProduct(name string) *models.Product {
+	return &models.Product{Name: name, Price: 15000, Stock: 10, CategoryID: 1}
+}
+
 func TestProductService_GetAll(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
 	now := time.Now()
-	expectedProducts := []models.Product{
+	expectedProducts := []models.ProductResponse{
 		{ID: 1, Name: "Nasi Goreng", CreatedAt: now},
 		{ID: 2, Name: "Es Teh", CreatedAt: now},
 	}
 
-	mockRepo.On("GetAll").Return(expectedProducts, nil)
+	mockRepo.On("GetAll", mock.Anything).Return(expectedProducts, nil)
 
-	products, err := service.GetAll()
+	products, err := service.GetAll(context.Background())
 
 	assert.NoError(t, err)
 	assert.Len(t, products, 2)
@@ -32,11 +53,11 @@ func TestProductService_GetAll(t *testing.T) {
 
 func TestProductService_GetAll_Error(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
-	mockRepo.On("GetAll").Return(nil, errors.New("database error"))
+	mockRepo.On("GetAll", mock.Anything).Return(nil, errors.New("database error"))
 
-	products, err := service.GetAll()
+	products, err := service.GetAll(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, products)
@@ -45,14 +66,14 @@ func TestProductService_GetAll_Error(t *testing.T) {
 
 func TestProductService_GetByID(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
 	now := time.Now()
-	expectedProduct := &models.Product{ID: 1, Name: "Nasi Goreng", CreatedAt: now}
+	expectedProduct := &models.ProductResponse{ID: 1, Name: "Nasi Goreng", CreatedAt: now}
 
-	mockRepo.On("GetByID", 1).Return(expectedProduct, nil)
+	mockRepo.On("GetByID", mock.Anything, 1).Return(expectedProduct, nil)
 
-	product, err := service.GetByID(1)
+	product, err := service.GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, product.ID)
@@ -62,32 +83,82 @@ func TestProductService_GetByID(t *testing.T) {
 
 func TestProductService_Create(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
-	product := &models.Product{Name: "Nasi Goreng"}
+	product := validProduct("Nasi Goreng")
+	createdProduct := &models.ProductResponse{ID: 1, Name: "Nasi Goreng"}
 
-	mockRepo.On("Create", product).Return(nil)
+	mockRepo.On("Create", mock.Anything, product).Return(createdProduct, nil)
 
-	err := service.Create(product)
+	result, err := service.Create(context.Background(), product)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProductService_Create_ReportsAllInvalidFields(t *testing.T) {
+	mockRepo := new(mocks.ProductRepositoryMock)
+	service := newTestProductService(mockRepo)
+
+	product := &models.Product{Name: strings.Repeat("a", 256), Price: -1, CategoryID: 1}
+
+	result, err := service.Create(context.Background(), product)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	appErr, _ := apperrors.As(err)
+	assert.Equal(t, []validation.FieldError{
+		{Field: "name", Message: "must be at most 255 characters"},
+		{Field: "price", Message: "must be greater than 0"},
+		{Field: "stock", Message: "must be greater than 0"},
+	}, appErr.Details)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestProductService_Create_CategoryNotFound(t *testing.T) {
+	mockRepo := new(mocks.ProductRepositoryMock)
+	categoryRepo := new(mocks.CategoryRepositoryMock)
+	service := NewProductService(mockRepo, categoryRepo, new(mocks.ProductImageRepositoryMock), new(mocks.BlobStoreMock))
+
+	categoryRepo.On("GetByID", mock.Anything, 9).Return(nil, repositories.ErrCategoryNotFound)
+
+	product := validProduct("Nasi Goreng")
+	product.CategoryID = 9
+
+	errs, err := service.Validate(context.Background(), product)
+
+	assert.NoError(t, err)
+	assert.Equal(t, validation.Errors{{Field: "category_id", Message: "category not found"}}, errs)
+}
+
+func TestProductService_Validate_CategoryLookupFails(t *testing.T) {
+	mockRepo := new(mocks.ProductRepositoryMock)
+	categoryRepo := new(mocks.CategoryRepositoryMock)
+	service := NewProductService(mockRepo, categoryRepo, new(mocks.ProductImageRepositoryMock), new(mocks.BlobStoreMock))
+
+	categoryRepo.On("GetByID", mock.Anything, 1).Return(nil, errors.New("connection refused"))
+
+	_, err := service.Validate(context.Background(), validProduct("Nasi Goreng"))
+
+	assert.EqualError(t, err, "connection refused")
+}
+
 func TestProductService_Update(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
 	id := 1
-	product := &models.Product{Name: "Nasi Goreng Updated"}
-	updatedProduct := &models.Product{ID: 1, Name: "Nasi Goreng Updated"}
+	product := validProduct("Nasi Goreng Updated")
+	updatedProduct := &models.ProductResponse{ID: 1, Name: "Nasi Goreng Updated"}
 
 	// Expect Update to be called
-	mockRepo.On("Update", id, product).Return(nil)
+	mockRepo.On("Update", mock.Anything, id, product).Return(nil)
 	// Expect GetByID to be called after Update
-	mockRepo.On("GetByID", id).Return(updatedProduct, nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(updatedProduct, nil)
 
-	result, err := service.Update(id, product)
+	result, err := service.Update(context.Background(), id, product)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Nasi Goreng Updated", result.Name)
@@ -96,14 +167,14 @@ func TestProductService_Update(t *testing.T) {
 
 func TestProductService_Update_Error(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
 	id := 1
-	product := &models.Product{Name: "Nasi Goreng Updated"}
+	product := validProduct("Nasi Goreng Updated")
 
-	mockRepo.On("Update", id, product).Return(errors.New("update failed"))
+	mockRepo.On("Update", mock.Anything, id, product).Return(errors.New("update failed"))
 
-	result, err := service.Update(id, product)
+	result, err := service.Update(context.Background(), id, product)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -112,13 +183,77 @@ func TestProductService_Update_Error(t *testing.T) {
 
 func TestProductService_Delete(t *testing.T) {
 	mockRepo := new(mocks.ProductRepositoryMock)
-	service := NewProductService(mockRepo)
+	service := newTestProductService(mockRepo)
 
 	id := 1
-	mockRepo.On("Delete", id).Return(nil)
+	mockRepo.On("Delete", mock.Anything, id, 2).Return(nil)
 
-	err := service.Delete(id)
+	err := service.Delete(context.Background(), id, 2)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestProductService_GetByID_WithImages(t *testing.T) {
+	mockRepo := new(mocks.ProductRepositoryMock)
+	imageRepo := new(mocks.ProductImageRepositoryMock)
+	service := NewProductService(mockRepo, new(mocks.CategoryRepositoryMock), imageRepo, new(mocks.BlobStoreMock))
+
+	mockRepo.On("GetByID", mock.Anything, 1).Return(&models.ProductResponse{ID: 1, Name: "Nasi Goreng"}, nil)
+	imageRepo.On("GetByProductIDs", mock.Anything, []int{1}).Return(map[int][]models.ProductImage{
+		1: {{
+			ID:            3,
+			ProductID:     1,
+			Key:           "products/1/ab/original.jpg",
+			ThumbnailKeys: map[string]string{"128": "products/1/ab/128.jpg"},
+		}},
+	}, nil)
+
+	product, err := service.GetByID(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, product.Images, 1)
+	assert.Equal(t, "/media/products/1/ab/original.jpg", product.Images[0].URL)
+	assert.Equal(t, "/media/products/1/ab/128.jpg", product.Images[0].Thumbnails["128"])
+}
+
+func TestProductService_Patch_OnlyValidatesSentFields(t *testing.T) {
+	mockRepo := new(mocks.ProductRepositoryMock)
+	service := newTestProductService(mockRepo)
+
+	// stok 0 di outlet ini tidak boleh menggagalkan patch harga
+	current := &models.ProductResponse{ID: 1, Name: "Nasi Goreng", Price: 15000, BasePrice: 15000, Stock: 0, CategoryID: 1}
+	patched := &models.ProductResponse{ID: 1, Name: "Nasi Goreng", Price: 12000, BasePrice: 12000, Stock: 0, CategoryID: 1}
+
+	var patch models.ProductPatch
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": 12000}`), &patch))
+
+	mockRepo.On("GetByID", mock.Anything, 1).Return(current, nil).Once()
+	mockRepo.On("Patch", mock.Anything, 1, patch).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, 1).Return(patched, nil).Once()
+
+	result, err := service.Patch(context.Background(), 1, patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12000.0, result.Price)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProductService_Patch_NullRequiredField(t *testing.T) {
+	mockRepo := new(mocks.ProductRepositoryMock)
+	service := newTestProductService(mockRepo)
+
+	current := &models.ProductResponse{ID: 1, Name: "Nasi Goreng", Price: 15000, BasePrice: 15000, Stock: 3, CategoryID: 1}
+	mockRepo.On("GetByID", mock.Anything, 1).Return(current, nil)
+
+	var patch models.ProductPatch
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": null, "description": null}`), &patch))
+
+	result, err := service.Patch(context.Background(), 1, patch)
+
+	assert.Nil(t, result)
+	appErr, ok := apperrors.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, []validation.FieldError{{Field: "name", Message: "is required"}}, appErr.Details)
+	mockRepo.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
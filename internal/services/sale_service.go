@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+type SaleServiceInterface interface {
+	GetByID(ctx context.Context, id int) (*models.Sale, error)
+}
+
+type SaleService struct {
+	saleRepo repositories.SaleRepositoryInterface
+}
+
+func NewSaleService(saleRepo repositories.SaleRepositoryInterface) SaleServiceInterface {
+	return &SaleService{
+		saleRepo: saleRepo,
+	}
+}
+
+func (serv *SaleService) GetByID(ctx context.Context, id int) (*models.Sale, error) {
+	ctx, span := tracing.Start(ctx, "SaleService.GetByID")
+	defer span.End()
+
+	return serv.saleRepo.GetByID(ctx, id)
+}
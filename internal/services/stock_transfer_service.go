@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"fajar7xx/go-kasir-umam-ds/models"
+)
+
+type StockTransferServiceInterface interface {
+	GetAll(ctx context.Context, status string) ([]models.StockTransfer, error)
+	GetByID(ctx context.Context, id int) (*models.StockTransfer, error)
+	Create(ctx context.Context, transfer *models.StockTransfer) (*models.StockTransfer, error)
+	Receive(ctx context.Context, id int) (*models.StockTransfer, error)
+	Cancel(ctx context.Context, id int) (*models.StockTransfer, error)
+}
+
+type StockTransferService struct {
+	transferRepo repositories.StockTransferRepositoryInterface
+}
+
+func NewStockTransferService(transferRepo repositories.StockTransferRepositoryInterface) StockTransferServiceInterface {
+	return &StockTransferService{
+		transferRepo: transferRepo,
+	}
+}
+
+func (serv *StockTransferService) GetAll(ctx context.Context, status string) ([]models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "StockTransferService.GetAll")
+	defer span.End()
+
+	switch status {
+	case "", models.StockTransferStatusInTransit, models.StockTransferStatusReceived, models.StockTransferStatusCancelled:
+	default:
+		return nil, apperrors.Validation("invalid stock transfer status")
+	}
+
+	return serv.transferRepo.GetAll(ctx, status)
+}
+
+func (serv *StockTransferService) GetByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "StockTransferService.GetByID")
+	defer span.End()
+
+	return serv.transferRepo.GetByID(ctx, id)
+}
+
+// Create mengirim stok dari outlet yang sedang dilayani ke outlet lain. outlet asal diambil
+// dari request kalau tidak diisi.
+func (serv *StockTransferService) Create(ctx context.Context, transfer *models.StockTransfer) (*models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "StockTransferService.Create")
+	defer span.End()
+
+	current, err := outlet.RequireID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.FromOutletID == 0 {
+		transfer.FromOutletID = current
+	}
+	if transfer.FromOutletID != current {
+		return nil, apperrors.Forbidden("WRONG_OUTLET", "stock can only be transferred from the current outlet")
+	}
+
+	if transfer.ToOutletID == 0 {
+		return nil, apperrors.Validation("destination outlet is required")
+	}
+
+	if transfer.FromOutletID == transfer.ToOutletID {
+		return nil, apperrors.Validation("source and destination outlet must be different")
+	}
+
+	if len(transfer.Items) == 0 {
+		return nil, apperrors.Validation("at least one item is required")
+	}
+
+	seen := make(map[int]bool)
+	for _, item := range transfer.Items {
+		if item.ProductID == 0 {
+			return nil, apperrors.Validation("product ID is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, apperrors.Validation("quantity must be greater than 0")
+		}
+		if seen[item.ProductID] {
+			return nil, apperrors.Validation("duplicate product in transfer")
+		}
+		seen[item.ProductID] = true
+	}
+
+	if err := serv.transferRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	return serv.transferRepo.GetByID(ctx, transfer.ID)
+}
+
+func (serv *StockTransferService) Receive(ctx context.Context, id int) (*models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "StockTransferService.Receive")
+	defer span.End()
+
+	if err := serv.transferRepo.Receive(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return serv.transferRepo.GetByID(ctx, id)
+}
+
+func (serv *StockTransferService) Cancel(ctx context.Context, id int) (*models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "StockTransferService.Cancel")
+	defer span.End()
+
+	if err := serv.transferRepo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return serv.transferRepo.GetByID(ctx, id)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStockTransferService_Create_DefaultsSourceOutlet(t *testing.T) {
+	mockRepo := new(mocks.StockTransferRepositoryMock)
+	service := NewStockTransferService(mockRepo)
+
+	ctx := outlet.WithID(context.Background(), 1)
+	transfer := &models.StockTransfer{
+		ToOutletID: 2,
+		Items:      []models.StockTransferItem{{ProductID: 5, Quantity: 3}},
+	}
+
+	mockRepo.On("Create", mock.Anything, transfer).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.StockTransfer).ID = 7
+	}).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, 7).Return(&models.StockTransfer{ID: 7, FromOutletID: 1, ToOutletID: 2, Status: models.StockTransferStatusInTransit}, nil)
+
+	created, err := service.Create(ctx, transfer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, transfer.FromOutletID)
+	assert.Equal(t, models.StockTransferStatusInTransit, created.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStockTransferService_Create_Invalid(t *testing.T) {
+	mockRepo := new(mocks.StockTransferRepositoryMock)
+	service := NewStockTransferService(mockRepo)
+
+	ctx := outlet.WithID(context.Background(), 1)
+	items := []models.StockTransferItem{{ProductID: 5, Quantity: 3}}
+	cases := map[string]*models.StockTransfer{
+		"same outlet":       {FromOutletID: 1, ToOutletID: 1, Items: items},
+		"missing outlet":    {FromOutletID: 1, Items: items},
+		"no items":          {FromOutletID: 1, ToOutletID: 2},
+		"zero quantity":     {FromOutletID: 1, ToOutletID: 2, Items: []models.StockTransferItem{{ProductID: 5}}},
+		"duplicate product": {FromOutletID: 1, ToOutletID: 2, Items: append(items, items...)},
+	}
+
+	for name, transfer := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Create(ctx, transfer)
+			assert.Error(t, err)
+		})
+	}
+
+	// tanpa outlet di context transfer tidak boleh dibuat dari outlet mana pun
+	_, err := service.Create(context.Background(), &models.StockTransfer{FromOutletID: 1, ToOutletID: 2, Items: items})
+	assert.ErrorIs(t, err, outlet.ErrMissingOutlet)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestStockTransferService_Create_FromOtherOutlet(t *testing.T) {
+	mockRepo := new(mocks.StockTransferRepositoryMock)
+	service := NewStockTransferService(mockRepo)
+
+	ctx := outlet.WithID(context.Background(), 2)
+	_, err := service.Create(ctx, &models.StockTransfer{
+		FromOutletID: 1,
+		ToOutletID:   3,
+		Items:        []models.StockTransferItem{{ProductID: 5, Quantity: 3}},
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, "stock can only be transferred from the current outlet", err.Error())
+}
+
+func TestStockTransferService_GetAll_InvalidStatus(t *testing.T) {
+	service := NewStockTransferService(new(mocks.StockTransferRepositoryMock))
+
+	_, err := service.GetAll(context.Background(), "lost")
+
+	assert.Error(t, err)
+}
+
+func TestOutletService_SetPrice_Negative(t *testing.T) {
+	mockRepo := new(mocks.OutletRepositoryMock)
+	service := NewOutletService(mockRepo)
+
+	price := -1.0
+	err := service.SetPrice(context.Background(), 1, 1, &price)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SetPrice", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOutletService_Create_NormalizesCode(t *testing.T) {
+	mockRepo := new(mocks.OutletRepositoryMock)
+	service := NewOutletService(mockRepo)
+
+	newOutlet := &models.Outlet{Code: " bdg ", Name: "Bandung"}
+	mockRepo.On("Create", mock.Anything, newOutlet).Return(nil)
+
+	err := service.Create(context.Background(), newOutlet)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BDG", newOutlet.Code)
+}
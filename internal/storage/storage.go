@@ -0,0 +1,38 @@
+// Package storage menyimpan file (gambar produk dan thumbnail-nya) di balik interface BlobStore,
+// supaya backend-nya bisa diganti antara filesystem lokal dan object storage S3-compatible.
+package storage
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// BlobStore menyimpan object berdasarkan key seperti "products/1/ab12/original.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL mengembalikan alamat publik object yang bisa dipakai langsung oleh UI
+	URL(key string) string
+}
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// cleanKey menolak key kosong, absolut, atau yang mencoba keluar dari root dengan ".."
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", ErrInvalidKey
+	}
+
+	return cleaned, nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
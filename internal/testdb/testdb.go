@@ -0,0 +1,166 @@
+//go:build integration
+
+// Package testdb menyalakan Postgres sungguhan untuk test integrasi (go test -tags integration).
+// secara default binary Postgres diunduh dan dijalankan lokal lewat embedded-postgres,
+// atau pakai database yang sudah ada (misalnya container di CI) lewat TEST_DATABASE_URL.
+package testdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fajar7xx/go-kasir-umam-ds/internal/database"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+// EnvDatabaseURL berisi connection string database yang dipakai sebagai pengganti Postgres lokal.
+// isinya dikosongkan oleh Reset, jadi jangan arahkan ke database yang datanya masih dibutuhkan.
+const EnvDatabaseURL = "TEST_DATABASE_URL"
+
+// Instance adalah database test yang skemanya sudah dimigrasi
+type Instance struct {
+	DB *sql.DB
+	// URL bisa dipakai untuk membuka koneksi tambahan
+	URL string
+
+	postgres   *embeddedpostgres.EmbeddedPostgres
+	runtimeDir string
+}
+
+// Start menyiapkan database dan menjalankan semua migrasi. dipanggil sekali dari TestMain.
+func Start() (*Instance, error) {
+	instance := &Instance{URL: os.Getenv(EnvDatabaseURL)}
+
+	if instance.URL == "" {
+		if err := instance.startEmbedded(); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := database.InitDB(instance.URL, database.PoolConfig{MaxOpenConns: 10, MaxIdleConns: 10})
+	if err != nil {
+		instance.Close()
+		return nil, err
+	}
+	instance.DB = db
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := database.Migrate(ctx, db); err != nil {
+		instance.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	return instance, nil
+}
+
+// startEmbedded menjalankan Postgres di port yang sedang kosong dengan data directory sementara.
+// binary-nya di-cache di ~/.embedded-postgres-go, jadi hanya diunduh saat pertama kali.
+func (i *Instance) startEmbedded() error {
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	i.runtimeDir, err = os.MkdirTemp("", "kasir-postgres-")
+	if err != nil {
+		return err
+	}
+
+	var logs bytes.Buffer
+	config := embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V15).
+		Port(port).
+		Database("kasir_test").
+		RuntimePath(i.runtimeDir).
+		StartTimeout(time.Minute).
+		Logger(&logs)
+
+	i.postgres = embeddedpostgres.NewDatabase(config)
+	if err := i.postgres.Start(); err != nil {
+		os.RemoveAll(i.runtimeDir)
+		return fmt.Errorf("start embedded postgres: %w\n%s", err, logs.String())
+	}
+
+	i.URL = config.GetConnectionURL() + "?sslmode=disable"
+	return nil
+}
+
+// Close menutup koneksi lalu mematikan Postgres lokal (database dari TEST_DATABASE_URL dibiarkan)
+func (i *Instance) Close() error {
+	var errs []error
+	if i.DB != nil {
+		if err := i.DB.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if i.postgres != nil {
+		if err := i.postgres.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+		os.RemoveAll(i.runtimeDir)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("testdb close: %v", errs)
+	}
+	return nil
+}
+
+// Reset mengosongkan semua tabel (kecuali schema_migrations) dan mengembalikan outlet 1
+// seperti setelah migrasi, sehingga setiap test mulai dari id 1
+func (i *Instance) Reset(t testing.TB) {
+	t.Helper()
+
+	ctx := context.Background()
+	rows, err := i.DB.QueryContext(ctx, `SELECT tablename FROM pg_tables
+			WHERE schemaname = 'public' AND tablename <> 'schema_migrations'`)
+	if err != nil {
+		t.Fatalf("testdb: list tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			t.Fatalf("testdb: list tables: %v", err)
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("testdb: list tables: %v", err)
+	}
+
+	_, err = i.DB.ExecContext(ctx, `TRUNCATE `+strings.Join(tables, ", ")+` RESTART IDENTITY CASCADE;
+		INSERT INTO outlets (id, code, name) VALUES (1, 'MAIN', 'Outlet Utama');
+		SELECT setval(pg_get_serial_sequence('outlets', 'id'), 1)`)
+	if err != nil {
+		t.Fatalf("testdb: reset: %v", err)
+	}
+}
+
+// Exec menjalankan SQL untuk menyiapkan data yang tidak bisa dibuat lewat repository
+// (misalnya poin yang sudah kedaluwarsa)
+func (i *Instance) Exec(t testing.TB, query string, args ...any) {
+	t.Helper()
+
+	if _, err := i.DB.ExecContext(context.Background(), query, args...); err != nil {
+		t.Fatalf("testdb: %v", err)
+	}
+}
+
+func freePort() (uint32, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
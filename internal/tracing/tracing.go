@@ -0,0 +1,83 @@
+// Package tracing menyiapkan OpenTelemetry tracing. satu request menghasilkan span HTTP
+// (middleware.Tracing), span per method service (Start) dan span per query SQL (otelsql di
+// database.InitDB), sehingga request lambat bisa dilihat bagian mana yang makan waktu.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "fajar7xx/go-kasir-umam-ds"
+
+// Config diisi dari TRACING_EXPORTER, TRACING_SERVICE_NAME dan TRACING_SAMPLE_RATIO
+type Config struct {
+	// Exporter: none (default, tracing mati), stdout atau otlp. endpoint OTLP diatur
+	// lewat variabel standar OTEL_EXPORTER_OTLP_ENDPOINT / OTEL_EXPORTER_OTLP_HEADERS
+	Exporter    string
+	ServiceName string
+	// SampleRatio 0..1 untuk trace baru, trace dari upstream mengikuti keputusan parent
+	SampleRatio float64
+}
+
+// Setup memasang TracerProvider dan propagator W3C trace-context global.
+// fungsi yang dikembalikan harus dipanggil saat shutdown supaya span terakhir terkirim.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(config.Exporter) {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		// ke stderr supaya tidak tercampur dengan log json di stdout
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start membuat span anak dari span di ctx, dipakai di awal setiap method service:
+//
+//	ctx, span := tracing.Start(ctx, "ProductService.GetAll")
+//	defer span.End()
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
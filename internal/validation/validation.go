@@ -0,0 +1,182 @@
+// Package validation memeriksa request berdasarkan tag `validate` di struct model.
+// semua field yang gagal dilaporkan sekaligus, bukan berhenti di error pertama.
+//
+// rule yang didukung (dipisah koma):
+//
+//	required   tidak boleh kosong (string kosong/spasi, angka 0, pointer nil)
+//	min=N      string: minimal N karakter, angka: minimal N
+//	max=N      string: maksimal N karakter, angka: maksimal N
+//	gt=N       angka harus lebih besar dari N
+//	gte=N      angka harus lebih besar atau sama dengan N
+//	email      format alamat email
+//	oneof=a b  nilai harus salah satu dari daftar
+//
+// pointer yang nil dilewati kecuali ada rule required, jadi field opsional cukup diberi rule biasa.
+package validation
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError adalah satu field yang tidak valid, dikirim ke client di error.details
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors mengumpulkan FieldError. service bisa menambah pengecekan yang butuh database
+// (misalnya category_id harus ada) sebelum memanggil Err.
+type Errors []FieldError
+
+func (e *Errors) Add(field, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// Merge menambahkan error lain dengan prefix, dipakai endpoint bulk: items[0].name
+func (e *Errors) Merge(prefix string, other Errors) {
+	for _, fieldErr := range other {
+		e.Add(prefix+"."+fieldErr.Field, fieldErr.Message)
+	}
+}
+
+// Err mengembalikan nil kalau tidak ada error, selain itu error validasi dengan details per field
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	appErr := apperrors.New(apperrors.ErrValidation, "VALIDATION_ERROR", "request validation failed")
+	appErr.Details = []FieldError(e)
+	return appErr
+}
+
+// Struct memeriksa semua field struct v (atau pointer ke struct) yang punya tag validate
+func Struct(v interface{}) Errors {
+	var errs Errors
+
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return errs
+	}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := fieldName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			if message := check(value.Field(i), rule); message != "" {
+				errs.Add(name, message)
+				// satu pesan per field sudah cukup
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// fieldName memakai nama di tag json supaya sama dengan yang dikirim client
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func check(value reflect.Value, rule string) string {
+	name, param, _ := strings.Cut(rule, "=")
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if name == "required" {
+				return "is required"
+			}
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch name {
+	case "required":
+		if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" {
+			return "is required"
+		}
+		if value.IsZero() {
+			return "is required"
+		}
+	case "min", "max", "gt", "gte":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validation: invalid %s parameter %q", name, param))
+		}
+		return checkLimit(value, name, limit, param)
+	case "email":
+		if value.String() == "" {
+			return ""
+		}
+		if _, err := mail.ParseAddress(value.String()); err != nil {
+			return "must be a valid email address"
+		}
+	case "oneof":
+		options := strings.Fields(param)
+		for _, option := range options {
+			if fmt.Sprint(value.Interface()) == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", name))
+	}
+
+	return ""
+}
+
+func checkLimit(value reflect.Value, rule string, limit float64, param string) string {
+	if value.Kind() == reflect.String {
+		length := float64(utf8.RuneCountInString(value.String()))
+		switch {
+		case rule == "min" && length < limit:
+			return "must be at least " + param + " characters"
+		case rule == "max" && length > limit:
+			return "must be at most " + param + " characters"
+		}
+		return ""
+	}
+
+	var number float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number = float64(value.Int())
+	case reflect.Float32, reflect.Float64:
+		number = value.Float()
+	case reflect.Slice, reflect.Map:
+		number = float64(value.Len())
+	default:
+		panic(fmt.Sprintf("validation: rule %q is not supported for %s", rule, value.Kind()))
+	}
+
+	switch {
+	case rule == "min" && number < limit:
+		return "must be at least " + param
+	case rule == "max" && number > limit:
+		return "must be at most " + param
+	case rule == "gt" && number <= limit:
+		return "must be greater than " + param
+	case rule == "gte" && number < limit:
+		return "must be greater than or equal to " + param
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sample struct {
+	Name     string   `json:"name" validate:"required,max=5"`
+	Note     *string  `json:"note" validate:"max=3"`
+	Email    string   `json:"email" validate:"email"`
+	Price    float64  `json:"price" validate:"gt=0"`
+	Quantity int      `json:"quantity" validate:"gte=1"`
+	Method   string   `json:"method" validate:"oneof=cash qris"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Ignored  string
+}
+
+func TestStruct_Valid(t *testing.T) {
+	note := "ok"
+	errs := Struct(&sample{Name: "Kopi", Note: &note, Email: "budi@example.com", Price: 1, Quantity: 1, Method: "cash"})
+
+	assert.Empty(t, errs)
+	assert.NoError(t, errs.Err())
+}
+
+func TestStruct_ReportsEveryField(t *testing.T) {
+	note := "too long"
+	errs := Struct(sample{Name: "  ", Note: &note, Email: "bukan-email", Method: "card", Tags: []string{"a", "b", "c"}})
+
+	assert.Equal(t, Errors{
+		{Field: "name", Message: "is required"},
+		{Field: "note", Message: "must be at most 3 characters"},
+		{Field: "email", Message: "must be a valid email address"},
+		{Field: "price", Message: "must be greater than 0"},
+		{Field: "quantity", Message: "must be greater than or equal to 1"},
+		{Field: "method", Message: "must be one of cash, qris"},
+		{Field: "tags", Message: "must be at most 2"},
+	}, errs)
+}
+
+func TestStruct_CountsRunes(t *testing.T) {
+	errs := Struct(sample{Name: "kópíé", Price: 1, Quantity: 1, Method: "qris"})
+	assert.Empty(t, errs)
+}
+
+func TestErrors_Err(t *testing.T) {
+	var errs Errors
+	errs.Add("name", "is required")
+	errs.Merge("items[0]", Errors{{Field: "quantity", Message: "must be greater than 0"}})
+
+	err := errs.Err()
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	appErr, ok := apperrors.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, "VALIDATION_ERROR", appErr.Code)
+	assert.Equal(t, []FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "items[0].quantity", Message: "must be greater than 0"},
+	}, appErr.Details)
+}
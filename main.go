@@ -1,84 +1,337 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fajar7xx/go-kasir-umam-ds/config"
 	"fajar7xx/go-kasir-umam-ds/handlers"
+	"fajar7xx/go-kasir-umam-ds/internal/apidocs"
+	"fajar7xx/go-kasir-umam-ds/internal/buildinfo"
 	"fajar7xx/go-kasir-umam-ds/internal/database"
+	"fajar7xx/go-kasir-umam-ds/internal/health"
+	"fajar7xx/go-kasir-umam-ds/internal/idempotency"
+	"fajar7xx/go-kasir-umam-ds/internal/logging"
+	"fajar7xx/go-kasir-umam-ds/internal/metrics"
+	"fajar7xx/go-kasir-umam-ds/internal/middleware"
+	"fajar7xx/go-kasir-umam-ds/internal/ratelimit"
+	"fajar7xx/go-kasir-umam-ds/internal/receipt"
 	"fajar7xx/go-kasir-umam-ds/internal/repositories"
+	"fajar7xx/go-kasir-umam-ds/internal/router"
+	"fajar7xx/go-kasir-umam-ds/internal/server"
 	"fajar7xx/go-kasir-umam-ds/internal/services"
+	"fajar7xx/go-kasir-umam-ds/internal/storage"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
-
-	"github.com/spf13/viper"
+	"syscall"
+	"time"
 )
 
 func main() {
-	// 1. load configuration
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	// 1. load configuration: default -> file (.env/YAML) -> env -> flag
+	args := os.Args[1:]
+	showConfig := len(args) >= 2 && args[0] == "config" && args[1] == "show"
+	if showConfig {
+		args = args[2:]
+	}
+
+	config, err := config.Load(args)
+	if err != nil {
+		fatal("failed to load configuration", err)
+	}
+
+	// `go-kasir-umam-ds config show` mencetak konfigurasi efektif tanpa secret lalu keluar
+	if showConfig {
+		config.WriteRedacted(os.Stdout)
+		if err := config.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	if _, err := os.Stat(".env"); err == nil {
-		viper.SetConfigFile(".env")
-		_ = viper.ReadInConfig()
+	if err := config.Validate(); err != nil {
+		fatal("invalid configuration", err)
 	}
 
-	config := config.Config{
-		Port:   viper.GetString("APP_PORT"),
-		DBConn: viper.GetString("SUPABASE_DB_CONN"),
+	// logger json ke stdout, log dari package log standar juga ikut diarahkan ke sini
+	logger, err := logging.New(os.Stdout, config.LogLevel, config.LogFormat)
+	if err != nil {
+		fatal("invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+
+	// ctx selesai saat SIGTERM (deploy/orchestrator) atau SIGINT (ctrl+c)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// tracing dipasang sebelum database supaya query migrasi juga memakai provider yang sama
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    config.TracingExporter,
+		ServiceName: config.TracingServiceName,
+		SampleRatio: config.TracingSampleRatio,
+	})
+	if err != nil {
+		fatal("invalid tracing configuration", err)
 	}
 
 	//2. database setup
-	db, err := database.InitDB(config.DBConn)
+	db, err := database.InitDB(config.DBConn, database.PoolConfig{
+		MaxOpenConns:    config.DBMaxOpenConns,
+		MaxIdleConns:    config.DBMaxIdleConns,
+		ConnMaxLifetime: time.Duration(config.DBConnMaxLifetimeMinutes) * time.Minute,
+		ConnMaxIdleTime: time.Duration(config.DBConnMaxIdleTimeMinutes) * time.Minute,
+	})
 	if err != nil {
-		log.Fatal("failed to initialize database: ", err)
+		fatal("failed to initialize database", err)
 	}
-	defer db.Close()
+
+	if err := database.Migrate(ctx, db); err != nil {
+		fatal("failed to run migrations", err)
+	}
+
+	receiptTemplates, err := receipt.LoadTemplates(config.ReceiptTemplateDir)
+	if err != nil {
+		fatal("failed to load receipt templates", err)
+	}
+
+	routes := router.New()
+
+	// penyimpanan file gambar produk
+	var blobStore storage.BlobStore
+	switch config.StorageDriver {
+	case "s3":
+		blobStore = storage.NewS3Store(storage.S3Config{
+			Endpoint:        config.S3Endpoint,
+			Region:          config.S3Region,
+			Bucket:          config.S3Bucket,
+			AccessKeyID:     config.S3AccessKeyID,
+			SecretAccessKey: config.S3SecretAccessKey,
+			PublicURL:       config.S3PublicURL,
+		}, nil)
+	case "local":
+		localStore, err := storage.NewLocalStore(config.StorageLocalDir, config.StoragePublicURL)
+		if err != nil {
+			fatal("failed to initialize local storage", err)
+		}
+		blobStore = localStore
+
+		// get /media/... (file gambar yang disimpan di disk)
+		publicPath := strings.TrimSuffix(config.StoragePublicURL, "/")
+		routes.Handle("GET "+publicPath+"/", http.StripPrefix(publicPath, localStore.Handler()))
+	default:
+		fatal("unknown STORAGE_DRIVER", fmt.Errorf("%q", config.StorageDriver))
+	}
+
+	// sudah divalidasi oleh config.Validate
+	thumbnailSizes, _ := config.ThumbnailSizes()
+	maxUploadBytes := int64(config.ImageMaxUploadMB) << 20
 
 	// dependency injection
+	// operasi yang menyentuh beberapa repository dijalankan dalam satu transaksi,
+	// diulang kalau gagal karena serialization failure atau deadlock
+	txManager := repositories.NewTxManager(db, repositories.TxConfig{
+		MaxAttempts: 3,
+		RetryDelay:  50 * time.Millisecond,
+	})
+
 	productRepository := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepository)
+	categoryRepository := repositories.NewCategoryRepository(db)
+	productImageRepository := repositories.NewProductImageRepository(db)
+	productService := services.NewProductService(productRepository, categoryRepository, productImageRepository, blobStore)
 	productHandler := handlers.NewProductHandler(productService)
 
-	categoryRepository := repositories.NewCategoryRepository(db)
+	productImageService := services.NewProductImageService(productRepository, productImageRepository, blobStore, services.ImageConfig{
+		MaxBytes:       maxUploadBytes,
+		ThumbnailSizes: thumbnailSizes,
+	})
+	productImageHandler := handlers.NewProductImageHandler(productImageService, maxUploadBytes)
+
 	categoryService := services.NewCategoryService(categoryRepository)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
-	// localhost:8080/health
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{
-			"status":  "ok",
-			"message": "API Successfull Running on port: 8080",
-		})
+	saleRepository := repositories.NewSaleRepository(db)
+	saleService := services.NewSaleService(saleRepository)
+	receiptHandler := handlers.NewReceiptHandler(saleService, receiptTemplates)
+
+	loyaltyRepository := repositories.NewLoyaltyRepository(db)
+	loyaltyService := services.NewLoyaltyService(loyaltyRepository, services.LoyaltyConfig{
+		EarnRate:    config.LoyaltyEarnRate,
+		RedeemValue: config.LoyaltyRedeemValue,
+		ExpiryDays:  config.LoyaltyPointsExpiryDays,
 	})
 
-	// GET /api/v1/products
-	// post /api/v1/products
-	http.HandleFunc("/api/v1/products", productHandler.HandleProducts)
+	customerRepository := repositories.NewCustomerRepository(db)
+	customerService := services.NewCustomerService(customerRepository)
+	customerHandler := handlers.NewCustomerHandler(customerService, loyaltyService)
+
+	draftOrderRepository := repositories.NewDraftOrderRepository(db)
+	draftOrderService := services.NewDraftOrderService(draftOrderRepository, loyaltyRepository, loyaltyService, txManager, config.SalesTaxRate)
+	draftOrderHandler := handlers.NewDraftOrderHandler(draftOrderService)
+
+	outletRepository := repositories.NewOutletRepository(db)
+	outletService := services.NewOutletService(outletRepository)
+	outletHandler := handlers.NewOutletHandler(outletService)
+
+	stockTransferRepository := repositories.NewStockTransferRepository(db)
+	stockTransferService := services.NewStockTransferService(stockTransferRepository)
+	stockTransferHandler := handlers.NewStockTransferHandler(stockTransferService)
+
+	// batas waktu per request, dipasang per route karena upload gambar butuh batas yang lebih longgar
+	timeout := middleware.Timeout(time.Duration(config.RequestTimeoutSeconds) * time.Second)
+	uploadTimeout := middleware.Timeout(time.Duration(config.UploadTimeoutSeconds) * time.Second)
 
-	// get /api/v1/products/{id}
-	// put /api/v1/products/{id}
-	// delete /api/v1/products/{id}
-	http.HandleFunc("/api/v1/products/{id}", productHandler.HandleProductByID)
+	// get /livez (proses hidup) dan get /readyz (database bisa di-ping, tidak sedang shutdown)
+	healthChecker := health.NewChecker(db, buildinfo.Get(), time.Duration(config.ReadinessTimeoutSeconds)*time.Second)
+	routes.HandleFunc("GET /livez", healthChecker.Livez)
+	routes.HandleFunc("GET /readyz", healthChecker.Readyz)
 
-	// get /api/v1/categories
-	// post /api/v1/categories
-	http.HandleFunc("/api/v1/categories", categoryHandler.HandleCategories)
+	// get /metrics (format Prometheus: request per route, connection pool, stok habis per outlet)
+	appMetrics := metrics.New(db, outletRepository)
+	routes.Handle("GET /metrics", appMetrics.Handler())
 
-	// get /api/v1/categories/{id}
-	// put /api/v1/categories/{id}
-	// delete /api/v1/categories/{id}
-	http.HandleFunc("/api/v1/categories/{id}", categoryHandler.HandleCategoryByID)
+	// semua POST yang membawa Idempotency-Key aman diulang oleh kasir
+	idempotencyRepository := repositories.NewIdempotencyRepository(db)
+	go purgeIdempotencyKeys(ctx, idempotencyRepository)
 
-	addr := "0.0.0.0:" + config.Port
-	fmt.Println("Server running on", addr)
+	// budget dan batas body khusus per pola route (lengkap dengan prefix group),
+	// route lain memakai budget read/write dan batas body JSON
+	limiter := ratelimit.New(ratelimit.Config{
+		Read:  ratelimit.PerMinute(config.RateLimitReadPerMinute),
+		Write: ratelimit.PerMinute(config.RateLimitWritePerMinute),
+		Routes: map[string]ratelimit.Rate{
+			uploadRoute: ratelimit.PerMinute(config.RateLimitUploadPerMinute),
+		},
+	})
+	bodyLimit := middleware.BodyLimit(middleware.BodyLimitConfig{
+		Default: int64(config.RequestMaxBodyKB) << 10,
+		Routes: map[string]int64{
+			// ruang tambahan untuk header multipart, ukuran file dicek lagi oleh handler (FILE_TOO_LARGE)
+			uploadRoute: maxUploadBytes + 1<<20,
+		},
+	})
+
+	// API key dicek sebelum rate limit supaya budget dihitung per key yang valid, dan body dibatasi
+	// sebelum Idempotency-Key membaca seluruh body. request tanpa izin tidak memesan key
+	api := routes.Group("/api/v1",
+		middleware.APIKey(config.APIKeys()),
+		limiter.Middleware,
+		bodyLimit,
+		idempotency.Middleware(idempotencyRepository, idempotency.Config{
+			TTL:         time.Duration(config.IdempotencyTTLHours) * time.Hour,
+			LockTimeout: time.Minute,
+		}),
+	)
+	registerAPIRoutes(api, apiHandlers{
+		product:       productHandler,
+		productImage:  productImageHandler,
+		category:      categoryHandler,
+		receipt:       receiptHandler,
+		customer:      customerHandler,
+		draftOrder:    draftOrderHandler,
+		outlet:        outletHandler,
+		stockTransfer: stockTransferHandler,
+	}, timeout, uploadTimeout)
+
+	// get /openapi.json (spesifikasi OpenAPI 3.1) dan get /docs (Swagger UI), tanpa API key
+	routes.HandleFunc("GET /openapi.json", apidocs.SpecHandler)
+	routes.HandleFunc("GET /docs", apidocs.UIHandler)
+	routes.HandleFunc("GET /docs/swagger-init.js", apidocs.UIHandler)
+	routes.HandleFunc("GET /docs/swagger-ui.css", apidocs.UIHandler)
+	routes.HandleFunc("GET /docs/swagger-ui-bundle.js", apidocs.UIHandler)
+
+	// middleware global, yang pertama paling luar: request id supaya access log dan semua error
+	// membawa id yang sama, lalu span HTTP (trace_id ikut tercatat di access log).
+	// Recover di dalam AccessLog dan Metrics supaya panic tercatat sebagai 500
+	routes.Use(
+		middleware.RequestID,
+		middleware.Tracing,
+		middleware.AccessLog(logger),
+		middleware.Metrics(appMetrics),
+		middleware.Recover(logger, appMetrics),
+		middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+			HSTS:                  config.SecurityHSTS,
+			HSTSMaxAgeSeconds:     config.SecurityHSTSMaxAgeSeconds,
+			ContentSecurityPolicy: config.SecurityCSP,
+		}),
+		// preflight CORS dijawab sebelum routing dan API key, browser tidak mengirim X-API-Key saat preflight
+		middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins:   config.CORSOrigins(),
+			AllowedMethods:   config.CORSMethods(),
+			AllowedHeaders:   config.CORSHeaders(),
+			ExposedHeaders:   config.CORSExposed(),
+			AllowCredentials: config.CORSAllowCredentials,
+			MaxAgeSeconds:    config.CORSMaxAgeSeconds,
+		}),
+	)
+
+	// timeout koneksi melindungi dari client lambat (slowloris), WriteTimeout harus lebih
+	// panjang dari batas request terpanjang (upload) supaya response 504 masih sempat terkirim
+	httpServer := &http.Server{
+		Addr:              "0.0.0.0:" + config.Port,
+		Handler:           routes,
+		ReadHeaderTimeout: time.Duration(config.HTTPReadHeaderTimeoutSeconds) * time.Second,
+		ReadTimeout:       time.Duration(config.HTTPReadTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(config.HTTPWriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(config.HTTPIdleTimeoutSeconds) * time.Second,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
 
-	err = http.ListenAndServe(addr, nil)
+	listener, err := net.Listen("tcp", httpServer.Addr)
 	if err != nil {
-		fmt.Println("Error starting server:", err)
+		fatal("failed to listen", err)
+	}
+	slog.Info("server running", "addr", httpServer.Addr, "version", buildinfo.Version)
+
+	runErr := server.Run(ctx, httpServer, listener, server.ShutdownConfig{
+		DrainDelay: time.Duration(config.ShutdownDrainSeconds) * time.Second,
+		Timeout:    time.Duration(config.ShutdownTimeoutSeconds) * time.Second,
+		OnDrain: func() {
+			// sinyal kedua langsung menghentikan proses tanpa menunggu
+			stop()
+			healthChecker.Drain()
+		},
+	})
+
+	// database dan tracing ditutup setelah semua request selesai
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(closeCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	if err := db.Close(); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+
+	if runErr != nil {
+		fatal("server stopped with error", runErr)
+	}
+	slog.Info("server stopped")
+}
+
+// fatal mencatat error lalu menghentikan aplikasi
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// purgeIdempotencyKeys menghapus Idempotency-Key yang sudah kedaluwarsa setiap jam sampai shutdown
+func purgeIdempotencyKeys(ctx context.Context, repo repositories.IdempotencyRepositoryInterface) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := repo.DeleteExpired(ctx); err != nil {
+				slog.Error("failed to purge idempotency keys", "error", err)
+			}
+		}
 	}
 }
@@ -4,10 +4,11 @@ import "time"
 
 type Category struct {
 	ID          int        `json:"id"`
-	Name        string     `json:"name"`
-	Description *string    `json:"description"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Description *string    `json:"description" validate:"max=1000"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+	Version     int        `json:"version"`
 }
 
 type CategorySummary struct {
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type Customer struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Phone     string     `json:"phone"`
+	Email     *string    `json:"email"`
+	Birthday  *string    `json:"birthday"` // format YYYY-MM-DD
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+const (
+	LoyaltyEntryEarn   = "earn"
+	LoyaltyEntryRedeem = "redeem"
+	LoyaltyEntryExpire = "expire"
+)
+
+// LoyaltyEntry adalah satu baris ledger poin. points positif untuk earn,
+// negatif untuk redeem/expire.
+type LoyaltyEntry struct {
+	ID         int        `json:"id"`
+	CustomerID int        `json:"customer_id"`
+	SaleID     *int       `json:"sale_id"`
+	Type       string     `json:"type"`
+	Points     int        `json:"points"`
+	Remaining  int        `json:"remaining"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type LoyaltyAccount struct {
+	CustomerID  int            `json:"customer_id"`
+	Balance     int            `json:"balance"`
+	RedeemValue float64        `json:"redeem_value"` // nilai rupiah per poin
+	Entries     []LoyaltyEntry `json:"entries"`
+}
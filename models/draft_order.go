@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	DraftOrderStatusOpen      = "open"
+	DraftOrderStatusPaid      = "paid"
+	DraftOrderStatusCancelled = "cancelled"
+	DraftOrderStatusMerged    = "merged"
+)
+
+// DraftOrder adalah pesanan yang diparkir (open tab), misalnya pelanggan yang masih makan di meja
+type DraftOrder struct {
+	ID           int              `json:"id"`
+	OutletID     int              `json:"outlet_id"`
+	TableNumber  *string          `json:"table_number"`
+	CustomerName *string          `json:"customer_name"`
+	Status       string           `json:"status"`
+	SaleID       *int             `json:"sale_id"`
+	Items        []DraftOrderItem `json:"items"`
+	Total        float64          `json:"total"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    *time.Time       `json:"updated_at"`
+}
+
+type DraftOrderItem struct {
+	ID           int       `json:"id"`
+	DraftOrderID int       `json:"draft_order_id"`
+	ProductID    int       `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	Quantity     int       `json:"quantity"`
+	Price        float64   `json:"price"`
+	Discount     float64   `json:"discount"`
+	Subtotal     float64   `json:"subtotal"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SplitLine memindahkan sebagian quantity sebuah item ke tab baru
+type SplitLine struct {
+	ItemID   int `json:"item_id"`
+	Quantity int `json:"quantity"`
+}
+
+type CheckoutRequest struct {
+	Discount     float64       `json:"discount"`
+	CustomerID   *int          `json:"customer_id"`
+	RedeemPoints int           `json:"redeem_points"`
+	Payments     []SalePayment `json:"payments"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IdempotencyRecord adalah request POST yang sudah pernah diterima dengan Idempotency-Key tertentu
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	// StatusCode nil selama request pertama masih diproses
+	StatusCode *int
+	Headers    map[string]string
+	Body       []byte
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// Completed bernilai true kalau response-nya sudah tersimpan dan bisa dikirim ulang
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != nil
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+const (
+	StockTransferStatusInTransit = "in_transit"
+	StockTransferStatusReceived  = "received"
+	StockTransferStatusCancelled = "cancelled"
+)
+
+// Outlet adalah satu cabang toko. produk dan kategori dipakai bersama, stok dan harga per outlet.
+type Outlet struct {
+	ID        int        `json:"id"`
+	Code      string     `json:"code"`
+	Name      string     `json:"name"`
+	Address   *string    `json:"address"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// OutletStock adalah posisi stok satu produk di satu outlet.
+// Available = Stock - Reserved (jumlah yang sedang dipegang open tab).
+type OutletStock struct {
+	OutletID      int      `json:"outlet_id"`
+	ProductID     int      `json:"product_id"`
+	ProductName   string   `json:"product_name"`
+	Stock         int      `json:"stock"`
+	Reserved      int      `json:"reserved"`
+	Available     int      `json:"available"`
+	Price         float64  `json:"price"`
+	PriceOverride *float64 `json:"price_override"`
+}
+
+// ConsolidatedStock menjumlahkan stok satu produk dari semua outlet,
+// termasuk barang yang sedang dalam perjalanan transfer.
+type ConsolidatedStock struct {
+	ProductID   int           `json:"product_id"`
+	ProductName string        `json:"product_name"`
+	Stock       int           `json:"stock"`
+	Reserved    int           `json:"reserved"`
+	InTransit   int           `json:"in_transit"`
+	Outlets     []OutletStock `json:"outlets"`
+}
+
+// OutletMetrics adalah angka bisnis per outlet untuk endpoint /metrics
+type OutletMetrics struct {
+	OutletID           int
+	OutletCode         string
+	ProductsOutOfStock int
+	OpenDraftOrders    int
+}
+
+// OutletPrice mengganti (atau menghapus, kalau nil) harga khusus produk di sebuah outlet
+type OutletPrice struct {
+	Price *float64 `json:"price"`
+}
+
+type StockTransfer struct {
+	ID           int                 `json:"id"`
+	FromOutletID int                 `json:"from_outlet_id"`
+	ToOutletID   int                 `json:"to_outlet_id"`
+	Status       string              `json:"status"`
+	Note         *string             `json:"note"`
+	Items        []StockTransferItem `json:"items"`
+	CreatedAt    time.Time           `json:"created_at"`
+	ReceivedAt   *time.Time          `json:"received_at"`
+	UpdatedAt    *time.Time          `json:"updated_at"`
+}
+
+type StockTransferItem struct {
+	ID          int    `json:"id"`
+	TransferID  int    `json:"transfer_id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import "encoding/json"
+
+// PatchField adalah satu field dari JSON merge patch (RFC 7396).
+// Set=false berarti field tidak dikirim dan tidak boleh diubah,
+// Null=true berarti field dikirim sebagai null (hapus nilainya).
+type PatchField[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON hanya dipanggil encoding/json kalau key-nya ada di body
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// SQLValue mengembalikan nilai untuk kolom database, nil kalau field di-null-kan
+func (f PatchField[T]) SQLValue() interface{} {
+	if f.Null {
+		return nil
+	}
+	return f.Value
+}
+
+// ProductPatch berisi field produk yang boleh diubah lewat PATCH
+type ProductPatch struct {
+	Name        PatchField[string]  `json:"name"`
+	Description PatchField[string]  `json:"description"`
+	Price       PatchField[float64] `json:"price"`
+	Stock       PatchField[int]     `json:"stock"`
+	CategoryID  PatchField[int]     `json:"category_id"`
+	// Version yang diharapkan, diisi handler dari If-Match
+	Version int `json:"-"`
+}
+
+// Apply menimpa field product dengan field yang dikirim di patch
+func (p ProductPatch) Apply(product *Product) {
+	if p.Name.Set {
+		product.Name = p.Name.Value
+	}
+	if p.Description.Set {
+		if p.Description.Null {
+			product.Description = nil
+		} else {
+			description := p.Description.Value
+			product.Description = &description
+		}
+	}
+	if p.Price.Set {
+		product.Price = p.Price.Value
+	}
+	if p.Stock.Set {
+		product.Stock = p.Stock.Value
+	}
+	if p.CategoryID.Set {
+		product.CategoryID = p.CategoryID.Value
+	}
+}
+
+// CategoryPatch berisi field kategori yang boleh diubah lewat PATCH
+type CategoryPatch struct {
+	Name        PatchField[string] `json:"name"`
+	Description PatchField[string] `json:"description"`
+	// Version yang diharapkan, diisi handler dari If-Match
+	Version int `json:"-"`
+}
+
+// Apply menimpa field category dengan field yang dikirim di patch
+func (p CategoryPatch) Apply(category *Category) {
+	if p.Name.Set {
+		category.Name = p.Name.Value
+	}
+	if p.Description.Set {
+		if p.Description.Null {
+			category.Description = nil
+		} else {
+			description := p.Description.Value
+			category.Description = &description
+		}
+	}
+}
@@ -4,13 +4,15 @@ import "time"
 
 type Product struct {
 	ID          int        `json:"id"`
-	Name        string     `json:"name"`
-	Description *string    `json:"description"` //accept null
-	Price       float64    `json:"price"`
-	Stock       int        `json:"stock"`
-	CategoryID  int        `json:"category_id"`
+	Name        string     `json:"name" validate:"required,max=255"`
+	Description *string    `json:"description" validate:"max=1000"` //accept null
+	Price       float64    `json:"price" validate:"gt=0"`
+	Stock       int        `json:"stock" validate:"gt=0"` // stok di outlet yang sedang dilayani
+	CategoryID  int        `json:"category_id" validate:"required"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+	// Version yang diharapkan saat update, diisi handler dari If-Match
+	Version int `json:"-"`
 }
 
 type ProductResponse struct {
@@ -18,9 +20,29 @@ type ProductResponse struct {
 	Name        string          `json:"name"`
 	Description *string         `json:"description"`
 	Price       float64         `json:"price"`
+	BasePrice   float64         `json:"base_price"`
 	Stock       int             `json:"stock"`
+	OutletID    int             `json:"outlet_id"`
 	CategoryID  int             `json:"category_id"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   *time.Time      `json:"updated_at"`
+	Version     int             `json:"version"`
 	Category    CategorySummary `json:"category"`
+	Images      []ProductImage  `json:"images"`
+}
+
+// ProductImage adalah gambar produk beserta thumbnail-nya.
+// Key dan ThumbnailKeys adalah lokasi di BlobStore, URL diisi oleh service.
+type ProductImage struct {
+	ID            int               `json:"id"`
+	ProductID     int               `json:"product_id"`
+	URL           string            `json:"url"`
+	Thumbnails    map[string]string `json:"thumbnails"`
+	ContentType   string            `json:"content_type"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	SizeBytes     int               `json:"size_bytes"`
+	Key           string            `json:"-"`
+	ThumbnailKeys map[string]string `json:"-"`
+	CreatedAt     time.Time         `json:"created_at"`
 }
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Sale adalah transaksi penjualan yang sudah dibayar
+type Sale struct {
+	ID             int           `json:"id"`
+	InvoiceNumber  string        `json:"invoice_number"`
+	OutletID       int           `json:"outlet_id"`
+	CustomerID     *int          `json:"customer_id"`
+	Subtotal       float64       `json:"subtotal"`
+	DiscountTotal  float64       `json:"discount_total"`
+	TaxTotal       float64       `json:"tax_total"`
+	Total          float64       `json:"total"`
+	PaidAmount     float64       `json:"paid_amount"`
+	ChangeAmount   float64       `json:"change_amount"`
+	PointsEarned   int           `json:"points_earned"`
+	PointsRedeemed int           `json:"points_redeemed"`
+	Items          []SaleItem    `json:"items"`
+	Payments       []SalePayment `json:"payments"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type SaleItem struct {
+	ID          int     `json:"id"`
+	SaleID      int     `json:"sale_id"`
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+	Discount    float64 `json:"discount"`
+	Subtotal    float64 `json:"subtotal"`
+}
+
+// SalePayment adalah satu tender (tunai, qris, debit, ...) dari sebuah sale
+type SalePayment struct {
+	ID     int     `json:"id"`
+	SaleID int     `json:"sale_id"`
+	Method string  `json:"method"`
+	Amount float64 `json:"amount"`
+}
@@ -0,0 +1,640 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/handlers"
+	"fajar7xx/go-kasir-umam-ds/internal/apidocs"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/mocks"
+	"fajar7xx/go-kasir-umam-ds/internal/receipt"
+	"fajar7xx/go-kasir-umam-ds/internal/router"
+	"fajar7xx/go-kasir-umam-ds/internal/validation"
+	"fajar7xx/go-kasir-umam-ds/models"
+	"fajar7xx/go-kasir-umam-ds/utils"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// test kontrak: response handler asli (service di-mock) divalidasi terhadap internal/apidocs/openapi.json.
+// schema response memakai additionalProperties false, jadi field baru di model yang belum ditulis
+// di spesifikasi (atau sebaliknya) membuat test gagal.
+
+const specURL = "https://kasir.local/openapi.json"
+
+var (
+	fixedTime = time.Date(2026, 1, 2, 8, 30, 0, 0, time.UTC)
+	testDesc  = "Kopi susu gula aren"
+)
+
+type contractMocks struct {
+	product       *mocks.ProductServiceMock
+	productImage  *mocks.ProductImageServiceMock
+	category      *mocks.CategoryServiceMock
+	sale          *mocks.SaleServiceMock
+	customer      *mocks.CustomerServiceMock
+	loyalty       *mocks.LoyaltyServiceMock
+	draftOrder    *mocks.DraftOrderServiceMock
+	outlet        *mocks.OutletServiceMock
+	stockTransfer *mocks.StockTransferServiceMock
+}
+
+// newContractRouter memasang route /api/v1 persis seperti main, tanpa middleware API key dan database
+func newContractRouter() (*router.Router, *contractMocks) {
+	m := &contractMocks{
+		product:       new(mocks.ProductServiceMock),
+		productImage:  new(mocks.ProductImageServiceMock),
+		category:      new(mocks.CategoryServiceMock),
+		sale:          new(mocks.SaleServiceMock),
+		customer:      new(mocks.CustomerServiceMock),
+		loyalty:       new(mocks.LoyaltyServiceMock),
+		draftOrder:    new(mocks.DraftOrderServiceMock),
+		outlet:        new(mocks.OutletServiceMock),
+		stockTransfer: new(mocks.StockTransferServiceMock),
+	}
+
+	routes := router.New()
+	passThrough := func(next http.Handler) http.Handler { return next }
+	registerAPIRoutes(routes.Group("/api/v1"), apiHandlers{
+		product:       handlers.NewProductHandler(m.product),
+		productImage:  handlers.NewProductImageHandler(m.productImage, 1<<20),
+		category:      handlers.NewCategoryHandler(m.category),
+		receipt:       handlers.NewReceiptHandler(m.sale, receipt.Templates{}),
+		customer:      handlers.NewCustomerHandler(m.customer, m.loyalty),
+		draftOrder:    handlers.NewDraftOrderHandler(m.draftOrder),
+		outlet:        handlers.NewOutletHandler(m.outlet),
+		stockTransfer: handlers.NewStockTransferHandler(m.stockTransfer),
+	}, passThrough, passThrough)
+	return routes, m
+}
+
+// openAPISpec membaca spesifikasi sebagai map untuk mencari operation, dan sebagai resource
+// JSON Schema (draft 2020-12, dialek OpenAPI 3.1) untuk memvalidasi body
+type openAPISpec struct {
+	doc      map[string]interface{}
+	compiler *jsonschema.Compiler
+}
+
+func loadSpec(t *testing.T) *openAPISpec {
+	t.Helper()
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(apidocs.Spec(), &doc))
+
+	resource, err := jsonschema.UnmarshalJSON(bytes.NewReader(apidocs.Spec()))
+	require.NoError(t, err)
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	require.NoError(t, compiler.AddResource(specURL, resource))
+
+	return &openAPISpec{doc: doc, compiler: compiler}
+}
+
+// operations mengembalikan semua "METHOD /path" di spesifikasi
+func (s *openAPISpec) operations() []string {
+	var ops []string
+	for path, item := range s.doc["paths"].(map[string]interface{}) {
+		for method := range item.(map[string]interface{}) {
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// lookup mengikuti pointer JSON di dalam dokumen, termasuk $ref lokal di ujungnya.
+// yang dikembalikan adalah pointer setelah $ref supaya bisa dipakai sebagai lokasi schema.
+func (s *openAPISpec) lookup(pointer ...string) (string, map[string]interface{}, bool) {
+	var node interface{} = s.doc
+	location := ""
+	for _, token := range pointer {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return "", nil, false
+		}
+		if node, ok = obj[token]; !ok {
+			return "", nil, false
+		}
+		location += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+
+		if ref, ok := node.(map[string]interface{})["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/responses/") {
+			name := strings.TrimPrefix(ref, "#/components/responses/")
+			node = s.doc["components"].(map[string]interface{})["responses"].(map[string]interface{})[name]
+			location = "/components/responses/" + name
+		}
+	}
+	obj, ok := node.(map[string]interface{})
+	return location, obj, ok
+}
+
+func (s *openAPISpec) validate(t *testing.T, location string, body []byte) {
+	t.Helper()
+
+	schema, err := s.compiler.Compile(specURL + "#" + location)
+	require.NoError(t, err, location)
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	require.NoError(t, err, string(body))
+	assert.NoError(t, schema.Validate(instance), "%s\n%s", location, body)
+}
+
+func TestOpenAPI_SpecCoversAllRoutes(t *testing.T) {
+	spec := loadSpec(t)
+	routes, _ := newContractRouter()
+
+	var registered []string
+	for _, pattern := range routes.Routes() {
+		if strings.Contains(pattern, " /api/v1/") {
+			registered = append(registered, pattern)
+		}
+	}
+	sort.Strings(registered)
+
+	assert.Equal(t, registered, spec.operations(), "route di routes.go dan paths di openapi.json harus sama")
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	w := httptest.NewRecorder()
+	apidocs.SpecHandler(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.True(t, json.Valid(w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	apidocs.UIHandler(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "swagger-ui")
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "script-src 'self';")
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	// aset Swagger UI dilayani dari binary, bukan dari CDN
+	for path, contentType := range map[string]string{
+		"/docs/swagger-ui.css":       "text/css",
+		"/docs/swagger-ui-bundle.js": "javascript",
+		"/docs/swagger-init.js":      "javascript",
+	} {
+		w = httptest.NewRecorder()
+		apidocs.UIHandler(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Header().Get("Content-Type"), contentType, path)
+		assert.NotEmpty(t, w.Body.Bytes(), path)
+	}
+
+	w = httptest.NewRecorder()
+	apidocs.UIHandler(w, httptest.NewRequest(http.MethodGet, "/docs/swagger.html", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+type contractCase struct {
+	name        string
+	method      string
+	path        string
+	body        string
+	contentType string
+	headers     map[string]string
+	setup       func(m *contractMocks)
+	status      int
+}
+
+func testProduct() *models.ProductResponse {
+	return &models.ProductResponse{
+		ID: 1, Name: "Kopi Susu", Description: &testDesc, Price: 18000, BasePrice: 20000, Stock: 12,
+		OutletID: 1, CategoryID: 2, CreatedAt: fixedTime, Version: 3,
+		Category: models.CategorySummary{ID: 2, Name: "Minuman"},
+		Images: []models.ProductImage{{
+			ID: 5, ProductID: 1, URL: "/media/products/1/a.jpg", Thumbnails: map[string]string{"128": "/media/products/1/a_128.jpg"},
+			ContentType: "image/jpeg", Width: 800, Height: 600, SizeBytes: 52000, CreatedAt: fixedTime,
+		}},
+	}
+}
+
+func testCategory() *models.Category {
+	return &models.Category{ID: 2, Name: "Minuman", CreatedAt: fixedTime, UpdatedAt: &fixedTime, Version: 1}
+}
+
+func testDraftOrder() *models.DraftOrder {
+	table := "A3"
+	return &models.DraftOrder{
+		ID: 7, OutletID: 1, TableNumber: &table, Status: models.DraftOrderStatusOpen, Total: 36000, CreatedAt: fixedTime,
+		Items: []models.DraftOrderItem{{
+			ID: 11, DraftOrderID: 7, ProductID: 1, ProductName: "Kopi Susu", Quantity: 2, Price: 18000, Subtotal: 36000, CreatedAt: fixedTime,
+		}},
+	}
+}
+
+func testSale() *models.Sale {
+	customerID := 4
+	return &models.Sale{
+		ID: 9, InvoiceNumber: "INV-20260102-0001", OutletID: 1, CustomerID: &customerID,
+		Subtotal: 36000, TaxTotal: 3960, Total: 39960, PaidAmount: 50000, ChangeAmount: 10040, PointsEarned: 36,
+		Items:     []models.SaleItem{{ID: 1, SaleID: 9, ProductID: 1, ProductName: "Kopi Susu", Quantity: 2, Price: 18000, Subtotal: 36000}},
+		Payments:  []models.SalePayment{{ID: 1, SaleID: 9, Method: "cash", Amount: 50000}},
+		CreatedAt: fixedTime,
+	}
+}
+
+func testCustomer() *models.Customer {
+	birthday := "1990-05-17"
+	return &models.Customer{ID: 4, Name: "Umam", Phone: "08123456789", Birthday: &birthday, CreatedAt: fixedTime}
+}
+
+func testOutlet() *models.Outlet {
+	return &models.Outlet{ID: 1, Code: "PST", Name: "Pusat", CreatedAt: fixedTime}
+}
+
+func testOutletStock() models.OutletStock {
+	return models.OutletStock{OutletID: 1, ProductID: 1, ProductName: "Kopi Susu", Stock: 12, Reserved: 2, Available: 10, Price: 18000}
+}
+
+func testTransfer() *models.StockTransfer {
+	return &models.StockTransfer{
+		ID: 3, FromOutletID: 1, ToOutletID: 2, Status: models.StockTransferStatusInTransit, CreatedAt: fixedTime,
+		Items: []models.StockTransferItem{{ID: 1, TransferID: 3, ProductID: 1, ProductName: "Kopi Susu", Quantity: 5}},
+	}
+}
+
+func etagOf(t *testing.T, v interface{}) string {
+	etag, err := utils.ETag(v)
+	require.NoError(t, err)
+	return etag
+}
+
+func imageUpload(t *testing.T) (string, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", "kopi.jpg")
+	require.NoError(t, err)
+	part.Write([]byte("jpeg"))
+	require.NoError(t, writer.Close())
+	return body.String(), writer.FormDataContentType()
+}
+
+func contractCases(t *testing.T) []contractCase {
+	outlet := map[string]string{"X-Outlet-ID": "1"}
+	productETag := map[string]string{"X-Outlet-ID": "1", "If-Match": etagOf(t, testProduct())}
+	categoryETag := map[string]string{"If-Match": etagOf(t, testCategory())}
+	uploadBody, uploadType := imageUpload(t)
+	price := 15000.0
+
+	return []contractCase{
+		// products
+		{name: "list products", method: "GET", path: "/api/v1/products", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.product.On("GetAll", mock.Anything).Return([]models.ProductResponse{*testProduct()}, nil)
+			}},
+		{name: "create product", method: "POST", path: "/api/v1/products", headers: outlet, status: 201,
+			body: `{"name":"Kopi Susu","description":null,"price":20000,"stock":12,"category_id":2}`,
+			setup: func(m *contractMocks) {
+				m.product.On("Create", mock.Anything, mock.Anything).Return(testProduct(), nil)
+			}},
+		{name: "create product validation error", method: "POST", path: "/api/v1/products", headers: outlet, status: 400,
+			body: `{"name":"","price":20000,"stock":12,"category_id":2}`,
+			setup: func(m *contractMocks) {
+				m.product.On("Create", mock.Anything, mock.Anything).Return(nil, &apperrors.Error{
+					Kind: apperrors.ErrValidation, Code: "VALIDATION_ERROR", Message: "request validation failed",
+					Details: validation.Errors{{Field: "name", Message: "is required"}},
+				})
+			}},
+		{name: "get product", method: "GET", path: "/api/v1/products/1", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.product.On("GetByID", mock.Anything, 1).Return(testProduct(), nil)
+			}},
+		{name: "get product not found", method: "GET", path: "/api/v1/products/99", headers: outlet, status: 404,
+			setup: func(m *contractMocks) {
+				m.product.On("GetByID", mock.Anything, 99).Return(nil, apperrors.NotFound("PRODUCT_NOT_FOUND", "product not found"))
+			}},
+		{name: "replace product", method: "PUT", path: "/api/v1/products/1", headers: productETag, status: 200,
+			body: `{"name":"Kopi Susu","description":"Kopi susu gula aren","price":20000,"stock":12,"category_id":2}`,
+			setup: func(m *contractMocks) {
+				m.product.On("GetByID", mock.Anything, 1).Return(testProduct(), nil)
+				m.product.On("Update", mock.Anything, 1, mock.Anything).Return(testProduct(), nil)
+			}},
+		{name: "replace product without If-Match", method: "PUT", path: "/api/v1/products/1", headers: outlet, status: 428,
+			body: `{"name":"Kopi Susu","price":20000,"stock":12,"category_id":2}`,
+			setup: func(m *contractMocks) {
+				m.product.On("GetByID", mock.Anything, 1).Return(testProduct(), nil)
+			}},
+		{name: "patch product", method: "PATCH", path: "/api/v1/products/1", headers: productETag, status: 200,
+			body: `{"price":21000}`, contentType: utils.MergePatchContentType,
+			setup: func(m *contractMocks) {
+				m.product.On("GetByID", mock.Anything, 1).Return(testProduct(), nil)
+				m.product.On("Patch", mock.Anything, 1, mock.Anything).Return(testProduct(), nil)
+			}},
+		{name: "delete product", method: "DELETE", path: "/api/v1/products/1", headers: productETag, status: 200,
+			setup: func(m *contractMocks) {
+				m.product.On("GetByID", mock.Anything, 1).Return(testProduct(), nil)
+				m.product.On("Delete", mock.Anything, 1, 3).Return(nil)
+			}},
+		{name: "upload product image", method: "POST", path: "/api/v1/products/1/images", headers: outlet, status: 201,
+			body: uploadBody, contentType: uploadType,
+			setup: func(m *contractMocks) {
+				m.productImage.On("Upload", mock.Anything, 1, mock.Anything).Return(&testProduct().Images[0], nil)
+			}},
+		{name: "delete product image", method: "DELETE", path: "/api/v1/products/1/images/5", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.productImage.On("Delete", mock.Anything, 1, 5).Return(nil)
+			}},
+
+		// categories
+		{name: "list categories", method: "GET", path: "/api/v1/categories", status: 200,
+			setup: func(m *contractMocks) {
+				m.category.On("GetAll", mock.Anything).Return([]models.Category{*testCategory()}, nil)
+			}},
+		{name: "create category", method: "POST", path: "/api/v1/categories", status: 201,
+			body: `{"name":"Minuman","description":"Kopi dan teh"}`,
+			setup: func(m *contractMocks) {
+				m.category.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					category := args.Get(1).(*models.Category)
+					category.ID, category.CreatedAt, category.Version = 2, fixedTime, 1
+				}).Return(nil)
+			}},
+		{name: "get category", method: "GET", path: "/api/v1/categories/2", status: 200,
+			setup: func(m *contractMocks) {
+				m.category.On("GetByID", mock.Anything, 2).Return(testCategory(), nil)
+			}},
+		{name: "replace category", method: "PUT", path: "/api/v1/categories/2", headers: categoryETag, status: 200,
+			body: `{"name":"Minuman","description":null}`,
+			setup: func(m *contractMocks) {
+				m.category.On("GetByID", mock.Anything, 2).Return(testCategory(), nil)
+				m.category.On("Update", mock.Anything, 2, mock.Anything).Return(testCategory(), nil)
+			}},
+		{name: "replace category stale If-Match", method: "PUT", path: "/api/v1/categories/2", status: 412,
+			headers: map[string]string{"If-Match": `"stale"`}, body: `{"name":"Minuman"}`,
+			setup: func(m *contractMocks) {
+				m.category.On("GetByID", mock.Anything, 2).Return(testCategory(), nil)
+			}},
+		{name: "patch category", method: "PATCH", path: "/api/v1/categories/2", headers: categoryETag, status: 200,
+			body: `{"description":null}`, contentType: utils.MergePatchContentType,
+			setup: func(m *contractMocks) {
+				m.category.On("GetByID", mock.Anything, 2).Return(testCategory(), nil)
+				m.category.On("Patch", mock.Anything, 2, mock.Anything).Return(testCategory(), nil)
+			}},
+		{name: "delete category", method: "DELETE", path: "/api/v1/categories/2", headers: categoryETag, status: 200,
+			setup: func(m *contractMocks) {
+				m.category.On("GetByID", mock.Anything, 2).Return(testCategory(), nil)
+				m.category.On("Delete", mock.Anything, 2, 1).Return(nil)
+			}},
+
+		// receipts
+		{name: "get receipt", method: "GET", path: "/api/v1/receipts/9?format=text", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.sale.On("GetByID", mock.Anything, 9).Return(testSale(), nil)
+			}},
+		{name: "get receipt unknown format", method: "GET", path: "/api/v1/receipts/9?format=html", headers: outlet, status: 400,
+			setup: func(m *contractMocks) {
+				m.sale.On("GetByID", mock.Anything, 9).Return(testSale(), nil)
+			}},
+
+		// customers
+		{name: "list customers", method: "GET", path: "/api/v1/customers", status: 200,
+			setup: func(m *contractMocks) {
+				m.customer.On("GetAll", mock.Anything).Return([]models.Customer{*testCustomer()}, nil)
+			}},
+		{name: "create customer", method: "POST", path: "/api/v1/customers", status: 201,
+			body: `{"name":"Umam","phone":"08123456789","email":null,"birthday":"1990-05-17"}`,
+			setup: func(m *contractMocks) {
+				m.customer.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					customer := args.Get(1).(*models.Customer)
+					customer.ID, customer.CreatedAt = 4, fixedTime
+				}).Return(nil)
+			}},
+		{name: "lookup customer", method: "GET", path: "/api/v1/customers/lookup?phone=08123456789", status: 200,
+			setup: func(m *contractMocks) {
+				m.customer.On("GetByPhone", mock.Anything, "08123456789").Return(testCustomer(), nil)
+			}},
+		{name: "get customer", method: "GET", path: "/api/v1/customers/4", status: 200,
+			setup: func(m *contractMocks) {
+				m.customer.On("GetByID", mock.Anything, 4).Return(testCustomer(), nil)
+			}},
+		{name: "update customer", method: "PUT", path: "/api/v1/customers/4", status: 200,
+			body: `{"name":"Umam","phone":"08123456789"}`,
+			setup: func(m *contractMocks) {
+				m.customer.On("Update", mock.Anything, 4, mock.Anything).Return(testCustomer(), nil)
+			}},
+		{name: "delete customer", method: "DELETE", path: "/api/v1/customers/4", status: 200,
+			setup: func(m *contractMocks) {
+				m.customer.On("Delete", mock.Anything, 4).Return(nil)
+			}},
+		{name: "customer points", method: "GET", path: "/api/v1/customers/4/points", status: 200,
+			setup: func(m *contractMocks) {
+				saleID := 9
+				expires := fixedTime.AddDate(1, 0, 0)
+				m.customer.On("GetByID", mock.Anything, 4).Return(testCustomer(), nil)
+				m.loyalty.On("GetAccount", mock.Anything, 4).Return(&models.LoyaltyAccount{
+					CustomerID: 4, Balance: 36, RedeemValue: 100,
+					Entries: []models.LoyaltyEntry{{
+						ID: 1, CustomerID: 4, SaleID: &saleID, Type: models.LoyaltyEntryEarn, Points: 36, Remaining: 36,
+						ExpiresAt: &expires, CreatedAt: fixedTime,
+					}},
+				}, nil)
+			}},
+
+		// draft orders
+		{name: "list draft orders", method: "GET", path: "/api/v1/draft-orders", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("GetOpen", mock.Anything).Return([]models.DraftOrder{*testDraftOrder()}, nil)
+			}},
+		{name: "create draft order", method: "POST", path: "/api/v1/draft-orders", headers: outlet, status: 201,
+			body: `{"table_number":"A3","items":[{"product_id":1,"quantity":2}]}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("Create", mock.Anything, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "get draft order", method: "GET", path: "/api/v1/draft-orders/7", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("GetByID", mock.Anything, 7).Return(testDraftOrder(), nil)
+			}},
+		{name: "update draft order", method: "PUT", path: "/api/v1/draft-orders/7", headers: outlet, status: 200,
+			body: `{"table_number":"B1","customer_name":"Umam"}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("UpdateTag", mock.Anything, 7, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "patch draft order", method: "PATCH", path: "/api/v1/draft-orders/7", headers: outlet, status: 200,
+			body: `{"table_number":"B1"}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("UpdateTag", mock.Anything, 7, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "cancel draft order", method: "DELETE", path: "/api/v1/draft-orders/7", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("Cancel", mock.Anything, 7).Return(nil)
+			}},
+		{name: "add draft order item", method: "POST", path: "/api/v1/draft-orders/7/items", headers: outlet, status: 201,
+			body: `{"product_id":1,"quantity":2,"discount":0}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("AddItem", mock.Anything, 7, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "add draft order item insufficient stock", method: "POST", path: "/api/v1/draft-orders/7/items", headers: outlet, status: 409,
+			body: `{"product_id":1,"quantity":200}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("AddItem", mock.Anything, 7, mock.Anything).Return(nil, apperrors.Conflict("INSUFFICIENT_STOCK", "insufficient stock"))
+			}},
+		{name: "update draft order item", method: "PUT", path: "/api/v1/draft-orders/7/items/11", headers: outlet, status: 200,
+			body: `{"quantity":3,"discount":1000}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("UpdateItem", mock.Anything, 7, 11, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "patch draft order item", method: "PATCH", path: "/api/v1/draft-orders/7/items/11", headers: outlet, status: 200,
+			body: `{"quantity":3}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("UpdateItem", mock.Anything, 7, 11, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "remove draft order item", method: "DELETE", path: "/api/v1/draft-orders/7/items/11", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("RemoveItem", mock.Anything, 7, 11).Return(testDraftOrder(), nil)
+			}},
+		{name: "merge draft orders", method: "POST", path: "/api/v1/draft-orders/7/merge", headers: outlet, status: 200,
+			body: `{"source_id":8}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("Merge", mock.Anything, 7, 8).Return(testDraftOrder(), nil)
+			}},
+		{name: "split draft order", method: "POST", path: "/api/v1/draft-orders/7/split", headers: outlet, status: 201,
+			body: `{"items":[{"item_id":11,"quantity":1}]}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("Split", mock.Anything, 7, mock.Anything).Return(testDraftOrder(), nil)
+			}},
+		{name: "checkout draft order", method: "POST", path: "/api/v1/draft-orders/7/checkout", headers: outlet, status: 201,
+			body: `{"discount":0,"customer_id":4,"redeem_points":0,"payments":[{"method":"cash","amount":50000}]}`,
+			setup: func(m *contractMocks) {
+				m.draftOrder.On("Checkout", mock.Anything, 7, mock.Anything).Return(testSale(), nil)
+			}},
+
+		// outlets & stock
+		{name: "list outlets", method: "GET", path: "/api/v1/outlets", status: 200,
+			setup: func(m *contractMocks) {
+				m.outlet.On("GetAll", mock.Anything).Return([]models.Outlet{*testOutlet()}, nil)
+			}},
+		{name: "create outlet", method: "POST", path: "/api/v1/outlets", status: 201,
+			body: `{"code":"PST","name":"Pusat","address":null}`,
+			setup: func(m *contractMocks) {
+				m.outlet.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					outlet := args.Get(1).(*models.Outlet)
+					outlet.ID, outlet.CreatedAt = 1, fixedTime
+				}).Return(nil)
+			}},
+		{name: "get outlet", method: "GET", path: "/api/v1/outlets/1", status: 200,
+			setup: func(m *contractMocks) {
+				m.outlet.On("GetByID", mock.Anything, 1).Return(testOutlet(), nil)
+			}},
+		{name: "update outlet", method: "PUT", path: "/api/v1/outlets/1", status: 200,
+			body: `{"code":"PST","name":"Pusat"}`,
+			setup: func(m *contractMocks) {
+				m.outlet.On("Update", mock.Anything, 1, mock.Anything).Return(testOutlet(), nil)
+			}},
+		{name: "outlet stock", method: "GET", path: "/api/v1/outlets/1/stock", status: 200,
+			setup: func(m *contractMocks) {
+				stock := testOutletStock()
+				stock.PriceOverride = &price
+				m.outlet.On("GetStock", mock.Anything, 1).Return([]models.OutletStock{stock}, nil)
+			}},
+		{name: "set outlet price", method: "PUT", path: "/api/v1/outlets/1/prices/1", status: 200,
+			body: `{"price":15000}`,
+			setup: func(m *contractMocks) {
+				m.outlet.On("SetPrice", mock.Anything, 1, 1, mock.Anything).Return(nil)
+			}},
+		{name: "consolidated stock", method: "GET", path: "/api/v1/stock", status: 200,
+			setup: func(m *contractMocks) {
+				m.outlet.On("GetConsolidatedStock", mock.Anything).Return([]models.ConsolidatedStock{{
+					ProductID: 1, ProductName: "Kopi Susu", Stock: 20, Reserved: 2, InTransit: 5,
+					Outlets: []models.OutletStock{testOutletStock()},
+				}}, nil)
+			}},
+
+		// stock transfers
+		{name: "list stock transfers", method: "GET", path: "/api/v1/stock-transfers?status=in_transit", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.stockTransfer.On("GetAll", mock.Anything, "in_transit").Return([]models.StockTransfer{*testTransfer()}, nil)
+			}},
+		{name: "create stock transfer", method: "POST", path: "/api/v1/stock-transfers", headers: outlet, status: 201,
+			body: `{"to_outlet_id":2,"note":null,"items":[{"product_id":1,"quantity":5}]}`,
+			setup: func(m *contractMocks) {
+				m.stockTransfer.On("Create", mock.Anything, mock.Anything).Return(testTransfer(), nil)
+			}},
+		{name: "get stock transfer", method: "GET", path: "/api/v1/stock-transfers/3", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.stockTransfer.On("GetByID", mock.Anything, 3).Return(testTransfer(), nil)
+			}},
+		{name: "receive stock transfer", method: "POST", path: "/api/v1/stock-transfers/3/receive", headers: map[string]string{"X-Outlet-ID": "2"}, status: 200,
+			setup: func(m *contractMocks) {
+				received := testTransfer()
+				received.Status, received.ReceivedAt = models.StockTransferStatusReceived, &fixedTime
+				m.stockTransfer.On("Receive", mock.Anything, 3).Return(received, nil)
+			}},
+		{name: "cancel stock transfer", method: "POST", path: "/api/v1/stock-transfers/3/cancel", headers: outlet, status: 200,
+			setup: func(m *contractMocks) {
+				m.stockTransfer.On("Cancel", mock.Anything, 3).Return(testTransfer(), nil)
+			}},
+	}
+}
+
+func TestOpenAPI_Contract(t *testing.T) {
+	spec := loadSpec(t)
+	covered := make(map[string]bool)
+
+	for _, tc := range contractCases(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			routes, m := newContractRouter()
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.body != "" {
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				req.Header.Set("Content-Type", contentType)
+			}
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, req)
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+
+			// r.Pattern diisi ServeMux pada request yang sama
+			require.NotEmpty(t, req.Pattern)
+			method, path, _ := strings.Cut(req.Pattern, " ")
+			operation := []string{"paths", path, strings.ToLower(method)}
+			covered[req.Pattern] = true
+
+			if tc.body != "" {
+				mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+				location, _, ok := spec.lookup(append(operation, "requestBody", "content", mediaType, "schema")...)
+				require.True(t, ok, "%s has no request body for %s", req.Pattern, mediaType)
+				if strings.HasSuffix(mediaType, "json") {
+					spec.validate(t, location, []byte(tc.body))
+				}
+			}
+
+			// status sukses wajib ditulis satu per satu, status error boleh jatuh ke default
+			responseKey := strconv.Itoa(w.Code)
+			if _, _, ok := spec.lookup(append(operation, "responses", responseKey)...); !ok {
+				require.GreaterOrEqual(t, w.Code, 400, "%s does not document status %d", req.Pattern, w.Code)
+				responseKey = "default"
+			}
+			location, response, ok := spec.lookup(append(operation, "responses", responseKey)...)
+			require.True(t, ok, "%s does not document status %d", req.Pattern, w.Code)
+
+			content, _ := response["content"].(map[string]interface{})
+			mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+			require.Contains(t, content, mediaType, "%s %d", req.Pattern, w.Code)
+			if mediaType == "application/json" {
+				spec.validate(t, location+"/content/application~1json/schema", w.Body.Bytes())
+			}
+		})
+	}
+
+	for _, operation := range spec.operations() {
+		assert.True(t, covered[operation], "no contract test for %s", operation)
+	}
+}
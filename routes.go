@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fajar7xx/go-kasir-umam-ds/handlers"
+	"fajar7xx/go-kasir-umam-ds/internal/outlet"
+	"fajar7xx/go-kasir-umam-ds/internal/router"
+)
+
+// uploadRoute (pola lengkap dengan prefix) punya budget rate limit dan batas body sendiri, lihat main
+const uploadRoute = "POST /api/v1/products/{id}/images"
+
+// apiHandlers semua handler /api/v1
+type apiHandlers struct {
+	product       *handlers.ProductHandler
+	productImage  *handlers.ProductImageHandler
+	category      *handlers.CategoryHandler
+	receipt       *handlers.ReceiptHandler
+	customer      *handlers.CustomerHandler
+	draftOrder    *handlers.DraftOrderHandler
+	outlet        *handlers.OutletHandler
+	stockTransfer *handlers.StockTransferHandler
+}
+
+// registerAPIRoutes mendaftarkan semua route /api/v1 ke group api. dipisah dari main supaya
+// test kontrak OpenAPI (openapi_test.go) memakai daftar route yang sama dengan server.
+func registerAPIRoutes(api *router.Group, h apiHandlers, timeout, uploadTimeout router.Middleware) {
+	v1 := api.With(timeout)
+	// route yang wajib X-Outlet-ID
+	outletRequired := v1.With(outlet.Require)
+
+	// patch products/{id} dan categories/{id} memakai JSON merge patch
+	outletRequired.HandleFunc("GET /products", h.product.GetAll)
+	outletRequired.HandleFunc("POST /products", h.product.Create)
+	outletRequired.HandleFunc("GET /products/{id}", h.product.GetByID)
+	outletRequired.HandleFunc("PUT /products/{id}", h.product.Update)
+	outletRequired.HandleFunc("PATCH /products/{id}", h.product.Patch)
+	outletRequired.HandleFunc("DELETE /products/{id}", h.product.Delete)
+
+	// post products/{id}/images (multipart, field "image")
+	api.With(uploadTimeout, outlet.Require).HandleFunc("POST /products/{id}/images", h.productImage.Upload)
+	outletRequired.HandleFunc("DELETE /products/{id}/images/{imageId}", h.productImage.Delete)
+
+	v1.HandleFunc("GET /categories", h.category.GetAll)
+	v1.HandleFunc("POST /categories", h.category.Create)
+	v1.HandleFunc("GET /categories/{id}", h.category.GetByID)
+	v1.HandleFunc("PUT /categories/{id}", h.category.Update)
+	v1.HandleFunc("PATCH /categories/{id}", h.category.Patch)
+	v1.HandleFunc("DELETE /categories/{id}", h.category.Delete)
+
+	// get receipts/{id}?format=escpos|text|pdf&store=default
+	outletRequired.HandleFunc("GET /receipts/{id}", h.receipt.GetByID)
+
+	// get customers/lookup?phone=08123456789
+	v1.HandleFunc("GET /customers", h.customer.GetAll)
+	v1.HandleFunc("POST /customers", h.customer.Create)
+	v1.HandleFunc("GET /customers/lookup", h.customer.Lookup)
+	v1.HandleFunc("GET /customers/{id}", h.customer.GetByID)
+	v1.HandleFunc("PUT /customers/{id}", h.customer.Update)
+	v1.HandleFunc("DELETE /customers/{id}", h.customer.Delete)
+	v1.HandleFunc("GET /customers/{id}/points", h.customer.GetPoints)
+
+	// draft order = open tab. put/patch draft-orders/{id} mengubah nomor meja / nama customer,
+	// delete membatalkan
+	outletRequired.HandleFunc("GET /draft-orders", h.draftOrder.GetOpen)
+	outletRequired.HandleFunc("POST /draft-orders", h.draftOrder.Create)
+	outletRequired.HandleFunc("GET /draft-orders/{id}", h.draftOrder.GetByID)
+	outletRequired.HandleFunc("PUT /draft-orders/{id}", h.draftOrder.UpdateTag)
+	outletRequired.HandleFunc("PATCH /draft-orders/{id}", h.draftOrder.UpdateTag)
+	outletRequired.HandleFunc("DELETE /draft-orders/{id}", h.draftOrder.Cancel)
+	outletRequired.HandleFunc("POST /draft-orders/{id}/items", h.draftOrder.AddItem)
+	outletRequired.HandleFunc("PUT /draft-orders/{id}/items/{itemId}", h.draftOrder.UpdateItem)
+	outletRequired.HandleFunc("PATCH /draft-orders/{id}/items/{itemId}", h.draftOrder.UpdateItem)
+	outletRequired.HandleFunc("DELETE /draft-orders/{id}/items/{itemId}", h.draftOrder.RemoveItem)
+	outletRequired.HandleFunc("POST /draft-orders/{id}/merge", h.draftOrder.Merge)
+	outletRequired.HandleFunc("POST /draft-orders/{id}/split", h.draftOrder.Split)
+	outletRequired.HandleFunc("POST /draft-orders/{id}/checkout", h.draftOrder.Checkout)
+
+	// outlets/{id}/stock stok per outlet, outlets/{id}/prices/{productId} harga khusus outlet
+	v1.HandleFunc("GET /outlets", h.outlet.GetAll)
+	v1.HandleFunc("POST /outlets", h.outlet.Create)
+	v1.HandleFunc("GET /outlets/{id}", h.outlet.GetByID)
+	v1.HandleFunc("PUT /outlets/{id}", h.outlet.Update)
+	v1.HandleFunc("GET /outlets/{id}/stock", h.outlet.GetStock)
+	v1.HandleFunc("PUT /outlets/{id}/prices/{productId}", h.outlet.SetPrice)
+
+	// get stock (stok gabungan semua outlet)
+	v1.HandleFunc("GET /stock", h.outlet.GetConsolidatedStock)
+
+	// get stock-transfers?status=in_transit (transfer dari/ke outlet yang sedang dilayani)
+	outletRequired.HandleFunc("GET /stock-transfers", h.stockTransfer.GetAll)
+	outletRequired.HandleFunc("POST /stock-transfers", h.stockTransfer.Create)
+	outletRequired.HandleFunc("GET /stock-transfers/{id}", h.stockTransfer.GetByID)
+	outletRequired.HandleFunc("POST /stock-transfers/{id}/receive", h.stockTransfer.Receive)
+	outletRequired.HandleFunc("POST /stock-transfers/{id}/cancel", h.stockTransfer.Cancel)
+}
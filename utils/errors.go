@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"log/slog"
+	"net/http"
+)
+
+// StatusFor memetakan Kind error domain ke status HTTP
+func StatusFor(err error) int {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrUnprocessable):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperrors.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, apperrors.ErrPrecondition):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperrors.ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, apperrors.ErrUnsupported):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, apperrors.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// SendAppError mengirim error dari service/repository dengan status yang sesuai.
+// error yang tidak dikenali dianggap internal: pesannya dicatat di log, bukan dikirim ke client.
+func SendAppError(w http.ResponseWriter, err error) {
+	err = apperrors.FromDB(err)
+
+	if appErr, ok := apperrors.As(err); ok {
+		if appErr.Err != nil && errors.Is(err, apperrors.ErrUnavailable) {
+			slog.Error("service unavailable", "error", appErr.Err, "request_id", w.Header().Get(HeaderRequestID))
+		}
+		if appErr.Details != nil {
+			SendErrorWithDetails(w, appErr.Code, appErr.Message, appErr.Details, StatusFor(err))
+			return
+		}
+		SendError(w, appErr.Code, appErr.Message, StatusFor(err))
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		SendError(w, "TIMEOUT", "Request timeout", http.StatusGatewayTimeout)
+		return
+	}
+
+	slog.Error("internal error", "error", err, "request_id", w.Header().Get(HeaderRequestID))
+	SendError(w, "INTERNAL_ERROR", "internal server error", http.StatusInternalServerError)
+}
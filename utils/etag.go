@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"fajar7xx/go-kasir-umam-ds/internal/tracing"
+	"net/http"
+	"strings"
+)
+
+// HeaderOutletID dikirim aplikasi kasir untuk menandai outlet-nya (lihat package outlet).
+// ada di sini karena body produk berbeda per outlet, jadi response ber-ETag memakai Vary: X-Outlet-ID
+const HeaderOutletID = "X-Outlet-ID"
+
+var (
+	errIfMatchRequired = apperrors.New(apperrors.ErrPreconditionRequired, "PRECONDITION_REQUIRED",
+		"If-Match header is required, send the ETag from the last GET")
+	errETagMismatch = apperrors.New(apperrors.ErrPrecondition, "PRECONDITION_FAILED",
+		"resource was modified by another request, reload and try again")
+)
+
+// ETag menghitung strong ETag dari representasi JSON data. dihitung dari isi response
+// (bukan hanya kolom version) supaya stok/harga outlet dan gambar yang berubah juga terdeteksi.
+func ETag(data interface{}) (string, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// SendSuccessWithETag sama seperti SendSuccess tapi menambahkan header ETag.
+// untuk GET/HEAD dengan If-None-Match yang cocok, dikirim 304 tanpa body.
+func SendSuccessWithETag(w http.ResponseWriter, r *http.Request, data interface{}, status int) {
+	// span terpisah supaya waktu encode JSON (ETag + body) terlihat di trace
+	_, span := tracing.Start(r.Context(), "utils.SendSuccessWithETag")
+	defer span.End()
+
+	etag, err := ETag(data)
+	if err != nil {
+		SendAppError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	// cache tidak boleh memakai response outlet lain
+	w.Header().Add("Vary", HeaderOutletID)
+
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag, false) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	SendSuccess(w, data, status)
+}
+
+// CheckIfMatch memastikan header If-Match ada dan cocok dengan ETag resource saat ini.
+// header kosong -> 428, tidak cocok -> 412.
+func CheckIfMatch(r *http.Request, current interface{}) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return errIfMatchRequired
+	}
+
+	etag, err := ETag(current)
+	if err != nil {
+		return err
+	}
+
+	if !etagMatches(ifMatch, etag, true) {
+		return errETagMismatch
+	}
+	return nil
+}
+
+// etagMatches mencocokkan daftar ETag di header (dipisah koma, atau "*") dengan etag.
+// strong=true dipakai If-Match: ETag weak (W/) tidak pernah cocok.
+func etagMatches(header, etag string, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak := strings.TrimPrefix(candidate, "W/"); weak != candidate {
+			if strong {
+				continue
+			}
+			candidate = weak
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
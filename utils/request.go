@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fajar7xx/go-kasir-umam-ds/internal/apperrors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DecodeJSON membaca body JSON ke dst dan menolak field yang tidak dikenal,
+// supaya salah ketik seperti "categori_id" tidak diam-diam diabaikan.
+func DecodeJSON(r *http.Request, dst interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxErr):
+			// batas dari middleware.BodyLimit
+			return apperrors.New(apperrors.ErrTooLarge, "PAYLOAD_TOO_LARGE",
+				"request body must be at most "+strconv.FormatInt(maxErr.Limit, 10)+" bytes")
+		case errors.Is(err, io.EOF):
+			return invalidRequest("request body is required")
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			return invalidRequest("unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field "))
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			return invalidRequest("field \"" + typeErr.Field + "\" must be " + typeErr.Type.String())
+		default:
+			return invalidRequest("invalid request body")
+		}
+	}
+
+	if decoder.More() {
+		return invalidRequest("request body must contain a single JSON object")
+	}
+
+	return nil
+}
+
+// MergePatchContentType adalah media type JSON merge patch (RFC 7396)
+const MergePatchContentType = "application/merge-patch+json"
+
+// DecodeMergePatch membaca body PATCH. application/json tetap diterima untuk client lama,
+// isinya harus berupa object karena patch selain object akan mengganti seluruh resource.
+func DecodeMergePatch(r *http.Request, dst interface{}) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != MergePatchContentType && mediaType != "application/json") {
+			return apperrors.New(apperrors.ErrUnsupported, "UNSUPPORTED_MEDIA_TYPE",
+				"PATCH body must be "+MergePatchContentType)
+		}
+	}
+
+	return DecodeJSON(r, dst)
+}
+
+func invalidRequest(message string) error {
+	return apperrors.New(apperrors.ErrValidation, "INVALID_REQUEST", message)
+}
@@ -19,8 +19,13 @@ type ErrorDetail struct {
 	Code    string      `json:"code"`
 	Message string      `json:"message"`
 	Details interface{} `json:"details,omitempty"`
+	// RequestID sama dengan header X-Request-ID, dipakai untuk mencari log saat ada komplain
+	RequestID string `json:"request_id,omitempty"`
 }
 
+// HeaderRequestID diisi oleh middleware.RequestID di setiap response
+const HeaderRequestID = "X-Request-ID"
+
 type Meta struct {
 	Page       int `json:"page,omitempty"`
 	PerPage    int `json:"per_page,omitempty"`
@@ -36,8 +41,9 @@ func SendSuccess(w http.ResponseWriter, data interface{}, status int) {
 func SendError(w http.ResponseWriter, code, message string, status int) {
 	response := ErrorResponse{
 		Error: ErrorDetail{
-			Code:    code,
-			Message: message,
+			Code:      code,
+			Message:   message,
+			RequestID: w.Header().Get(HeaderRequestID),
 		},
 	}
 	sendJSON(w, status, response)
@@ -46,9 +52,10 @@ func SendError(w http.ResponseWriter, code, message string, status int) {
 func SendErrorWithDetails(w http.ResponseWriter, code, message string, details interface{}, status int) {
 	response := ErrorResponse{
 		Error: ErrorDetail{
-			Code:    code,
-			Message: message,
-			Details: details,
+			Code:      code,
+			Message:   message,
+			Details:   details,
+			RequestID: w.Header().Get(HeaderRequestID),
 		},
 	}
 	sendJSON(w, status, response)